@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestAnalyzeDirectoryUsesCountedContent(t *testing.T) {
+	page := "<div class=\"intro\">Hello world, this is a page</div>\n<p>Second line</p>\n"
+	root := writeDir(t, map[string]string{"page.html": page})
+
+	out, err := runCommand(t, "count", "--analyze", "--strip-html", "--format", "json", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		TotalTokens int `json:"total_tokens"`
+		Files       []struct {
+			TotalTokens int `json:"total_tokens"`
+			TotalChars  int `json:"total_chars"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(report.Files) != 1 {
+		t.Fatalf("got %d files, want 1", len(report.Files))
+	}
+	// The markup was stripped before counting, so the analysis must not see it either
+	if file := report.Files[0]; file.TotalChars >= len(page) || file.TotalTokens != report.TotalTokens {
+		t.Errorf("analyzed %d chars and %d tokens, want the %d tokens of the stripped text (under %d chars)", file.TotalChars, file.TotalTokens, report.TotalTokens, len(page))
+	}
+}
+
+func TestAnalyzeJSONSchema(t *testing.T) {
+	path := writeFile(t, "launch.txt", "Launch day \U0001F680 is here.\nApprove\u200b the refund.\n")
+
+	out, err := runCommand(t, "count", "--analyze", "--format", "json", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report map[string]any
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+
+	// Each key must be present with the JSON type it is documented with
+	checkKeys := func(object map[string]any, where string, keys map[string]string) {
+		t.Helper()
+		for key, kind := range keys {
+			value, ok := object[key]
+			if !ok {
+				t.Errorf("%s: missing %q", where, key)
+				continue
+			}
+			var got string
+			switch value.(type) {
+			case string:
+				got = "string"
+			case float64:
+				got = "number"
+			case []any:
+				got = "array"
+			case map[string]any:
+				got = "object"
+			default:
+				got = fmt.Sprintf("%T", value)
+			}
+			if got != kind {
+				t.Errorf("%s: %q is a %s, want a %s", where, key, got, kind)
+			}
+		}
+	}
+	checkKeys(report, "analysis", map[string]string{
+		"file": "string", "total_tokens": "number", "total_lines": "number", "total_chars": "number",
+		"efficiency_score": "number", "potential_savings": "number", "waste_tokens": "number",
+		"recommendations": "array", "quick_wins": "array", "percentiles": "object", "llm_safety": "object",
+	})
+	checkKeys(report["percentiles"].(map[string]any), "percentiles", map[string]string{
+		"min": "number", "median": "number", "max": "number", "mean": "number",
+	})
+	safety, _ := report["llm_safety"].(map[string]any)
+	if safety == nil {
+		t.Fatal("llm_safety is not an object")
+	}
+	checkKeys(safety, "llm_safety", map[string]string{
+		"emoji_issues": "array", "invisible_char_issues": "array", "bidi_control_issues": "array",
+		"secret_issues": "array", "total_issues": "number", "reliability_score": "number",
+	})
+
+	if report["file"] != path || report["total_lines"] != float64(3) {
+		t.Errorf("file = %v, total_lines = %v, want %s and 3", report["file"], report["total_lines"], path)
+	}
+	invisible, _ := safety["invisible_char_issues"].([]any)
+	if len(invisible) != 1 {
+		t.Fatalf("invisible_char_issues = %v, want the zero-width space", invisible)
+	}
+	issue := invisible[0].(map[string]any)
+	if issue["char_type"] != "zwsp" || issue["line_number"] != float64(2) {
+		t.Errorf("invisible issue = %v, want a zwsp on line 2", issue)
+	}
+	if emoji, _ := safety["emoji_issues"].([]any); len(emoji) != 1 {
+		t.Errorf("emoji_issues = %v, want the rocket", emoji)
+	}
+}
+
+func TestAnalyzeFix(t *testing.T) {
+	content := "Approve\u200b the refund.\n\n\n\nThanks\n"
+	want := "Approve the refund.\n\nThanks\n"
+
+	path := writeFile(t, "reply.txt", content)
+	out, err := runCommand(t, "count", "--analyze", "--fix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != want {
+		t.Errorf("--fix printed %q, want %q", out, want)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("--fix without --write changed the file to %q", data)
+	}
+
+	if _, err := runCommand(t, "count", "--analyze", "--fix", "--write", path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("--fix --write left %q, want %q", data, want)
+	}
+}
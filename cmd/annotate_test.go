@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAnnotateManifest(t *testing.T) {
+	dir := writeDir(t, map[string]string{
+		"manifest.yaml": "# Prompts used by the support bot\n" +
+			"prompts:\n" +
+			"  - path: prompts/greeting.txt\n" +
+			"    owner: support\n" +
+			"  - path: prompts/escalation.txt\n" +
+			"    tokens: 1\n",
+		"prompts/greeting.txt":   "Hello, how can I help you today?",
+		"prompts/escalation.txt": "Escalate to a human agent when the customer asks for a refund.",
+	})
+	manifestPath := filepath.Join(dir, "manifest.yaml")
+
+	if _, err := runCommand(t, "count", "--annotate", manifestPath); err != nil {
+		t.Fatalf("count --annotate: %v", err)
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest struct {
+		Prompts []struct {
+			Path      string    `yaml:"path"`
+			Owner     string    `yaml:"owner"`
+			Tokens    int       `yaml:"tokens"`
+			Cost      float64   `yaml:"cost"`
+			CountedAt time.Time `yaml:"counted_at"`
+		} `yaml:"prompts"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+
+	if len(manifest.Prompts) != 2 {
+		t.Fatalf("entries = %d, want 2\n%s", len(manifest.Prompts), data)
+	}
+	for _, entry := range manifest.Prompts {
+		if entry.Tokens <= 1 || entry.Cost <= 0 || time.Since(entry.CountedAt) > time.Hour {
+			t.Errorf("%s = %d tokens, cost %v, counted at %v; want fresh annotations", entry.Path, entry.Tokens, entry.Cost, entry.CountedAt)
+		}
+	}
+	if manifest.Prompts[1].Tokens <= manifest.Prompts[0].Tokens {
+		t.Errorf("escalation.txt has %d tokens, want more than greeting.txt's %d", manifest.Prompts[1].Tokens, manifest.Prompts[0].Tokens)
+	}
+	// Fields and comments other than the annotations survive
+	if manifest.Prompts[0].Owner != "support" || !strings.Contains(string(data), "# Prompts used by the support bot") {
+		t.Errorf("manifest lost existing content:\n%s", data)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/iota-uz/cc-token/internal/cache"
+	"github.com/iota-uz/cc-token/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -10,22 +11,42 @@ var cacheCmd = &cobra.Command{
 	Short: "Manage token count cache",
 	Long: `Manage the local cache of token counts.
 
-The cache is stored in ~/.cc-token/cache.json and helps avoid redundant API calls
-by storing previously counted token values along with file hashes and modification times.`,
+The cache is stored in ~/.cc-token/cache.json (override with --cache-dir or CC_TOKEN_CACHE_DIR)
+and helps avoid redundant API calls by storing previously counted token values along with file
+hashes and modification times.`,
 }
 
 var clearCacheCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear the token count cache",
-	Long:  `Remove all cached token counts from ~/.cc-token/cache.json`,
+	Long:  `Remove all cached token counts from the cache directory (default: ~/.cc-token/cache.json)`,
 	Example: `  # Clear the cache
   cc-token cache clear`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cache.Clear()
+		return cache.Clear(cfg.CacheDir)
+	},
+}
+
+var statsCacheCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show token count cache statistics",
+	Long:  `Show the number of cached entries, total cached tokens, oldest and newest entry timestamps, and the on-disk size of the cache file.`,
+	Example: `  # Show cache statistics
+  cc-token cache stats
+
+  # Machine-readable statistics
+  cc-token cache stats --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := cache.Load(cfg.CacheDir)
+		if err != nil {
+			return err
+		}
+		return output.FormatCacheStats(c.Stats(), cfg)
 	},
 }
 
 func init() {
 	cacheCmd.AddCommand(clearCacheCmd)
+	cacheCmd.AddCommand(statsCacheCmd)
 	rootCmd.AddCommand(cacheCmd)
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCacheStatsMissingCache(t *testing.T) {
+	dir := t.TempDir()
+
+	out, err := execute(t, []string{"cache", "stats", "--cache-dir", dir, "--format", "json"})
+	if err != nil {
+		t.Fatalf("cache stats: %v", err)
+	}
+	var stats map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	for _, key := range []string{"entries", "total_tokens", "size_bytes"} {
+		if stats[key] != float64(0) {
+			t.Errorf("%s = %v, want 0", key, stats[key])
+		}
+	}
+	if stats["oldest"] != nil || stats["newest"] != nil {
+		t.Errorf("oldest/newest = %v/%v, want null", stats["oldest"], stats["newest"])
+	}
+
+	out, err = execute(t, []string{"cache", "stats", "--cache-dir", dir})
+	if err != nil {
+		t.Fatalf("cache stats: %v", err)
+	}
+	if !strings.Contains(out, "Entries: 0") || !strings.Contains(out, "Oldest entry: -") {
+		t.Errorf("text output:\n%s", out)
+	}
+}
+
+func TestCacheStatsAfterCount(t *testing.T) {
+	srv := newCountServer(t, 7)
+	dir := t.TempDir()
+	path := writeFile(t, "a.txt", "hello")
+
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	if _, err := execute(t, []string{"count", "--api-base-url", srv.URL, "--cache-dir", dir, path}); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	out, err := execute(t, []string{"cache", "stats", "--cache-dir", dir, "--format", "json"})
+	if err != nil {
+		t.Fatalf("cache stats: %v", err)
+	}
+	var stats struct {
+		Entries     int    `json:"entries"`
+		TotalTokens int    `json:"total_tokens"`
+		Oldest      string `json:"oldest"`
+	}
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if stats.Entries != 1 || stats.TotalTokens != 7 || stats.Oldest == "" {
+		t.Errorf("stats = %+v, want the one counted file", stats)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestNoColorDisablesANSIEscapes(t *testing.T) {
+	// Pretend stdout is a terminal, so only the settings under test turn colors off
+	savedTerminal, savedOutput, savedNoColor := stdoutIsTerminal, color.Output, color.NoColor
+	stdoutIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdoutIsTerminal, color.Output, color.NoColor = savedTerminal, savedOutput, savedNoColor })
+
+	path := writeFile(t, "prompt.md", "# Title\n\nThe quick brown fox jumps over the lazy dog.\n"+
+		strings.Repeat("Repeat this sentence to give the analysis something to report. ", 5)+"\n")
+	older := writeFile(t, "older.md", "# Title\n")
+
+	commands := [][]string{
+		{"count", "--analyze", path},
+		{"count", "--per-line", path},
+		{"diff", older, path},
+		{"visualize", "basic", "--yes", path},
+	}
+	tests := []struct {
+		name     string
+		noColor  string
+		flags    []string
+		wantANSI bool
+	}{
+		{"terminal", "", nil, true},
+		{"NO_COLOR", "1", nil, false},
+		{"--no-color", "", []string{"--no-color"}, false},
+		{"--plain", "", []string{"--plain"}, false},
+	}
+	for _, tt := range tests {
+		for _, args := range commands {
+			t.Run(tt.name+"/"+strings.Join(args[:len(args)-1], " "), func(t *testing.T) {
+				t.Setenv("NO_COLOR", tt.noColor)
+				// fatih/color prints to its own stdout handle, so collect what it writes too
+				var colored bytes.Buffer
+				color.Output = &colored
+
+				stdout, err := runCommand(t, append(append([]string{}, args...), tt.flags...)...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.Contains(stdout+colored.String(), "\x1b["); got != tt.wantANSI {
+					t.Errorf("ANSI escapes present = %v, want %v", got, tt.wantANSI)
+				}
+			})
+		}
+	}
+}
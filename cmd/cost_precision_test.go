@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCostPrecision(t *testing.T) {
+	// About 12k tokens, roughly $0.037 at Sonnet prices
+	path := writeFile(t, "prompt.txt", strings.Repeat("word ", 12345))
+
+	out, err := runCommand(t, "count", "--cost-precision", "2", path)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if !regexp.MustCompile(`Estimated cost: \$0\.0\d\n`).MatchString(out) {
+		t.Errorf("cost not shown with 2 decimal places:\n%s", out)
+	}
+
+	// JSON keeps full precision regardless of the flag
+	out, err = runCommand(t, "count", "--cost-precision", "2", "--format", "json", path)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	var results []struct {
+		EstimatedCost float64 `json:"estimated_cost"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	if cost := results[0].EstimatedCost; cost*100 == float64(int(cost*100)) {
+		t.Errorf("JSON cost %v was rounded to cents", cost)
+	}
+}
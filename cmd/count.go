@@ -1,12 +1,24 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/manifest"
 	"github.com/iota-uz/cc-token/internal/output"
+	"github.com/iota-uz/cc-token/internal/pricing"
 	"github.com/iota-uz/cc-token/internal/processor"
+	"github.com/iota-uz/cc-token/internal/tui"
 	"github.com/spf13/cobra"
 )
 
@@ -30,22 +42,59 @@ configured filters. Results can be displayed in tree format or JSON.`,
   cc-token count --model haiku file.txt
 
   # Output JSON format
-  cc-token count --json . > tokens.json
+  cc-token count --format json . > tokens.json
 
   # Read from stdin
   cat file.txt | cc-token count -
 
+  # Count NUL-separated documents from stdin separately
+  printf 'first document\0second document' | cc-token count --stdin-delimiter '\0' -
+
   # Process multiple paths
   cc-token count file1.txt file2.txt dir1/
 
   # Analyze token optimization opportunities
-  cc-token count --analyze document.txt`,
-	Args: cobra.MinimumNArgs(1),
+  cc-token count --analyze document.txt
+
+  # Most expensive lines across a whole directory
+  cc-token count --analyze prompts/
+
+  # Per-role subtotals for a conversation file ({"system": ..., "messages": [...]})
+  cc-token count --by-role chat.json
+
+  # Include a system prompt in every count and see its overhead
+  cc-token count --system prompts/system.txt input.txt
+
+  # Write current token counts into a YAML manifest of prompt files
+  cc-token count --annotate prompts.yaml
+
+  # Browse a directory interactively
+  cc-token count --tui .`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// --print-config exits before any path is counted
+		if cfg.PrintConfig {
+			return nil
+		}
+		// The manifest or file list supplies the paths in --annotate and --files-from mode
+		if cfg.Annotate != "" || cfg.FilesFrom != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		// --text supplies the content itself; paths may be counted alongside it
+		if len(cfg.Texts) > 0 {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Handle --analyze flag (files only)
+		// Handle --annotate flag (manifest of prompt files)
+		if cfg.Annotate != "" {
+			return annotateManifest(cfg.Annotate)
+		}
+
+		// Handle --analyze flag (a single file or directory)
 		if cfg.Analyze {
 			if len(args) != 1 {
-				return fmt.Errorf("--analyze flag requires exactly one file argument")
+				return fmt.Errorf("--analyze flag requires exactly one file or directory argument")
 			}
 
 			path := args[0]
@@ -55,13 +104,12 @@ configured filters. Results can be displayed in tree format or JSON.`,
 				return fmt.Errorf("--analyze flag does not support stdin input")
 			}
 
-			// Check if it's a file (not directory)
 			info, err := os.Stat(path)
 			if err != nil {
 				return fmt.Errorf("failed to access %s: %w", path, err)
 			}
 			if info.IsDir() {
-				return fmt.Errorf("--analyze flag only works with individual files, not directories")
+				return analyzeDirectory(cmd, path)
 			}
 
 			// Read file content
@@ -71,41 +119,531 @@ configured filters. Results can be displayed in tree format or JSON.`,
 			}
 
 			// Get accurate token count from API
-			tokens, err := apiClient.CountTokens(string(content), cfg.Model)
+			tokens, err := counter.CountTokens(string(content), cfg.Model)
 			if err != nil {
 				return fmt.Errorf("failed to count tokens: %w", err)
 			}
 
 			// Perform analysis
-			analysis, err := analyzer.AnalyzeFile(string(content), tokens, apiClient)
+			analysis, err := analyzer.AnalyzeFile(string(content), tokens, counter, analyzerOptions())
 			if err != nil {
 				return fmt.Errorf("failed to analyze file: %w", err)
 			}
 
+			// The system prompt is analyzed separately; report its size with the client-side tokenizer
+			if cfg.SystemPrompt != "" {
+				systemTokens, err := counter.ExtractTokensClientSide(cfg.SystemPrompt)
+				if err != nil {
+					return fmt.Errorf("failed to tokenize system prompt: %w", err)
+				}
+				analysis.SystemTokens = len(systemTokens)
+			}
+
+			if cfg.Fix {
+				return fixFile(path, string(content), analysis)
+			}
+
 			// Format and output analysis
-			formatter := output.NewAnalysisFormatter(!cfg.Plain)
-			return formatter.FormatAnalysis(analysis, path, cfg)
+			if cfg.Format == config.FormatJSON {
+				err = output.NewAnalysisJSONFormatter().FormatAnalysis(analysis, path, cfg)
+			} else if cfg.Format == config.FormatSARIF {
+				err = output.NewSARIFFormatter().FormatAnalysis(analysis, path, cfg)
+			} else {
+				err = output.NewAnalysisFormatter(!cfg.Plain).FormatAnalysis(analysis, path, cfg)
+			}
+			if err != nil {
+				return err
+			}
+
+			files := []*analyzer.FileAnalysis{{Path: path, Analysis: analysis}}
+			if err := checkStrictAnalysis(cmd, files, analysis.Recommendations); err != nil {
+				return err
+			}
+			return checkSafetyGates(cmd, files)
+		}
+
+		// Handle --per-line flag (a single file or stdin)
+		if cfg.PerLine {
+			if len(args) != 1 {
+				return fmt.Errorf("--per-line flag requires exactly one file argument")
+			}
+			return countPerLine(args[0])
+		}
+
+		// Handle --count-runs flag (single file benchmarking)
+		if cfg.CountRuns > 1 {
+			if len(args) != 1 || args[0] == "-" {
+				return fmt.Errorf("--count-runs flag requires exactly one file argument")
+			}
+
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+
+			// Repeat the API call directly, bypassing the cache, so every run is measured
+			tokens := make([]int, 0, cfg.CountRuns)
+			latencies := make([]time.Duration, 0, cfg.CountRuns)
+			for i := 0; i < cfg.CountRuns; i++ {
+				start := time.Now()
+				count, err := counter.CountTokensWithSystem(string(content), cfg.SystemPrompt, cfg.Model)
+				if err != nil {
+					return fmt.Errorf("run %d failed: %w", i+1, err)
+				}
+				latencies = append(latencies, time.Since(start))
+				tokens = append(tokens, count)
+			}
+
+			return output.FormatRunStats(args[0], output.ComputeRunStats(tokens, latencies), cfg)
+		}
+
+		// Handle --by-role flag (conversation files only)
+		if cfg.ByRole {
+			if len(args) != 1 || args[0] == "-" {
+				return fmt.Errorf("--by-role flag requires exactly one conversation file argument")
+			}
+
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+
+			var conv api.Conversation
+			if err := json.Unmarshal(content, &conv); err != nil {
+				return fmt.Errorf("failed to parse conversation file: %w", err)
+			}
+
+			count, err := counter.CountConversationByRole(&conv, cfg.Model)
+			if err != nil {
+				return fmt.Errorf("failed to count conversation: %w", err)
+			}
+
+			return output.NewConversationFormatter(pricingService).FormatConversation(args[0], count, cfg)
 		}
 
 		// Normal count mode
 		// Create processor
-		proc := processor.New(apiClient, cacheInst, cfg)
+		proc := processor.New(counter, cacheInst, cfg)
+		proc.SetPricer(pricingService)
+		if showProgress() {
+			proc.SetProgressWriter(os.Stderr)
+		}
+
+		// On Ctrl+C, stop starting new work and report what was counted so far.
+		// A second Ctrl+C terminates immediately.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+		proc.SetContext(ctx)
 
 		// Process each path
 		var results []*processor.Result
+		if cfg.FilesFrom != "" {
+			paths, err := processor.LoadFileList(cfg.FilesFrom)
+			if err != nil {
+				return err
+			}
+			results, err = proc.ProcessFileList(paths)
+			if err != nil {
+				if errors.Is(err, processor.ErrBudgetExceeded) {
+					cmd.SilenceUsage = true
+				}
+				return fmt.Errorf("failed to process %s: %w", cfg.FilesFrom, err)
+			}
+		}
+		if len(cfg.Texts) > 0 {
+			texts, err := proc.ProcessTexts(cfg.Texts)
+			if err != nil {
+				return fmt.Errorf("failed to process --text: %w", err)
+			}
+			results = append(results, texts...)
+		}
 		for _, path := range args {
+			if proc.Interrupted() {
+				break
+			}
+
+			// Split stdin into separately counted documents
+			if path == "-" && cfg.StdinDelimiter != "" {
+				documents, err := proc.ProcessStdinDocuments(cfg.StdinDelimiter)
+				if err != nil {
+					return fmt.Errorf("failed to process stdin: %w", err)
+				}
+				results = append(results, documents...)
+				continue
+			}
+
 			result, err := proc.ProcessPath(path)
 			if err != nil {
+				if errors.Is(err, processor.ErrBudgetExceeded) {
+					cmd.SilenceUsage = true
+				}
 				return fmt.Errorf("failed to process %s: %w", path, err)
 			}
 			results = append(results, result)
 		}
 
+		// Browse results interactively
+		if cfg.TUI {
+			return tui.Run(results, cfg, pricingService)
+		}
+
 		// Output results
-		return output.OutputResults(results, cfg, pricingService)
+		if err := output.OutputResults(results, cfg, pricingService); err != nil {
+			return err
+		}
+
+		if proc.Interrupted() {
+			return interrupted(cmd)
+		}
+
+		if err := checkFailedResults(cmd, results); err != nil {
+			return err
+		}
+		return checkStrictResults(cmd, results)
 	},
 }
 
+// countPerLine prints path (or stdin for "-") with each line's token count from the
+// client-side tokenizer
+func countPerLine(path string) error {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+		path = cfg.StdinName
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	tokens, err := counter.ExtractTokensClientSide(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to tokenize %s: %w", path, err)
+	}
+	return output.NewPerLineFormatter(!cfg.Plain).Format(path, analyzer.TokensPerLine(string(content), tokens))
+}
+
+// showProgress reports whether directory scans print progress to stderr: always with
+// --progress, never with --no-progress, and by default only for tree output (without
+// --cost-only) when stderr is a terminal
+func showProgress() bool {
+	if cfg.Progress || cfg.NoProgress {
+		return cfg.Progress
+	}
+	if cfg.Format != config.FormatTree || cfg.CostOnly || cfg.Quiet {
+		return false
+	}
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// analyzeDirectory analyzes every file the count would include (respecting .gitignore and
+// filters) and reports per-file summaries with the most expensive lines across all files
+func analyzeDirectory(cmd *cobra.Command, path string) error {
+	if cfg.Fix && !cfg.WriteFixes {
+		return fmt.Errorf("--fix on a directory requires --write")
+	}
+
+	proc := processor.New(counter, cacheInst, cfg)
+	proc.SetPricer(pricingService)
+	proc.SetKeepContent(true)
+	if showProgress() {
+		proc.SetProgressWriter(os.Stderr)
+	}
+	result, err := proc.ProcessPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to process %s: %w", path, err)
+	}
+
+	var files []*analyzer.FileAnalysis
+	for _, file := range result.Children {
+		if file.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s: ERROR - %v\n", file.Path, file.Error)
+			continue
+		}
+		if file.SkippedBinary || file.Image {
+			continue
+		}
+
+		// Analyze the text that was counted, so line costs match the token total. --fix
+		// rewrites the file as stored, so it analyzes the raw file instead.
+		content := file.Content
+		if cfg.Fix {
+			raw, err := os.ReadFile(file.Path)
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+			content = string(raw)
+		}
+
+		analysis, err := analyzer.AnalyzeFile(content, file.Tokens, counter, analyzerOptions())
+		if err != nil {
+			return fmt.Errorf("failed to analyze %s: %w", file.Path, err)
+		}
+		files = append(files, &analyzer.FileAnalysis{Path: file.Path, Analysis: analysis})
+
+		if cfg.Fix {
+			if err := fixFile(file.Path, content, analysis); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no files to analyze in %s", path)
+	}
+	if cfg.Fix {
+		return nil
+	}
+
+	dir := analyzer.NewDirectoryAnalysis(files)
+	if cfg.Format == config.FormatJSON {
+		err = output.NewAnalysisJSONFormatter().FormatDirectoryAnalysis(dir, path, cfg)
+	} else if cfg.Format == config.FormatSARIF {
+		err = output.NewSARIFFormatter().FormatDirectoryAnalysis(dir, path, cfg)
+	} else {
+		err = output.NewAnalysisFormatter(!cfg.Plain).FormatDirectoryAnalysis(dir, path, cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := checkStrictAnalysis(cmd, files, dir.Recommendations()); err != nil {
+		return err
+	}
+	return checkSafetyGates(cmd, files)
+}
+
+// analyzerOptions selects the analysis detectors from the detector flags
+func analyzerOptions() analyzer.Options {
+	opts := analyzer.NewOptions(cfg.EnableDetectors, cfg.DisableDetectors, cfg.OnlySecurity)
+	opts.Thresholds = analyzer.Thresholds{
+		MinRepetitions: cfg.MinRepetitions,
+		LongLineLength: cfg.LongLineLength,
+		MinURLLength:   cfg.MinURLLength,
+	}
+	return opts
+}
+
+// annotateManifest counts every file listed in a YAML manifest and writes tokens, cost and
+// counted_at back into each entry
+func annotateManifest(path string) error {
+	m, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	entries := m.Entries()
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries with a %q field found in %s", "path", path)
+	}
+
+	proc := processor.New(counter, cacheInst, cfg)
+	results := make([]*processor.Result, 0, len(entries))
+	for _, entry := range entries {
+		result, err := proc.ProcessPath(entry.ResolvedPath)
+		if err != nil {
+			return fmt.Errorf("failed to process %s: %w", entry.Path, err)
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to count %s: %w", entry.Path, result.Error)
+		}
+
+		entry.Annotate(result.Tokens, pricingService.CalculateCost(result.Tokens, cfg.Model), time.Now())
+		result.Path = entry.Path
+		results = append(results, result)
+	}
+
+	if err := m.Save(); err != nil {
+		return err
+	}
+
+	if err := output.OutputResults(results, cfg, pricingService); err != nil {
+		return err
+	}
+	if cfg.Format == config.FormatTree && !cfg.CostOnly && !cfg.Quiet {
+		fmt.Printf("Annotated %d entries in %s\n", len(entries), path)
+	}
+	return nil
+}
+
+// interrupted saves the cache after an interrupted count and fails the command. The post-run
+// hook that normally saves the cache doesn't run when the command returns an error.
+func interrupted(cmd *cobra.Command) error {
+	fmt.Fprintln(os.Stderr, "(interrupted) results above are partial")
+	if cacheInst != nil {
+		if err := cacheInst.Save(); err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save cache: %v\n", err)
+		}
+	}
+
+	cmd.SilenceUsage = true
+	return fmt.Errorf("interrupted")
+}
+
+// checkFailedResults fails the command when any file could not be counted. Each failure has
+// already been reported next to its path in the output.
+func checkFailedResults(cmd *cobra.Command, results []*processor.Result) error {
+	failed := 0
+	unauthorized := false
+	var walk func(result *processor.Result)
+	walk = func(result *processor.Result) {
+		if result.Error != nil {
+			failed++
+			unauthorized = unauthorized || errors.Is(result.Error, api.ErrUnauthorized)
+		}
+		for _, child := range result.Children {
+			walk(child)
+		}
+	}
+	for _, result := range results {
+		walk(result)
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		if unauthorized {
+			return fmt.Errorf("%d file(s) failed to count: %w", failed, api.ErrUnauthorized)
+		}
+		return fmt.Errorf("%d file(s) failed to count", failed)
+	}
+	return nil
+}
+
+// checkStrictResults fails a count under --strict when any file contains a secret or the
+// counted tokens overflow the model's context window
+func checkStrictResults(cmd *cobra.Command, results []*processor.Result) error {
+	if !cfg.Strict {
+		return nil
+	}
+
+	var secretPaths []string
+	var walk func(result *processor.Result)
+	walk = func(result *processor.Result) {
+		if result.Secrets > 0 {
+			secretPaths = append(secretPaths, result.Path)
+		}
+		for _, child := range result.Children {
+			walk(child)
+		}
+	}
+	tokens := 0
+	for _, result := range results {
+		walk(result)
+		tokens += result.Tokens
+	}
+
+	return strictFailure(cmd, secretPaths, 0, tokens)
+}
+
+// checkStrictAnalysis fails the command under --strict when an analyzed file contains a
+// secret, there are priority-1 recommendations or the files overflow the model's context window
+func checkStrictAnalysis(cmd *cobra.Command, files []*analyzer.FileAnalysis, recommendations []*analyzer.Recommendation) error {
+	if !cfg.Strict {
+		return nil
+	}
+
+	var secretPaths []string
+	tokens := 0
+	for _, file := range files {
+		if safety := file.Analysis.LLMSafetyAnalysis; safety != nil && len(safety.SecretIssues) > 0 {
+			secretPaths = append(secretPaths, file.Path)
+		}
+		tokens += file.Analysis.TotalTokens
+	}
+
+	critical := 0
+	for _, rec := range recommendations {
+		if rec.Priority == 1 {
+			critical++
+		}
+	}
+
+	return strictFailure(cmd, secretPaths, critical, tokens)
+}
+
+// strictFailure reports every --strict gate that failed, or nil when all passed
+func strictFailure(cmd *cobra.Command, secretPaths []string, critical, tokens int) error {
+	var failures []string
+	if len(secretPaths) > 0 {
+		failures = append(failures, fmt.Sprintf("secrets found in %s", strings.Join(secretPaths, ", ")))
+	}
+	if critical > 0 {
+		failures = append(failures, fmt.Sprintf("%d priority-1 issue(s) found", critical))
+	}
+	if tokens > pricing.ContextWindow {
+		failures = append(failures, fmt.Sprintf("%d tokens overflow the %d-token context window of %s", tokens, pricing.ContextWindow, cfg.Model))
+	}
+
+	if len(failures) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("--strict: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// fixFile applies the safe --fix rewrites to a file and reports what changed. The fixed
+// content replaces the file under --write and is printed to stdout otherwise, with the
+// report on stderr.
+func fixFile(path, content string, analysis *analyzer.Analysis) error {
+	fix := analyzer.Fix(content)
+	if !cfg.WriteFixes {
+		if _, err := os.Stdout.WriteString(fix.Content); err != nil {
+			return err
+		}
+		output.FormatFixSummary(os.Stderr, path, fix, analysis, false)
+		return nil
+	}
+
+	if fix.Changed() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to access %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, []byte(fix.Content), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	output.FormatFixSummary(os.Stdout, path, fix, analysis, true)
+	return nil
+}
+
+// checkSafetyGates fails the command when a file's reliability score is below --fail-under or
+// any --fail-on-detector detector reported an issue
+func checkSafetyGates(cmd *cobra.Command, files []*analyzer.FileAnalysis) error {
+	if cfg.FailUnder == 0 && len(cfg.FailOnDetectors) == 0 {
+		return nil
+	}
+
+	var failures []string
+	for _, file := range files {
+		safety := file.Analysis.LLMSafetyAnalysis
+		if safety == nil {
+			continue
+		}
+		if safety.ReliabilityScore < cfg.FailUnder {
+			failures = append(failures, fmt.Sprintf("%s: reliability score %d is below %d", file.Path, safety.ReliabilityScore, cfg.FailUnder))
+		}
+		for _, detector := range cfg.FailOnDetectors {
+			if count := safety.IssueCount(detector); count > 0 {
+				failures = append(failures, fmt.Sprintf("%s: %d %s issue(s)", file.Path, count, detector))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("analysis gate failed:\n  %s", strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(countCmd)
 }
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCountRuns(t *testing.T) {
+	srv := newCountServer(t, 42)
+	path := writeFile(t, "prompt.txt", "Explain the change")
+
+	out, err := runCommandAPI(t, srv.URL, "count", "--count-runs", "4", "--format", "json", path)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	requests := srv.received()
+	if len(requests) != 4 {
+		t.Fatalf("API requests = %d, want one per run", len(requests))
+	}
+	for _, req := range requests {
+		if len(req.Messages) != 1 || req.Messages[0].Content.String() != "Explain the change" {
+			t.Errorf("request = %+v, want the file content", req)
+		}
+	}
+
+	var stats struct {
+		Runs          int     `json:"runs"`
+		MinTokens     int     `json:"min_tokens"`
+		MaxTokens     int     `json:"max_tokens"`
+		MeanTokens    float64 `json:"mean_tokens"`
+		MeanLatencyMS float64 `json:"mean_latency_ms"`
+		MaxLatencyMS  float64 `json:"max_latency_ms"`
+	}
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if stats.Runs != 4 || stats.MinTokens != 42 || stats.MaxTokens != 42 || stats.MeanTokens != 42 {
+		t.Errorf("stats = %+v, want 4 runs of 42 tokens", stats)
+	}
+	if stats.MeanLatencyMS <= 0 || stats.MaxLatencyMS < stats.MeanLatencyMS {
+		t.Errorf("latency mean %.3fms, max %.3fms; want positive with max >= mean", stats.MeanLatencyMS, stats.MaxLatencyMS)
+	}
+
+	out, err = runCommandAPI(t, srv.URL, "count", "--count-runs", "2", path)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if !strings.Contains(out, "2 runs") || !strings.Contains(out, "min 42 | max 42 | mean 42.0") {
+		t.Errorf("text output:\n%s", out)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/output"
+	"github.com/iota-uz/cc-token/internal/processor"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare the token counts of two files",
+	Long: `Count two versions of a file and show how many tokens (and how much cost) the change adds
+or saves, with the percentage change.
+
+Both files are counted the same way as the count command, including the cache, --offline,
+--system and preprocessing flags. With --analyze, added and removed lines are listed with the
+tokens each one gained or saved (client-side estimate).`,
+	Example: `  # Tokens saved by an edited prompt
+  cc-token diff prompt.old.md prompt.md
+
+  # Which lines gained or lost tokens
+  cc-token diff --analyze prompt.old.md prompt.md
+
+  # Machine-readable delta
+  cc-token diff --format json prompt.old.md prompt.md`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Format != config.FormatTree && cfg.Format != config.FormatJSON {
+			return fmt.Errorf("diff supports --format tree or json, not %s", cfg.Format)
+		}
+
+		proc := processor.New(counter, cacheInst, cfg)
+		proc.SetPricer(pricingService)
+
+		results := make([]*processor.Result, 0, len(args))
+		for _, path := range args {
+			if path == "-" {
+				return fmt.Errorf("diff does not support stdin input")
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("failed to access %s: %w", path, err)
+			}
+			if info.IsDir() {
+				return fmt.Errorf("diff compares two files, %s is a directory", path)
+			}
+
+			result, err := proc.ProcessPath(path)
+			if err != nil {
+				return fmt.Errorf("failed to process %s: %w", path, err)
+			}
+			if result.Error != nil {
+				return fmt.Errorf("failed to count %s: %w", path, result.Error)
+			}
+			results = append(results, result)
+		}
+
+		var lines []*analyzer.LineDelta
+		if cfg.Analyze {
+			oldContent, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+			newContent, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+
+			lines, err = analyzer.DiffLines(string(oldContent), string(newContent), counter)
+			if err != nil {
+				return fmt.Errorf("failed to diff lines: %w", err)
+			}
+		}
+
+		return output.NewDiffFormatter(pricingService, !cfg.Plain).FormatDiff(results[0], results[1], lines, cfg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
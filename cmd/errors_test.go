@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+func TestUnauthorizedExitCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	path := writeFile(t, "notes.txt", "Hello world\n")
+
+	_, err := runCommandAPI(t, srv.URL, "count", path)
+	if !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("err = %v, want api.ErrUnauthorized", err)
+	}
+	if code := ExitCode(err); code != exitUnauthorized {
+		t.Errorf("exit code = %d, want %d", code, exitUnauthorized)
+	}
+}
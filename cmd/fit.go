@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var fitCmd = &cobra.Command{
+	Use:   "fit --budget <tokens> <file>",
+	Short: "Find where to truncate a file to fit a token budget",
+	Long: `Tokenize a file with the client-side tokenizer and report where the cumulative token count
+reaches --budget: the line and byte offset of the last token that fits, how many complete lines
+fit, and how many tokens and lines are left over.
+
+With --output, the complete lines that fit are written to that file. Counts are approximate
+(the API adds a few tokens of message framing), so leave some headroom in the budget.`,
+	Example: `  # Where does a log stop fitting in 100k tokens?
+  cc-token fit --budget 100000 app.log
+
+  # Write the part that fits
+  cc-token fit --budget 100000 --output app.head.log app.log
+
+  # Machine-readable cut point
+  cc-token fit --budget 8000 --format json prompt.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Budget <= 0 {
+			return fmt.Errorf("fit requires a positive --budget")
+		}
+		if cfg.Format != config.FormatTree && cfg.Format != config.FormatJSON {
+			return fmt.Errorf("fit supports --format tree or json, not %s", cfg.Format)
+		}
+
+		path := args[0]
+		var content []byte
+		var err error
+		if path == "-" {
+			content, err = io.ReadAll(os.Stdin)
+			path = cfg.StdinName
+		} else {
+			content, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		tokens, err := counter.ExtractTokensClientSide(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to tokenize %s: %w", path, err)
+		}
+		fit := analyzer.FitBudget(string(content), tokens, cfg.Budget)
+
+		if cfg.OutputFile != "" {
+			if err := os.WriteFile(cfg.OutputFile, content[:fit.LineOffset], 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", cfg.OutputFile, err)
+			}
+		}
+		return output.FormatFit(path, fit, cfg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fitCmd)
+}
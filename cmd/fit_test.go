@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFitWritesLinesWithinBudget(t *testing.T) {
+	line := "The quick brown fox jumps over the lazy dog.\n"
+	path := writeFile(t, "story.txt", strings.Repeat(line, 10))
+	outputPath := filepath.Join(t.TempDir(), "head.txt")
+
+	// Every line has the same tokens, so a budget of 3.5 lines fits exactly 3
+	perLine, err := runCommand(t, "fit", "--budget", "1000", "--format", "json", writeFile(t, "line.txt", line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var single struct {
+		TotalTokens int `json:"total_tokens"`
+	}
+	if err := json.Unmarshal([]byte(perLine), &single); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, perLine)
+	}
+	budget := single.TotalTokens*3 + single.TotalTokens/2
+
+	stdout, err := runCommand(t, "fit", "--budget", strconv.Itoa(budget), "--format", "json", "--output", outputPath, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fit struct {
+		Fits           bool `json:"fits"`
+		CutLine        int  `json:"cut_line"`
+		FittingLines   int  `json:"fitting_lines"`
+		LineOffset     int  `json:"line_offset"`
+		LeftoverTokens int  `json:"leftover_tokens"`
+		LeftoverLines  int  `json:"leftover_lines"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &fit); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if fit.Fits || fit.CutLine != 4 || fit.FittingLines != 3 || fit.LineOffset != 3*len(line) || fit.LeftoverLines != 7 {
+		t.Errorf("fit = %+v, want the cut on line 4 after 3 complete lines", fit)
+	}
+	if want := single.TotalTokens*10 - budget; fit.LeftoverTokens != want {
+		t.Errorf("leftover_tokens = %d, want %d", fit.LeftoverTokens, want)
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Repeat(line, 3); string(written) != want {
+		t.Errorf("--output wrote %q, want %q", written, want)
+	}
+}
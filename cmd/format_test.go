@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONFlagIsDeprecatedAlias(t *testing.T) {
+	path := writeFile(t, "notes.txt", "Hello world\n")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	out, err := runCommand(t, "count", "--json", path)
+	w.Close()
+	os.Stderr = stderr
+	warning, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []map[string]any
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("--json output is not JSON: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(warning), "--json has been deprecated") {
+		t.Errorf("stderr = %q, want a deprecation warning", warning)
+	}
+
+	if _, err := runCommand(t, "count", "--json", "--format", "csv", path); err == nil || !strings.Contains(err.Error(), "--json conflicts with --format csv") {
+		t.Errorf("--json --format csv: error = %v, want a conflict", err)
+	}
+	if _, err := runCommand(t, "count", "--json", "--format", "json", path); err != nil {
+		t.Errorf("--json --format json: %v", err)
+	}
+}
+
+func TestInvalidFormat(t *testing.T) {
+	path := writeFile(t, "notes.txt", "Hello world\n")
+	for _, format := range []string{"yaml", "JSON", ""} {
+		_, err := runCommand(t, "count", "--format", format, path)
+		if err == nil || !strings.Contains(err.Error(), "invalid format") {
+			t.Errorf("--format %q: error = %v, want an invalid format error", format, err)
+		}
+	}
+}
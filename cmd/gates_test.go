@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalysisGates(t *testing.T) {
+	clean := writeFile(t, "clean.txt", "Summarize the report in three bullet points.\n")
+	// A right-to-left override reorders how the line displays (Trojan Source)
+	trojan := writeFile(t, "trojan.txt", "access = \"user\u202e \u2066// admin\u2069 \u2066\"\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"score above threshold", []string{"count", "--analyze", "--fail-under", "90", clean}, ""},
+		{"score below threshold", []string{"count", "--analyze", "--fail-under", "99", trojan}, "reliability score"},
+		{"detector without issues", []string{"count", "--analyze", "--fail-on-detector", "bidi_control", clean}, ""},
+		{"detector with issues", []string{"count", "--analyze", "--fail-on-detector", "bidi_control", trojan}, "bidi_control issue(s)"},
+		{"gates off", []string{"count", "--analyze", trojan}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCommand(t, tt.args...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if code := ExitCode(err); code != 1 {
+				t.Errorf("exit code = %d, want 1", code)
+			}
+		})
+	}
+}
+
+func TestAnalysisGatesRequireAnalyze(t *testing.T) {
+	path := writeFile(t, "clean.txt", "Hello\n")
+	if _, err := runCommand(t, "count", "--fail-under", "50", path); err == nil || !strings.Contains(err.Error(), "require --analyze") {
+		t.Errorf("error = %v, want --fail-under to require --analyze", err)
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGroupByExtensionJSON(t *testing.T) {
+	dir := writeDir(t, map[string]string{
+		"main.go":        "package main func main() {}",
+		"util.go":        "package main",
+		"docs/README.md": "# Title and some words",
+		"Makefile":       "all: build",
+	})
+
+	out, err := runCommand(t, "count", "--json", "--group-by", "ext", dir)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	var envelope struct {
+		TotalTokens int `json:"total_tokens"`
+		ByExtension map[string]struct {
+			Tokens int     `json:"tokens"`
+			Files  int     `json:"files"`
+			Cost   float64 `json:"cost"`
+		} `json:"by_extension"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+
+	if got := envelope.ByExtension[".go"].Files; got != 2 {
+		t.Errorf(".go files = %d, want 2", got)
+	}
+	if len(envelope.ByExtension) != 3 {
+		t.Errorf("extensions = %v, want .go, .md and no extension", envelope.ByExtension)
+	}
+	tokens, files := 0, 0
+	for ext, totals := range envelope.ByExtension {
+		if totals.Tokens > 0 && totals.Cost <= 0 {
+			t.Errorf("%s cost = %v, want positive", ext, totals.Cost)
+		}
+		tokens += totals.Tokens
+		files += totals.Files
+	}
+	if tokens == 0 || tokens != envelope.TotalTokens {
+		t.Errorf("per-extension tokens sum to %d, want total %d", tokens, envelope.TotalTokens)
+	}
+	if files != 4 {
+		t.Errorf("per-extension files sum to %d, want 4", files)
+	}
+
+	// Without --group-by the breakdown stays out of the output
+	out, err = runCommand(t, "count", "--json", dir)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if strings.Contains(out, "by_extension") {
+		t.Errorf("by_extension present without --group-by:\n%s", out)
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// runCommand runs cc-token with args, offline and without the cache, and returns what it
+// wrote to stdout. Flags are reset to their defaults first, since they are shared globals.
+func runCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	return execute(t, append([]string{args[0], "--offline", "--no-cache"}, args[1:]...))
+}
+
+// runCommandAPI runs cc-token with args against the API at baseURL, without the cache
+func runCommandAPI(t *testing.T, baseURL string, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	return execute(t, append([]string{args[0], "--api-base-url", baseURL, "--no-cache"}, args[1:]...))
+}
+
+// execute runs the root command with args and returns what it wrote to stdout
+func execute(t *testing.T, args []string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+	t.Setenv("CC_TOKEN_CACHE_DIR", t.TempDir())
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+
+	w.Close()
+	os.Stdout = stdout
+	return <-done, err
+}
+
+// countServer is a fake count_tokens endpoint that answers every request with tokens input
+// tokens and records the requests it receives
+type countServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []api.Request
+}
+
+func newCountServer(t *testing.T, tokens int) *countServer {
+	t.Helper()
+	s := &countServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+		fmt.Fprintf(w, `{"input_tokens":%d}`, tokens)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the requests the server has answered so far
+func (s *countServer) received() []api.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]api.Request(nil), s.requests...)
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default value
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace([]string{})
+		} else {
+			_ = flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// writeFile creates a file with content in a new temp directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := t.TempDir() + string(os.PathSeparator) + name
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeDir creates files, keyed by slash-separated relative path, in a new temp directory
+// and returns the directory
+func writeDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
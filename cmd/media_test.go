@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// samplePDF is a minimal one-page PDF
+const samplePDF = "%PDF-1.4\n1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj\n2 0 obj<</Type/Pages/Kids[3 0 R]/Count 1>>endobj\n3 0 obj<</Type/Page/Parent 2 0 R/MediaBox[0 0 200 200]>>endobj\ntrailer<</Root 1 0 R>>\n%%EOF\n"
+
+func TestCountPDFSendsDocumentBlock(t *testing.T) {
+	srv := newCountServer(t, 1500)
+	path := writeFile(t, "report.pdf", samplePDF)
+
+	out, err := runCommandAPI(t, srv.URL, "count", "--format", "json", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := srv.received()
+	if len(requests) != 1 || len(requests[0].Messages) != 1 {
+		t.Fatalf("got requests %+v, want one single-message request", requests)
+	}
+	blocks := requests[0].Messages[0].Content.Blocks
+	if len(blocks) != 1 || blocks[0].Type != "document" || blocks[0].Source == nil {
+		t.Fatalf("content blocks = %+v, want one document block", blocks)
+	}
+	source := blocks[0].Source
+	if source.Type != "base64" || source.MediaType != "application/pdf" {
+		t.Errorf("document source = %s %s, want base64 application/pdf", source.Type, source.MediaType)
+	}
+	if data, err := base64.StdEncoding.DecodeString(source.Data); err != nil || string(data) != samplePDF {
+		t.Errorf("document data doesn't decode to the PDF (err %v)", err)
+	}
+
+	var results []struct {
+		Tokens int `json:"tokens"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 || results[0].Tokens != 1500 {
+		t.Errorf("results = %s (err %v), want the API's 1500 tokens", out, err)
+	}
+}
+
+func TestCountPDFSkippedOffline(t *testing.T) {
+	// The client-side tokenizer can't read PDFs, so offline they are skipped like binary files
+	dir := writeDir(t, map[string]string{"report.pdf": samplePDF, "notes.txt": "Hello"})
+	out, err := runCommand(t, "count", "--format", "json", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []struct {
+		Files         int `json:"files"`
+		SkippedBinary int `json:"skipped_binary"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 {
+		t.Fatalf("invalid JSON (err %v):\n%s", err, out)
+	}
+	if results[0].Files != 1 || results[0].SkippedBinary != 1 {
+		t.Errorf("files = %d, skipped = %d, want notes.txt counted and report.pdf skipped", results[0].Files, results[0].SkippedBinary)
+	}
+}
+
+// writePNG writes a blank PNG of the given size and returns its path
+func writePNG(t *testing.T, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, "chart.png", buf.String())
+}
+
+func TestCountImageSendsImageBlock(t *testing.T) {
+	srv := newCountServer(t, 54)
+	path := writePNG(t, 200, 100)
+
+	out, err := runCommandAPI(t, srv.URL, "count", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := srv.received()
+	if len(requests) != 1 || len(requests[0].Messages) != 1 {
+		t.Fatalf("got requests %+v, want one single-message request", requests)
+	}
+	blocks := requests[0].Messages[0].Content.Blocks
+	if len(blocks) != 1 || blocks[0].Type != "image" || blocks[0].Source == nil || blocks[0].Source.MediaType != api.PNGMediaType {
+		t.Fatalf("content blocks = %+v, want one image/png image block", blocks)
+	}
+	if !strings.Contains(out, "54 tokens (image)") {
+		t.Errorf("output = %q, want the API count labeled as image tokens", out)
+	}
+
+	out, err = runCommandAPI(t, srv.URL, "count", "--format", "json", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []struct {
+		Tokens int  `json:"tokens"`
+		Image  bool `json:"image"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 || results[0].Tokens != 54 || !results[0].Image {
+		t.Errorf("JSON results = %s (err %v), want 54 tokens marked as an image", out, err)
+	}
+}
+
+func TestCountImageOffline(t *testing.T) {
+	path := writePNG(t, 200, 100)
+	out, err := runCommand(t, "count", "--quiet", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprint(api.EstimateImageTokens(200, 100) + api.OfflineMessageOverhead); strings.TrimSpace(out) != want {
+		t.Errorf("offline count = %q, want the %s-token estimate from the image size", out, want)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnknownModelSuggestion(t *testing.T) {
+	path := writeFile(t, "notes.txt", "Hello world\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"typo suggests the closest model", []string{"count", "--model", "claude-sonet-4-5", path}, `unknown model "claude-sonet-4-5"; did you mean "claude-sonnet-4-5"?`},
+		{"alias typo suggests the alias", []string{"count", "--model", "hiaku", path}, `did you mean "haiku"?`},
+		{"no close match points at models", []string{"count", "--model", "gpt-4o", path}, "see cc-token models"},
+		{"strict refuses unknown models", []string{"count", "--strict", "--allow-unknown-model", "--model", "claude-sonet-4-5", path}, "--strict requires a model with known pricing"},
+		{"escape hatch", []string{"count", "--allow-unknown-model", "--model", "claude-sonnet-9", path}, ""},
+		{"known model", []string{"count", "--model", "claude-haiku-4-5", path}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCommand(t, tt.args...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List supported models and their pricing",
+	Long: `List the models cc-token knows prices for, with the alternate names and short aliases
+(sonnet, haiku, opus) each one accepts for --model.
+
+Prices are per 1M input tokens, with the prompt cache write and read rates, converted to
+--currency. Models added or repriced by a pricing file are included.`,
+	Example: `  # Show models and prices
+  cc-token models
+
+  # Prices in euros
+  cc-token models --currency EUR
+
+  # Machine-readable list
+  cc-token models --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Format != config.FormatTree && cfg.Format != config.FormatJSON {
+			return fmt.Errorf("models supports --format tree or json, not %s", cfg.Format)
+		}
+		return output.FormatModels(pricingService.Models(), pricingService, cfg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+)
+
+// listedModel is a model in `models --format json` output
+type listedModel struct {
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases"`
+	Family     string   `json:"family"`
+	InputPrice float64  `json:"input_per_million"`
+	CacheWrite float64  `json:"cache_write_per_million"`
+	CacheRead  float64  `json:"cache_read_per_million"`
+}
+
+func TestModelsListsKnownPrices(t *testing.T) {
+	stdout, err := runCommand(t, "models", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed struct {
+		Currency string        `json:"currency"`
+		Models   []listedModel `json:"models"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if listed.Currency != "USD" {
+		t.Errorf("currency = %q, want USD", listed.Currency)
+	}
+
+	tests := []struct {
+		name                         string
+		alias                        string
+		input, cacheWrite, cacheRead float64
+	}{
+		{"claude-sonnet-4-5", "sonnet", 3.00, 3.75, 0.30},
+		{"claude-haiku-4-5", "haiku", 1.00, 1.25, 0.10},
+		{"claude-opus-4-1", "opus", 15.00, 18.75, 1.50},
+		{"claude-haiku-3-5", "claude-3-5-haiku", 0.80, 1.00, 0.08},
+		{"claude-haiku-3", "claude-3-haiku", 0.25, 0.30, 0.03},
+	}
+	for _, tt := range tests {
+		i := slices.IndexFunc(listed.Models, func(m listedModel) bool { return m.Name == tt.name })
+		if i < 0 {
+			t.Errorf("%s not listed", tt.name)
+			continue
+		}
+		model := listed.Models[i]
+		if model.InputPrice != tt.input {
+			t.Errorf("%s input = %v, want %v", tt.name, model.InputPrice, tt.input)
+		}
+		if math.Abs(model.CacheWrite-tt.cacheWrite) > 1e-9 || math.Abs(model.CacheRead-tt.cacheRead) > 1e-9 {
+			t.Errorf("%s cache write/read = %v/%v, want %v/%v", tt.name, model.CacheWrite, model.CacheRead, tt.cacheWrite, tt.cacheRead)
+		}
+		if !slices.Contains(model.Aliases, tt.alias) {
+			t.Errorf("%s aliases = %v, want %s among them", tt.name, model.Aliases, tt.alias)
+		}
+	}
+
+	// Sorted by family: every haiku before every sonnet before every opus
+	rank := map[string]int{"haiku": 0, "sonnet": 1, "opus": 2}
+	for i := 1; i < len(listed.Models); i++ {
+		if rank[listed.Models[i-1].Family] > rank[listed.Models[i].Family] {
+			t.Errorf("%s listed before %s", listed.Models[i-1].Name, listed.Models[i].Name)
+		}
+	}
+}
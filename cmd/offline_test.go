@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+func TestOfflineMakesNoRequests(t *testing.T) {
+	srv := newCountServer(t, 10)
+	old := writeFile(t, "old.txt", "The quick brown fox.\n")
+	newer := writeFile(t, "new.txt", "The quick brown fox jumps over the lazy dog.\n")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"count", []string{"count", old}},
+		{"analyze", []string{"count", "--analyze", old}},
+		{"diff", []string{"diff", old, newer}},
+		{"fit", []string{"fit", "--budget", "5", newer}},
+		{"visualize", []string{"visualize", "json", old}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An API key and base URL are set, so only --offline keeps the command off the network
+			args := append([]string{tt.args[0], "--offline"}, tt.args[1:]...)
+			if _, err := runCommandAPI(t, srv.URL, args...); err != nil {
+				t.Fatalf("%v: %v", tt.args, err)
+			}
+		})
+	}
+	if got := len(srv.received()); got != 0 {
+		t.Errorf("server received %d requests, want 0", got)
+	}
+}
+
+func TestOfflineUsesNoAPIClient(t *testing.T) {
+	path := writeFile(t, "notes.txt", "Hello world\n")
+	if _, err := runCommand(t, "count", path); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := counter.(*api.OfflineCounter); !ok {
+		t.Errorf("counter = %T, want *api.OfflineCounter", counter)
+	}
+}
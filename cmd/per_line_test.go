@@ -0,0 +1,30 @@
+package cmd
+
+import "testing"
+
+func TestPerLineAnnotatesFixture(t *testing.T) {
+	path := writeFile(t, "hello.go", "package main\n"+
+		"\n"+
+		"// main prints a greeting to standard output and exits with status zero when done\n"+
+		"func main() {\n"+
+		"\tprintln(\"hello, world\")\n"+
+		"}\n")
+
+	stdout, err := runCommand(t, "count", "--per-line", "--plain", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Counts from the client-side tokenizer, right-aligned to the widest count
+	want := " 3 | package main\n" +
+		" 1 | \n" +
+		"16 | // main prints a greeting to standard output and exits with status zero when done\n" +
+		" 5 | func main() {\n" +
+		" 8 | \tprintln(\"hello, world\")\n" +
+		" 2 | }\n" +
+		"\n" +
+		path + ": 35 tokens across 6 lines (client-side tokenizer)\n"
+	if stdout != want {
+		t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+	}
+}
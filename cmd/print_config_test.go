@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// printConfigArgsEnv carries the arguments for a --print-config run in a test subprocess,
+// since --print-config exits the process
+const printConfigArgsEnv = "CC_TOKEN_TEST_PRINT_CONFIG_ARGS"
+
+func TestPrintConfigFlagOverridesEnvironment(t *testing.T) {
+	if args := os.Getenv(printConfigArgsEnv); args != "" {
+		resetFlags(rootCmd)
+		rootCmd.SetArgs(strings.Fields(args))
+		if err := rootCmd.Execute(); err != nil {
+			os.Exit(2)
+		}
+		// --print-config should have exited already
+		os.Exit(3)
+	}
+
+	envDir, flagDir := t.TempDir(), t.TempDir()
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPrintConfigFlagOverridesEnvironment$")
+		cmd.Env = append(os.Environ(),
+			printConfigArgsEnv+"="+strings.Join(args, " "),
+			"CC_TOKEN_CACHE_DIR="+envDir,
+			"ANTHROPIC_BASE_URL=https://gateway.example.com",
+			"ANTHROPIC_API_KEY=",
+		)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		var printed map[string]any
+		if err := json.Unmarshal(out, &printed); err != nil {
+			t.Fatalf("%v printed invalid JSON: %v\n%s", args, err, out)
+		}
+		return printed
+	}
+
+	printed := run("count", "--print-config")
+	if printed["cache_dir"] != envDir || printed["api_base_url"] != "https://gateway.example.com" {
+		t.Errorf("without flags: cache_dir = %v, api_base_url = %v, want the environment values", printed["cache_dir"], printed["api_base_url"])
+	}
+
+	printed = run("count", "--print-config", "--cache-dir", flagDir, "--api-base-url", "http://localhost:9000", "--model", "opus")
+	if printed["cache_dir"] != flagDir || printed["api_base_url"] != "http://localhost:9000" {
+		t.Errorf("with flags: cache_dir = %v, api_base_url = %v, want the flag values", printed["cache_dir"], printed["api_base_url"])
+	}
+	if model, _ := printed["model"].(string); !strings.HasPrefix(model, "claude-opus") {
+		t.Errorf("model = %v, want the opus alias resolved", printed["model"])
+	}
+}
@@ -2,13 +2,18 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/fatih/color"
+	"github.com/iota-uz/cc-token/internal/analyzer"
 	"github.com/iota-uz/cc-token/internal/api"
 	"github.com/iota-uz/cc-token/internal/cache"
 	"github.com/iota-uz/cc-token/internal/config"
 	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/server"
 	"github.com/spf13/cobra"
 )
 
@@ -20,9 +25,15 @@ const (
 	defaultConcurrency = 5
 )
 
+// tokenCounter is the counting backend: the API client, or the client-side tokenizer with --offline
+type tokenCounter interface {
+	api.TokenCounter
+	api.ConversationCounter
+}
+
 var (
 	cfg            *config.Config
-	apiClient      *api.Client
+	counter        tokenCounter
 	cacheInst      *cache.Cache
 	pricingService *pricing.Pricer
 )
@@ -34,7 +45,18 @@ var rootCmd = &cobra.Command{
 	Long: `cc-token is a CLI tool for counting tokens in files and directories using Anthropic's Claude API.
 It supports caching, parallel processing, and multiple output formats.`,
 	Version: version,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The bare root command only prints help, unless it is asked for the config
+		if !cmd.HasParent() && !cfg.PrintConfig {
+			return nil
+		}
+
+		// Every formatter's ANSI colors go through fatih/color, so this one switch covers them all
+		color.NoColor = !colorEnabled()
+
 		// Normalize extensions to have leading dots
 		for i, ext := range cfg.Extensions {
 			if ext != "" && ext[0] != '.' {
@@ -42,40 +64,111 @@ It supports caching, parallel processing, and multiple output formats.`,
 			}
 		}
 
+		// --json is a deprecated alias for --format json
+		if cfg.JSONOutput {
+			if cmd.Flags().Changed("format") && cfg.Format != config.FormatJSON {
+				return fmt.Errorf("--json conflicts with --format %s", cfg.Format)
+			}
+			cfg.Format = config.FormatJSON
+		}
+
+		// --by-ext is shorthand for --group-by ext
+		if cfg.ByExtension && cfg.GroupBy == "" {
+			cfg.GroupBy = config.GroupByExtension
+		}
+
+		// Resolve the API endpoint (flag, then ANTHROPIC_BASE_URL, then the official endpoint)
+		if cfg.APIBaseURL == "" {
+			cfg.APIBaseURL = os.Getenv("ANTHROPIC_BASE_URL")
+		}
+		if cfg.APIBaseURL == "" {
+			cfg.APIBaseURL = api.DefaultBaseURL
+		}
+
 		// Validate configuration
 		if err := cfg.Validate(); err != nil {
 			return err
 		}
 
+		// Load the system prompt from a file, or use the value as the prompt itself
+		if cfg.System != "" {
+			systemPrompt, err := readSystemPrompt(cfg.System)
+			if err != nil {
+				return err
+			}
+			cfg.SystemPrompt = systemPrompt
+		}
+
 		// Resolve model alias
-		pricingService = pricing.New()
+		pricingService = pricing.New(cfg.PricingFile)
 		cfg.Model = pricingService.ResolveModelAlias(cfg.Model)
+		currency, _ := pricing.LookupCurrency(cfg.Currency)
+		cfg.Currency = currency.Code
+		pricingService.SetCurrency(currency)
+		if cfg.Verbose && currency.Code != pricing.DefaultCurrency {
+			fmt.Fprintf(os.Stderr, "Currency: %s at %g per USD (%s)\n", currency.Code, currency.Rate, currency.RateSource())
+		}
+		if !pricingService.IsKnownModel(cfg.Model) && (cfg.Strict || !cfg.AllowUnknownModel) {
+			return unknownModelError(cfg.Model)
+		}
+
+		// Resolve cache location (flag, then CC_TOKEN_CACHE_DIR, then ~/.cc-token)
+		if !cfg.NoCache || isCacheCommand(cmd) {
+			cacheDir, err := cache.ResolveDir(cfg.CacheDir)
+			if err != nil {
+				return err
+			}
+			cfg.CacheDir = cacheDir
+		}
+
+		// --print-config reports the fully resolved settings and exits, like --version
+		if cfg.PrintConfig {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(cfg.Redacted()); err != nil {
+				return fmt.Errorf("failed to print config: %w", err)
+			}
+			os.Exit(0)
+		}
 
-		// Validate API key (except for cache clear command)
-		if cmd.Name() != "clear" {
+		// Validate API key (except for cache management commands and offline runs)
+		if cfg.Offline {
+			counter = api.NewOfflineCounter()
+		} else if !isCacheCommand(cmd) && cmd != modelsCmd {
 			apiKey := os.Getenv("ANTHROPIC_API_KEY")
 			if apiKey == "" {
 				return fmt.Errorf("ANTHROPIC_API_KEY environment variable is not set.\nGet your API key from: https://console.anthropic.com/")
 			}
 
 			// Initialize API client
-			apiClient = api.NewClient(apiKey)
+			apiClient := api.NewClientWithOptions(apiKey, api.WithBaseURL(cfg.APIBaseURL), api.WithTimeout(cfg.Timeout))
+			apiClient.SetMaxRetries(cfg.MaxRetries)
+			// The adaptive limiter retries 429s itself, so it sees each one and backs off
+			apiClient.SetRetryRateLimits(!cfg.AdaptiveConcurrency)
+			counter = apiClient
 		}
 
-		// Initialize cache
-		if !cfg.NoCache && cmd.Name() != "clear" {
+		// Initialize cache (offline counts are approximate, so they never enter the cache)
+		if !cfg.NoCache && !cfg.Offline && !isCacheCommand(cmd) && cmd != modelsCmd {
 			var err error
-			cacheInst, err = cache.Load()
+			cacheInst, err = cache.Load(cfg.CacheDir)
 			if err != nil && cfg.Verbose {
 				fmt.Fprintf(os.Stderr, "Warning: Failed to load cache: %v\n", err)
 			}
+			if cacheInst != nil {
+				cacheInst.SetMaxEntries(cfg.CacheMaxEntries)
+				if discarded := cacheInst.Discarded(); discarded > 0 {
+					fmt.Fprintf(os.Stderr, "Note: Discarded %d cache entries from an older cache format that didn't record the model; they will be recounted\n", discarded)
+				}
+			}
 		}
 
 		return nil
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
 		// Save cache
-		if cacheInst != nil && cmd.Name() != "clear" {
+		if cacheInst != nil && !isCacheCommand(cmd) {
 			if err := cacheInst.Save(); err != nil && cfg.Verbose {
 				fmt.Fprintf(os.Stderr, "Warning: Failed to save cache: %v\n", err)
 			}
@@ -84,9 +177,75 @@ It supports caching, parallel processing, and multiple output formats.`,
 	},
 }
 
+// colorEnabled reports whether output may use ANSI colors: not with --plain or --no-color, when
+// NO_COLOR is set (https://no-color.org), or when stdout is not a terminal
+func colorEnabled() bool {
+	if cfg.Plain || cfg.NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal; tests replace it to simulate one
+var stdoutIsTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// unknownModelError explains that model has no known pricing, suggesting the closest known name
+func unknownModelError(model string) error {
+	hint := "use --allow-unknown-model for models released after this version, priced as Sonnet"
+	if cfg.Strict {
+		hint = "--strict requires a model with known pricing"
+	}
+	if suggestion := pricingService.SuggestModel(model); suggestion != "" {
+		return fmt.Errorf("unknown model %q; did you mean %q? (%s)", model, suggestion, hint)
+	}
+	return fmt.Errorf("unknown model %q (%s; see cc-token models)", model, hint)
+}
+
+// isCacheCommand reports whether cmd is a cache management subcommand, which works on the
+// cache directory directly and needs no API key
+func isCacheCommand(cmd *cobra.Command) bool {
+	return cmd.Parent() == cacheCmd
+}
+
+// readSystemPrompt returns the contents of value when it names an existing file, otherwise value itself
+func readSystemPrompt(value string) (string, error) {
+	info, err := os.Stat(value)
+	if err != nil || info.IsDir() {
+		return value, nil
+	}
+
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to read system prompt: %w", err)
+	}
+	return string(content), nil
+}
+
+// exitUnauthorized is the exit code when the API rejected the API key
+const exitUnauthorized = 3
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if errors.Is(err, api.ErrUnauthorized) {
+		fmt.Fprintln(os.Stderr, "Your ANTHROPIC_API_KEY appears invalid or expired; check https://console.anthropic.com/settings/keys")
+	}
+	return err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, api.ErrUnauthorized):
+		return exitUnauthorized
+	default:
+		return 1
+	}
 }
 
 func init() {
@@ -94,16 +253,82 @@ func init() {
 
 	// Global flags available to all commands
 	rootCmd.PersistentFlags().StringVarP(&cfg.Model, "model", "m", pricing.DefaultModel, "Model to use for token counting (supports aliases: sonnet, haiku, opus)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AllowUnknownModel, "allow-unknown-model", false, "Accept a --model without known pricing (costs are estimated at Sonnet prices)")
 	rootCmd.PersistentFlags().StringSliceVarP(&cfg.Extensions, "ext", "e", []string{}, "File extensions to include (e.g., .go,.txt,.md)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.Exclude, "exclude", []string{}, "Glob pattern of paths to skip, relative to the directory being scanned (repeatable, supports **)")
+	rootCmd.PersistentFlags().IntVar(&cfg.MaxDepth, "max-depth", -1, "Levels of subdirectories to descend into (0 for the directory's own files only, -1 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.FollowSymlinks, "follow-symlinks", false, "Walk into symlinked directories (each real directory is visited once)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.IncludeBinary, "include-binary", false, "Count files that look like binary data instead of skipping them")
 	rootCmd.PersistentFlags().Int64Var(&cfg.MaxSize, "max-size", defaultMaxFileSize, "Maximum file size in bytes (default: 2MB)")
+	rootCmd.PersistentFlags().Int64Var(&cfg.ChunkSize, "chunk-size", 0, "Count files over --max-size in chunks of this many bytes and sum them (approximate; 0 skips such files)")
+	rootCmd.PersistentFlags().IntVar(&cfg.MaxTokens, "max-tokens", 0, "Count only the first N tokens of each file, e.g. to sample large logs (0 for no limit)")
 	rootCmd.PersistentFlags().IntVarP(&cfg.Concurrency, "concurrency", "c", defaultConcurrency, "Number of concurrent API requests for directories")
 	rootCmd.PersistentFlags().BoolVar(&cfg.ShowCost, "show-cost", true, "Show estimated API cost")
-	rootCmd.PersistentFlags().BoolVarP(&cfg.JSONOutput, "json", "j", false, "Output results in JSON format")
+	rootCmd.PersistentFlags().Float64Var(&cfg.MaxCost, "max-cost", 0, "Estimate a directory's cost before counting and confirm (abort with --yes or without a terminal) if it exceeds this amount (in --currency)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Currency, "currency", pricing.DefaultCurrency, "Currency to convert and display costs in (USD, EUR, GBP, JPY, CAD, AUD, CHF, INR)")
+	rootCmd.PersistentFlags().StringVar(&cfg.PricingFile, "pricing-file", "", "JSON file of model input prices (USD per 1M tokens) merged over the built-in prices (default: ~/.cc-token/pricing.json)")
+	rootCmd.PersistentFlags().IntVar(&cfg.CacheWrites, "cache-write", 0, "Estimate prompt caching: number of requests writing the counted content to the prompt cache")
+	rootCmd.PersistentFlags().IntVar(&cfg.CacheReads, "cache-read", 0, "Estimate prompt caching: number of requests reading the counted content from the prompt cache")
+	rootCmd.PersistentFlags().StringVar(&cfg.Format, "format", config.FormatTree, "Output format: tree, json, csv, markdown or sarif (csv and markdown apply to count results, sarif to --analyze)")
+	rootCmd.PersistentFlags().BoolVarP(&cfg.JSONOutput, "json", "j", false, "Output results in JSON format (deprecated: use --format json)")
+	_ = rootCmd.PersistentFlags().MarkDeprecated("json", "use --format json instead")
 	rootCmd.PersistentFlags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&cfg.CacheDir, "cache-dir", "", "Cache directory, may be shared between machines (default: $CC_TOKEN_CACHE_DIR or ~/.cc-token)")
+	rootCmd.PersistentFlags().IntVar(&cfg.CacheMaxEntries, "cache-max-entries", cache.DefaultMaxEntries, "Maximum cached entries; least recently used entries are evicted on save (0 for unlimited)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.NoCache, "no-cache", false, "Disable caching")
+	rootCmd.PersistentFlags().BoolVar(&cfg.PrintConfig, "print-config", false, "Print the resolved configuration as JSON and exit")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Offline, "offline", false, "Count with the client-side tokenizer only (approximate; no API key or network needed)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Refresh, "refresh", false, "Re-count every file via the API, ignoring cache hits, and update the cache")
 	rootCmd.PersistentFlags().BoolVarP(&cfg.SkipConfirmation, "yes", "y", false, "Skip confirmation prompts (for automation)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.Plain, "plain", false, "Use plain text output without ANSI colors")
-	rootCmd.PersistentFlags().StringVarP(&cfg.OutputFile, "output", "o", "", "Output file path for HTML export")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoColor, "no-color", false, "Disable ANSI colors but keep the regular layout (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().StringVarP(&cfg.OutputFile, "output", "o", "", "Output file path for HTML export, or for the part of a file that fits with fit")
 	rootCmd.PersistentFlags().BoolVar(&cfg.NoBrowser, "no-browser", false, "Skip auto-opening browser for web visualization")
+	rootCmd.PersistentFlags().StringVar(&cfg.Host, "host", server.DefaultHost, "Address the interactive visualization server listens on (e.g. 0.0.0.0 to reach it from other machines)")
+	rootCmd.PersistentFlags().IntVar(&cfg.Port, "port", 0, "Port for the interactive visualization server (default: first free port from 8080)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.HighlightIssues, "highlight-issues", false, "Highlight tokens overlapping invisible characters, emoji or confusables in visualize output")
 	rootCmd.PersistentFlags().BoolVar(&cfg.Analyze, "analyze", false, "Perform comprehensive token optimization analysis (files only)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.EnableDetectors, "enable-detector", []string{}, "With --analyze, run only this detector (repeatable, e.g. bidi_control)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.DisableDetectors, "disable-detector", []string{}, "With --analyze, skip this detector (repeatable, e.g. repeated_phrase)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.OnlySecurity, "only-security", false, "With --analyze, run only the security detectors (bidi, confusables, encoding, injection, invisible, normalization, secret)")
+	rootCmd.PersistentFlags().IntVar(&cfg.MinRepetitions, "min-repetitions", analyzer.DefaultThresholds().MinRepetitions, "With --analyze, occurrences before a phrase counts as repeated")
+	rootCmd.PersistentFlags().IntVar(&cfg.LongLineLength, "long-line-length", analyzer.DefaultThresholds().LongLineLength, "With --analyze, characters above which a line is reported as long")
+	rootCmd.PersistentFlags().IntVar(&cfg.MinURLLength, "min-url-length", analyzer.DefaultThresholds().MinURLLength, "With --analyze, length above which a repeated URL is worth a reference link")
+	rootCmd.PersistentFlags().IntVar(&cfg.FailUnder, "fail-under", 0, "With --analyze, exit 1 if a file's LLM reliability score is below this (0-100)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.FailOnDetectors, "fail-on-detector", []string{}, "With --analyze, exit 1 if this LLM safety detector reports any issue (repeatable, e.g. bidi_control)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Fix, "fix", false, "With --analyze, strip invisible/BiDi characters, normalize to NFC and collapse blank lines (prints the fixed file)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.WriteFixes, "write", false, "With --fix, rewrite files in place instead of printing to stdout (required for directories)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Progress, "progress", false, "Report directory scan progress on stderr even when it is not a terminal")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoProgress, "no-progress", false, "Never report directory scan progress")
+	rootCmd.PersistentFlags().BoolVar(&cfg.CostOnly, "cost-only", false, "Print only the total estimated cost as a bare number (for scripts)")
+	rootCmd.PersistentFlags().BoolVarP(&cfg.Quiet, "quiet", "q", false, "Print only the total token count as a bare number (for scripts; errors still go to stderr)")
+	rootCmd.PersistentFlags().IntVar(&cfg.CostPrecision, "cost-precision", pricing.DefaultCostPrecision, "Decimal places for displayed costs (sub-cent costs auto-scale; JSON keeps full precision)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.JSONTree, "json-tree", false, "With --format json, emit nested directories with subtotals and children arrays")
+	rootCmd.PersistentFlags().BoolVar(&cfg.EmitHash, "emit-hash", false, "Include SHA-256 content hashes in JSON output")
+	rootCmd.PersistentFlags().StringVar(&cfg.Annotate, "annotate", "", "Count files listed in a YAML manifest and write tokens/cost/counted_at back into each entry")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Strict, "strict", false, "CI mode: reject unknown models and exit non-zero on secrets, context window overflow and priority-1 analysis issues")
+	rootCmd.PersistentFlags().StringVar(&cfg.System, "system", "", "System prompt (file path or literal text) sent with every request and counted separately")
+	rootCmd.PersistentFlags().IntVar(&cfg.MaxRetries, "max-retries", api.DefaultMaxRetries, "Retries for transient API failures (429, 5xx, network) with exponential backoff; total attempts = retries+1")
+	rootCmd.PersistentFlags().DurationVar(&cfg.Timeout, "timeout", api.DefaultTimeout, "Timeout for each API request attempt (e.g. 90s, 2m)")
+	rootCmd.PersistentFlags().StringVar(&cfg.APIBaseURL, "api-base-url", "", "Anthropic API endpoint, e.g. a gateway or proxy (default: $ANTHROPIC_BASE_URL or "+api.DefaultBaseURL+")")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AdaptiveConcurrency, "adaptive-concurrency", false, "Ramp API concurrency up on success and back off on rate limits (starts at --concurrency)")
+	rootCmd.PersistentFlags().IntVar(&cfg.CountRuns, "count-runs", 0, "Count a single file N times and report min/max/mean tokens and latency")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ByRole, "by-role", false, "Count a conversation JSON file with per-role (system/user/assistant) subtotals")
+	rootCmd.PersistentFlags().StringVar(&cfg.StdinDelimiter, "stdin-delimiter", "", "Split stdin into documents on this delimiter and count each separately (supports \\0, \\n, \\t)")
+	rootCmd.PersistentFlags().StringVar(&cfg.FilesFrom, "files-from", "", "Count only the newline-separated paths listed in this file (- for stdin) instead of path arguments")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.Texts, "text", []string{}, "Count this literal string instead of a file (repeatable; each is reported separately and totals are summed)")
+	rootCmd.PersistentFlags().StringVar(&cfg.StdinName, "stdin-name", "<stdin>", "Name to report for stdin input in place of <stdin>")
+	rootCmd.PersistentFlags().StringVar(&cfg.JSONPath, "json-path", "", "Count only string values at this path in .json files (e.g. $.prompt, $.messages[*].content)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.StripHTML, "strip-html", false, "Strip tags, scripts and styles from HTML content before counting")
+	rootCmd.PersistentFlags().BoolVar(&cfg.StripFrontmatter, "strip-frontmatter", false, "Strip leading YAML front matter from markdown content before counting")
+	rootCmd.PersistentFlags().StringVar(&cfg.ContentType, "content-type", "", "Treat input as html, markdown or text instead of detecting by extension (required for stdin preprocessing)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ParseMIME, "parse-mime", false, "Count only the text/plain and text/html body parts of .eml files")
+	rootCmd.PersistentFlags().BoolVar(&cfg.PerLine, "per-line", false, "Print a single file with each line's token count in a gutter (client-side tokenizer)")
+	rootCmd.PersistentFlags().IntVar(&cfg.Budget, "budget", 0, "Token budget for the fit command")
+	rootCmd.PersistentFlags().BoolVar(&cfg.TUI, "tui", false, "Browse count results in an interactive terminal UI")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Stats, "stats", false, "Report per-file token distribution (min/median/p90/p95/max/mean)")
+	rootCmd.PersistentFlags().IntVar(&cfg.Top, "top", 0, "Show only the N files with the most tokens, with totals across all files")
+	rootCmd.PersistentFlags().StringVar(&cfg.Sort, "sort", config.SortName, "Order of a directory's files in tree output: name, tokens or cost (most expensive first)")
+	rootCmd.PersistentFlags().StringVar(&cfg.GroupBy, "group-by", "", "Group count summary totals (supported: ext)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ByExtension, "by-ext", false, "Break count summary totals down by file extension (same as --group-by ext)")
 }
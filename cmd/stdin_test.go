@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// withStdin replaces os.Stdin with content for the rest of the test
+func withStdin(t *testing.T, content string) {
+	t.Helper()
+	f, err := os.Open(writeFile(t, "stdin", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		f.Close()
+	})
+}
+
+func TestStdinName(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"default", []string{"count", "--format", "json", "-"}, "<stdin>"},
+		{"custom", []string{"count", "--format", "json", "--stdin-name", "prompts/system.md", "-"}, "prompts/system.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, "You are a helpful assistant.\n")
+			out, err := runCommand(t, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var results []struct {
+				Path   string `json:"path"`
+				Tokens int    `json:"tokens"`
+			}
+			if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 {
+				t.Fatalf("invalid JSON (err %v):\n%s", err, out)
+			}
+			if results[0].Path != tt.want || results[0].Tokens == 0 {
+				t.Errorf("result = %+v, want path %q with tokens", results[0], tt.want)
+			}
+		})
+	}
+
+	withStdin(t, "You are a helpful assistant.\n")
+	out, err := runCommand(t, "count", "--stdin-name", "system.md", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "system.md:") {
+		t.Errorf("tree output = %q, want the custom name", out)
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrictFailsOnSecret(t *testing.T) {
+	secret := writeFile(t, "settings.env", "DEBUG=true\nANTHROPIC_API_KEY=sk-ant-REDACTED\n")
+	clean := writeFile(t, "readme.txt", "Nothing to see here.\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"count with secret", []string{"count", "--strict", secret}, "secrets found in " + secret},
+		{"analyze with secret", []string{"count", "--strict", "--analyze", secret}, "secrets found in " + secret},
+		{"clean file", []string{"count", "--strict", clean}, ""},
+		{"secret without --strict", []string{"count", secret}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCommand(t, tt.args...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if code := ExitCode(err); code == 0 {
+				t.Errorf("exit code = 0, want non-zero")
+			}
+		})
+	}
+}
+
+func TestStrictFailsOnContextOverflow(t *testing.T) {
+	// About 250k tokens offline, over the 200k context window
+	large := writeFile(t, "large.txt", strings.Repeat("word ", 250_000))
+
+	_, err := runCommand(t, "count", "--strict", "--max-size", "10000000", large)
+	if err == nil || !strings.Contains(err.Error(), "context window") {
+		t.Fatalf("error = %v, want a context window overflow", err)
+	}
+	if _, err := runCommand(t, "count", "--max-size", "10000000", large); err != nil {
+		t.Fatalf("without --strict: %v", err)
+	}
+}
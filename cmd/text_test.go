@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCountText(t *testing.T) {
+	srv := newCountServer(t, 7)
+
+	out, err := runCommandAPI(t, srv.URL, "count", "--format", "json", "--text", "Hello, world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []struct {
+		Path   string `json:"path"`
+		Tokens int    `json:"tokens"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil || len(results) != 1 {
+		t.Fatalf("invalid JSON (err %v):\n%s", err, out)
+	}
+	if results[0].Path != "<text>" || results[0].Tokens != 7 {
+		t.Errorf("result = %+v, want <text> with 7 tokens", results[0])
+	}
+	requests := srv.received()
+	if len(requests) != 1 || requests[0].Messages[0].Content.Text != "Hello, world" {
+		t.Fatalf("got requests %+v, want one request with the literal string", requests)
+	}
+
+	// Each --text is counted separately and the totals are summed
+	out, err = runCommandAPI(t, srv.URL, "count", "--text", "first", "--text", "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<text>#1: 7 tokens", "<text>#2: 7 tokens", "Total: 14 tokens across 2 files"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output:\n%s\nwant %q", out, want)
+		}
+	}
+	var contents []string
+	for _, req := range srv.received()[1:] {
+		contents = append(contents, req.Messages[0].Content.Text)
+	}
+	slices.Sort(contents)
+	if !slices.Equal(contents, []string{"first", "second"}) {
+		t.Errorf("counted %q, want first and second", contents)
+	}
+}
@@ -25,7 +25,7 @@ Visualization Modes:
   json        - Output structured JSON data (LLM-friendly, machine-readable)
   plain       - Output plain text with pipe delimiters (no ANSI colors)
 
-The global --json flag can also be used with 'basic' or 'interactive' modes to override
+The global --format json flag can also be used with 'basic' or 'interactive' modes to override
 the output format to JSON.
 
 Note: Visualization only works with single files, not directories.`,
@@ -51,7 +51,7 @@ Note: Visualization only works with single files, not directories.`,
   cc-token visualize plain document.txt
 
   # Use JSON with global flag
-  cc-token visualize basic --json document.txt
+  cc-token visualize basic --format json document.txt
 
   # Skip confirmation prompt
   cc-token visualize basic --yes document.txt
@@ -81,8 +81,11 @@ Note: Visualization only works with single files, not directories.`,
 		// args[0] is the mode, args[1] is the file path
 		path := args[1]
 
+		// Arguments are valid; failures from here on (e.g. a busy --port) are not usage errors
+		cmd.SilenceUsage = true
+
 		// Create visualizer
-		viz := visualizer.New(apiClient, pricingService)
+		viz := visualizer.New(counter, pricingService)
 
 		// Run visualization
 		return viz.Run(path, cfg)
@@ -0,0 +1,62 @@
+package analyzer
+
+import "sort"
+
+// FileAnalysis is the analysis of a single file within a directory analysis
+type FileAnalysis struct {
+	Path     string
+	Analysis *Analysis
+}
+
+// DirectoryAnalysis aggregates the analyses of all files under a directory
+type DirectoryAnalysis struct {
+	Files            []*FileAnalysis
+	TotalTokens      int
+	PotentialSavings int
+}
+
+// AttributedLine is a line insight together with the file it belongs to
+type AttributedLine struct {
+	Path string
+	*LineInsight
+}
+
+// NewDirectoryAnalysis combines per-file analyses, summing their totals
+func NewDirectoryAnalysis(files []*FileAnalysis) *DirectoryAnalysis {
+	dir := &DirectoryAnalysis{Files: files}
+	for _, file := range files {
+		dir.TotalTokens += file.Analysis.TotalTokens
+		dir.PotentialSavings += file.Analysis.PotentialSavings
+	}
+	return dir
+}
+
+// GetTopExpensiveLines returns the N most token-expensive lines across all files
+func (d *DirectoryAnalysis) GetTopExpensiveLines(n int) []*AttributedLine {
+	var lines []*AttributedLine
+	for _, file := range d.Files {
+		// Each file's top N is enough to find the global top N
+		for _, line := range file.Analysis.GetTopExpensiveLines(n) {
+			lines = append(lines, &AttributedLine{Path: file.Path, LineInsight: line})
+		}
+	}
+
+	// Stable ordering keeps file order for ties
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Tokens > lines[j].Tokens
+	})
+
+	if n > len(lines) {
+		n = len(lines)
+	}
+	return lines[:n]
+}
+
+// Recommendations returns the recommendations of every file analysis
+func (d *DirectoryAnalysis) Recommendations() []*Recommendation {
+	var recommendations []*Recommendation
+	for _, file := range d.Files {
+		recommendations = append(recommendations, file.Analysis.Recommendations...)
+	}
+	return recommendations
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+)
+
+// lineAnalysis builds an analysis with one line insight per token count
+func lineAnalysis(tokens ...int) *Analysis {
+	analysis := &Analysis{}
+	for i, n := range tokens {
+		analysis.LineInsights = append(analysis.LineInsights, &LineInsight{LineNumber: i + 1, Tokens: n})
+		analysis.TotalTokens += n
+	}
+	return analysis
+}
+
+func TestDirectoryTopExpensiveLines(t *testing.T) {
+	dir := NewDirectoryAnalysis([]*FileAnalysis{
+		{Path: "a.md", Analysis: lineAnalysis(5, 40, 10, 30)},
+		{Path: "b.md", Analysis: lineAnalysis(35, 1, 50)},
+	})
+	if dir.TotalTokens != 171 {
+		t.Errorf("total tokens = %d, want 171", dir.TotalTokens)
+	}
+
+	var got []string
+	for _, line := range dir.GetTopExpensiveLines(4) {
+		got = append(got, fmt.Sprintf("%s:%d=%d", line.Path, line.LineNumber, line.Tokens))
+	}
+	want := []string{"b.md:3=50", "a.md:2=40", "b.md:1=35", "a.md:4=30"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("top lines = %v, want %v", got, want)
+	}
+
+	if all := dir.GetTopExpensiveLines(100); len(all) != 7 {
+		t.Errorf("top 100 = %d lines, want all 7", len(all))
+	}
+}
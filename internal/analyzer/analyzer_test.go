@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+const formattingRecommendation = "Simplify heavy formatting"
+
+func analyzeOffline(t *testing.T, content string, opts Options) *Analysis {
+	t.Helper()
+	counter := api.NewOfflineCounter()
+	tokens, err := counter.CountTokens(content, "")
+	if err != nil {
+		t.Fatalf("CountTokens: %v", err)
+	}
+	analysis, err := AnalyzeFile(content, tokens, counter, opts)
+	if err != nil {
+		t.Fatalf("AnalyzeFile: %v", err)
+	}
+	return analysis
+}
+
+func hasRecommendation(analysis *Analysis, title string) bool {
+	for _, rec := range analysis.Recommendations {
+		if rec.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFormattingRecommendation(t *testing.T) {
+	heavy := strings.Repeat("## **_Big_** **`Title`** ~~*x*~~ **[a](b)**\n", 20)
+	prose := strings.Repeat("The quick brown fox jumps over the lazy dog near the river bank.\n", 20)
+
+	tests := []struct {
+		name    string
+		content string
+		ratio   float64
+		want    bool
+	}{
+		{"heavily formatted file", heavy, 0, true},
+		{"plain prose", prose, 0, false},
+		{"ratio above the formatting share", heavy, 0.99, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := analyzeOffline(t, tt.content, Options{Thresholds: Thresholds{FormattingRatio: tt.ratio}})
+			if got := hasRecommendation(analysis, formattingRecommendation); got != tt.want {
+				t.Errorf("formatting recommendation = %v, want %v (formatting %d of %d tokens)",
+					got, tt.want, analysis.CategoryBreakdown.Formatting, analysis.CategoryBreakdown.Total)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ type CategoryBreakdown struct {
 	CodeBlocks int // Code blocks (``` markers)
 	URLs       int // URLs
 	Formatting int // Markdown formatting symbols
+	Tables     int // Markdown table syntax: pipes, delimiter rows and padding
 	Whitespace int // Empty lines and whitespace
 	Total      int
 }
@@ -24,6 +25,7 @@ type CategoryStats struct {
 	CodeBlocks float64
 	URLs       float64
 	Formatting float64
+	Tables     float64
 	Whitespace float64
 }
 
@@ -38,6 +40,9 @@ var (
 	urlRegex            = regexp.MustCompile(`https?://[^\s\)]+`)
 )
 
+// markdownSyntaxChars are the characters one of the Markdown formatting regexes needs to match
+const markdownSyntaxChars = "#-*+_[`"
+
 // CategorizeTokens classifies tokens into categories
 func CategorizeTokens(lines []string, tokens []api.Token, insights []*LineInsight) *CategoryBreakdown {
 	breakdown := &CategoryBreakdown{}
@@ -46,16 +51,11 @@ func CategorizeTokens(lines []string, tokens []api.Token, insights []*LineInsigh
 	inCodeBlock := false
 	codeBlockTokens := 0
 
-	// Map tokens to lines
-	lineTokenMap := make(map[int][]api.Token)
-	lineStarts := utils.CalculateLineStarts(lines)
+	lineTokenMap := tokensByLine(lines, tokens)
+	tableLines := tableLineSet(lines)
 
-	for _, token := range tokens {
-		lineIdx := utils.FindLineForPosition(token.Position, lineStarts)
-		if lineIdx >= 0 && lineIdx < len(lines) {
-			lineTokenMap[lineIdx] = append(lineTokenMap[lineIdx], token)
-		}
-	}
+	// Repeated URLs are estimated once
+	urlTokenCache := make(map[string]int)
 
 	// Process each line
 	for i, line := range lines {
@@ -81,14 +81,38 @@ func CategorizeTokens(lines []string, tokens []api.Token, insights []*LineInsigh
 			continue
 		}
 
+		// Table rows split into table syntax and cell content
+		if tableLines[i] {
+			for _, token := range lineTokens {
+				if isTableFormattingToken(token) {
+					breakdown.Tables++
+				} else {
+					breakdown.Prose++
+				}
+			}
+			continue
+		}
+
+		// Lines without URL or Markdown syntax characters are all prose; skip the regexes
+		hasURL := strings.Contains(line, "://")
+		if !hasURL && !strings.ContainsAny(line, markdownSyntaxChars) {
+			breakdown.Prose += lineTokenCount
+			continue
+		}
+
 		// Categorize tokens on this line
 		urlTokens := 0
 		formattingTokens := 0
 
 		// URLs
-		if urlMatches := urlRegex.FindAllString(line, -1); len(urlMatches) > 0 {
-			for _, url := range urlMatches {
-				urlTokens += utils.EstimateTokens(url)
+		if hasURL {
+			for _, url := range urlRegex.FindAllString(line, -1) {
+				estimate, ok := urlTokenCache[url]
+				if !ok {
+					estimate = utils.EstimateTokens(url)
+					urlTokenCache[url] = estimate
+				}
+				urlTokens += estimate
 			}
 		}
 
@@ -146,11 +170,32 @@ func CategorizeTokens(lines []string, tokens []api.Token, insights []*LineInsigh
 
 	// Calculate total
 	breakdown.Total = breakdown.Prose + breakdown.CodeBlocks + breakdown.URLs +
-		breakdown.Formatting + breakdown.Whitespace
+		breakdown.Formatting + breakdown.Tables + breakdown.Whitespace
 
 	return breakdown
 }
 
+// tokensByLine groups tokens by the line they start on. Tokens are in position order, so a
+// single pass over the line starts assigns them without a search per token.
+func tokensByLine(lines []string, tokens []api.Token) [][]api.Token {
+	byLine := make([][]api.Token, len(lines))
+	lineStarts := utils.CalculateLineStarts(lines)
+
+	line := 0
+	for _, token := range tokens {
+		if token.Position < 0 {
+			continue
+		}
+		for line+1 < len(lineStarts) && token.Position >= lineStarts[line+1] {
+			line++
+		}
+		if line < len(lines) {
+			byLine[line] = append(byLine[line], token)
+		}
+	}
+	return byLine
+}
+
 // GetStats calculates percentage breakdown
 func (c *CategoryBreakdown) GetStats() *CategoryStats {
 	if c.Total == 0 {
@@ -162,6 +207,7 @@ func (c *CategoryBreakdown) GetStats() *CategoryStats {
 		CodeBlocks: float64(c.CodeBlocks) / float64(c.Total) * 100,
 		URLs:       float64(c.URLs) / float64(c.Total) * 100,
 		Formatting: float64(c.Formatting) / float64(c.Total) * 100,
+		Tables:     float64(c.Tables) / float64(c.Total) * 100,
 		Whitespace: float64(c.Whitespace) / float64(c.Total) * 100,
 	}
 }
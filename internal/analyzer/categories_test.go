@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// benchmarkLines is the size of the CategorizeTokens benchmark input
+const benchmarkLines = 50_000
+
+// categorizeBenchmarkContent returns Markdown mixing prose, formatting, URLs, tables and code
+// blocks, with mostly unformatted lines as in typical documents
+func categorizeBenchmarkContent(lines int) string {
+	paragraph := []string{
+		"## Section heading",
+		"",
+		"The quick brown fox jumps over the lazy dog, then rests under the old oak tree.",
+		"Plain prose without any formatting makes up most of a typical document.",
+		"See https://example.com/docs/getting-started?ref=benchmark for **more** details.",
+		"| Name | Value |",
+		"|------|-------|",
+		"| alpha | 1 |",
+		"```go",
+		"func main() { fmt.Println(\"hello\") }",
+		"```",
+		"- a list item with `inline code` and _emphasis_",
+		"Another ordinary sentence that carries content rather than markup.",
+		"",
+	}
+	var b strings.Builder
+	for i := 0; i < lines; i++ {
+		line := paragraph[i%len(paragraph)]
+		if strings.Contains(line, "example.com") {
+			line = strings.Replace(line, "benchmark", fmt.Sprintf("benchmark-%d", i%50), 1)
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func BenchmarkCategorizeTokens(b *testing.B) {
+	content := categorizeBenchmarkContent(benchmarkLines)
+	tokens, err := api.NewOfflineCounter().ExtractTokensClientSide(content)
+	if err != nil {
+		b.Fatal(err)
+	}
+	lines := strings.Split(content, "\n")
+	insights := TokensPerLine(content, tokens)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CategorizeTokens(lines, tokens, insights)
+	}
+}
@@ -36,8 +36,7 @@ func (d *BiDiControlDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*BiDiControlIssue, 0)
 
 	for lineNum, line := range ctx.Lines {
-		runes := []rune(line)
-		for pos, r := range runes {
+		for pos, r := range line {
 			if controlType, exists := bidiControlCharMap[r]; exists {
 				context := extractContext(line, pos)
 				isTrojanSource := detectTrojanSourcePattern(line)
@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"unicode/utf8"
+
 	"github.com/mtibben/confusables"
 )
 
@@ -40,8 +42,7 @@ func (d *ConfusablesDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*ConfusableIssue, 0)
 
 	for lineNum, line := range ctx.Lines {
-		runes := []rune(line)
-		for pos, r := range runes {
+		for pos, r := range line {
 			// Skip ASCII characters and common punctuation
 			if r < 128 {
 				continue
@@ -138,18 +139,27 @@ func getUnicodeScriptHelper(r rune) string {
 // This is a local helper for the detector (llmsafety.go has detectMixedScript)
 func detectMixedScriptConfusable(line string, pos int) bool {
 	start := pos
-	for start > 0 && (isLetterOrDigit(rune(line[start-1]))) {
-		start--
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:start])
+		if !isLetterOrDigit(r) {
+			break
+		}
+		start -= size
 	}
-	end := pos
-	for end < len(line)-1 && (isLetterOrDigit(rune(line[end+1]))) {
-		end++
+	_, size := utf8.DecodeRuneInString(line[pos:])
+	end := pos + size
+	for end < len(line) {
+		r, size := utf8.DecodeRuneInString(line[end:])
+		if !isLetterOrDigit(r) {
+			break
+		}
+		end += size
 	}
-	if end-start < 2 {
+
+	word := line[start:end]
+	if utf8.RuneCountInString(word) < 3 {
 		return false
 	}
-
-	word := line[start : end+1]
 	hasLatin, hasCyrillic, hasGreek := false, false, false
 	for _, r := range word {
 		if r >= 0x0041 && r <= 0x007A {
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestConfusableAfterEmoji(t *testing.T) {
+	// The emoji are 4 bytes each, so byte and rune offsets of the Cyrillic a (U+0430) differ
+	line := "\U0001F680\U0001F680 Launch: P\u0430y the invoice today"
+	d := NewConfusablesDetector()
+	if err := d.Detect(&DetectionContext{Content: line, Lines: []string{line}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.issues) != 1 {
+		t.Fatalf("got %d issues %+v, want the Cyrillic a", len(d.issues), d.issues)
+	}
+	issue := d.issues[0]
+	if want := strings.Index(line, "\u0430"); issue.Position != want {
+		t.Errorf("position = %d, want byte offset %d", issue.Position, want)
+	}
+	if issue.OriginalChar != '\u0430' || issue.ConfusableChar != 'a' {
+		t.Errorf("confusable = %q for %q, want U+0430 for 'a'", issue.OriginalChar, issue.ConfusableChar)
+	}
+	if !utf8.ValidString(issue.Context) || !strings.Contains(issue.Context, "P\u0430y") {
+		t.Errorf("context = %q, want valid UTF-8 around the confusable", issue.Context)
+	}
+	if !issue.IsMixedScript {
+		t.Error("IsMixedScript = false, want true for a Cyrillic letter in a Latin word")
+	}
+}
+
+func TestExtractContextMultibyte(t *testing.T) {
+	line := strings.Repeat("\U0001F680", 20) + "\u0430" + strings.Repeat("\u00e9", 20)
+	for pos := 0; pos <= len(line); pos++ {
+		if context := extractContext(line, pos); !utf8.ValidString(context) {
+			t.Fatalf("extractContext at byte %d = %q, not valid UTF-8", pos, context)
+		}
+	}
+}
@@ -2,13 +2,18 @@ package analyzer
 
 // ConsecutiveEmptyDetector finds consecutive empty lines that can harm readability and tokenization
 type ConsecutiveEmptyDetector struct {
-	issues []*ConsecutiveEmptyLines
+	minLines int
+	issues   []*ConsecutiveEmptyLines
 }
 
-// NewConsecutiveEmptyDetector creates a new consecutive empty detector
-func NewConsecutiveEmptyDetector() *ConsecutiveEmptyDetector {
+// NewConsecutiveEmptyDetector creates a new consecutive empty detector flagging runs of at least minLines empty lines
+func NewConsecutiveEmptyDetector(minLines int) *ConsecutiveEmptyDetector {
+	if minLines <= 0 {
+		minLines = minConsecutiveEmptyLines
+	}
 	return &ConsecutiveEmptyDetector{
-		issues: make([]*ConsecutiveEmptyLines, 0),
+		minLines: minLines,
+		issues:   make([]*ConsecutiveEmptyLines, 0),
 	}
 }
 
@@ -51,7 +56,7 @@ func (d *ConsecutiveEmptyDetector) Detect(ctx *DetectionContext) error {
 				currentRun.Count++
 			}
 		} else {
-			if currentRun != nil && currentRun.Count >= minConsecutiveEmptyLines {
+			if currentRun != nil && currentRun.Count >= d.minLines {
 				d.issues = append(d.issues, currentRun)
 			}
 			currentRun = nil
@@ -59,7 +64,7 @@ func (d *ConsecutiveEmptyDetector) Detect(ctx *DetectionContext) error {
 	}
 
 	// Don't forget the last run
-	if currentRun != nil && currentRun.Count >= minConsecutiveEmptyLines {
+	if currentRun != nil && currentRun.Count >= d.minLines {
 		d.issues = append(d.issues, currentRun)
 	}
 
@@ -1,16 +1,38 @@
 package analyzer
 
-import "regexp"
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minDecodedTextLength is the minimum decoded length for Base64 or hex to count as hidden text
+	minDecodedTextLength = 4
+	// defaultMinBase64Entropy is the Shannon entropy (bits per character) below which a Base64-looking
+	// string is treated as an identifier or repeated filler rather than encoded data
+	defaultMinBase64Entropy = 3.5
+)
 
 // EncodingDetector finds Base64, hex, ROT13, leetspeak, and ASCII art patterns
 type EncodingDetector struct {
-	issues []*EncodingIssue
+	minBase64Entropy float64
+	issues           []*EncodingIssue
 }
 
-// NewEncodingDetector creates a new encoding detector
-func NewEncodingDetector() *EncodingDetector {
+// NewEncodingDetector creates a new encoding detector ignoring Base64 candidates whose entropy is
+// below minBase64Entropy bits per character
+func NewEncodingDetector(minBase64Entropy float64) *EncodingDetector {
+	if minBase64Entropy <= 0 {
+		minBase64Entropy = defaultMinBase64Entropy
+	}
 	return &EncodingDetector{
-		issues: make([]*EncodingIssue, 0),
+		minBase64Entropy: minBase64Entropy,
+		issues:           make([]*EncodingIssue, 0),
 	}
 }
 
@@ -38,17 +60,26 @@ func (d *EncodingDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*EncodingIssue, 0)
 
 	base64Pattern := regexp.MustCompile(`[A-Za-z0-9+/]{20,}={0,2}`)
-	hexPattern := regexp.MustCompile(`(?:\\x[0-9a-fA-F]{2}|0x[0-9a-fA-F]{8,})`)
+	hexPattern := regexp.MustCompile(`(?:(?:\\x[0-9a-fA-F]{2})+|0x[0-9a-fA-F]{8,})`)
 
 	for lineNum, line := range ctx.Lines {
 		// Base64 detection
 		if matches := base64Pattern.FindAllStringIndex(line, -1); len(matches) > 0 {
 			for _, match := range matches {
 				encoded := line[match[0]:match[1]]
+				// Skip fragments of longer words, paths and identifiers
+				if !isStandaloneMatch(line, match[0], match[1]) || shannonEntropy(encoded) < d.minBase64Entropy {
+					continue
+				}
+				// Only report strings that decode to readable text; hashes and binary blobs don't
+				decoded, ok := decodeBase64Text(encoded)
+				if !ok {
+					continue
+				}
 				issue := &EncodingIssue{
 					EncodingType: "base64",
 					EncodedText:  encoded,
-					DecodedText:  "",
+					DecodedText:  decoded,
 					LineNumber:   lineNum + 1,
 					Position:     match[0],
 					Length:       len(encoded),
@@ -62,10 +93,14 @@ func (d *EncodingDetector) Detect(ctx *DetectionContext) error {
 		if matches := hexPattern.FindAllStringIndex(line, -1); len(matches) > 0 {
 			for _, match := range matches {
 				encoded := line[match[0]:match[1]]
+				decoded, ok := decodeHexText(encoded)
+				if !ok {
+					continue
+				}
 				issue := &EncodingIssue{
 					EncodingType: "hex",
 					EncodedText:  encoded,
-					DecodedText:  "",
+					DecodedText:  decoded,
 					LineNumber:   lineNum + 1,
 					Position:     match[0],
 					Length:       len(encoded),
@@ -150,3 +185,75 @@ func rot13DecodeEncoding(text string) string {
 func detectASCIIArtEncoding(line string) bool {
 	return detectASCIIArt(line)
 }
+
+// decodeBase64Text decodes standard Base64 and reports whether the result is readable text.
+// The input must be a multiple of 4 characters long, with padding where needed.
+func decodeBase64Text(encoded string) (string, bool) {
+	if len(encoded)%4 != 0 {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	return printableText(decoded)
+}
+
+// isStandaloneMatch reports whether line[start:end] is not glued to surrounding word
+// characters, e.g. part of a longer identifier, file name or URL path
+func isStandaloneMatch(line string, start, end int) bool {
+	isWordByte := func(b byte) bool {
+		return b == '_' || b == '-' || b == '.' || b == '/' || b == '\\' ||
+			(b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+	}
+	if start > 0 && isWordByte(line[start-1]) {
+		return false
+	}
+	return end == len(line) || !isWordByte(line[end])
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// decodeHexText decodes a run of \xNN escapes or a 0x-prefixed hex literal and reports
+// whether the result is readable text
+func decodeHexText(encoded string) (string, bool) {
+	digits := strings.ReplaceAll(encoded, `\x`, "")
+	digits = strings.TrimPrefix(digits, "0x")
+	decoded, err := hex.DecodeString(digits)
+	if err != nil {
+		return "", false
+	}
+	return printableText(decoded)
+}
+
+// printableText returns b as a string if it is valid UTF-8 of at least minDecodedTextLength
+// characters made of printable characters and whitespace
+func printableText(b []byte) (string, bool) {
+	if !utf8.Valid(b) || utf8.RuneCount(b) < minDecodedTextLength {
+		return "", false
+	}
+	text := string(b)
+	for _, r := range text {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return "", false
+		}
+	}
+	return text, true
+}
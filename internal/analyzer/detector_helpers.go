@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // detector_helpers.go contains shared helper functions and constants used by multiple detectors
@@ -77,16 +78,26 @@ func tryMergeIssueByLineAndType[T any](issues []*T, lineNum int, typeMatcher fun
 	return false
 }
 
-// extractContext extracts a context window around a position in a line
+// extractContext extracts a context window around a byte offset in a line. The window is
+// widened to rune boundaries so multibyte characters are never split.
 func extractContext(line string, pos int) string {
 	start := pos - invisibleCharContextSize
 	if start < 0 {
 		start = 0
 	}
+	for start > 0 && !utf8.RuneStart(line[start]) {
+		start--
+	}
 	end := pos + invisibleCharContextSize
 	if end > len(line) {
 		end = len(line)
 	}
+	for end < len(line) && !utf8.RuneStart(line[end]) {
+		end++
+	}
+	if start > end {
+		start = end
+	}
 	return line[start:end]
 }
 
@@ -166,8 +177,8 @@ func rot13Decode(text string) string {
 // detectASCIIArt checks if a line is likely ASCII art
 // ASCII art has high density of box-drawing and ASCII art characters
 func detectASCIIArt(line string) bool {
-	// Skip short lines
-	if len(line) < 10 {
+	// Skip short lines and Markdown table delimiter rows
+	if len(line) < 10 || tableDelimiterPattern.MatchString(line) {
 		return false
 	}
 
@@ -209,6 +220,10 @@ func estimateTokenCost(issueType string, value string) int {
 	case "hash":
 		return (len(value) + 3) / 4 // 1 token per 3-4 hex characters
 
+	// Pattern types
+	case "long_word":
+		return (len(value) + 2) / 3 // Unbroken runs split into short subwords, ~3 characters each
+
 	default:
 		return 1
 	}
@@ -0,0 +1,216 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/utils"
+)
+
+var (
+	// xmlTagPattern matches an opening or closing XML/HTML tag, capturing the slash of a
+	// closing tag, the name (which ends at whitespace or '>') and any attributes
+	xmlTagPattern = regexp.MustCompile(`<(/)?([A-Za-z][\w:.-]*)(\s[^<>]*)?>`)
+)
+
+// InlineBlobDetector finds large serialized JSON or XML payloads embedded inline in prose
+type InlineBlobDetector struct {
+	minLength int
+	issues    []*InlineBlob
+}
+
+// NewInlineBlobDetector creates a new inline blob detector flagging blocks of at least minLength characters
+func NewInlineBlobDetector(minLength int) *InlineBlobDetector {
+	if minLength <= 0 {
+		minLength = defaultInlineBlobLength
+	}
+	return &InlineBlobDetector{
+		minLength: minLength,
+		issues:    make([]*InlineBlob, 0),
+	}
+}
+
+// Name returns the detector's identifier
+func (d *InlineBlobDetector) Name() string {
+	return "inline_blob"
+}
+
+// Priority returns execution priority (lower values execute first)
+func (d *InlineBlobDetector) Priority() int {
+	return 18
+}
+
+// Issues returns the detected issues
+func (d *InlineBlobDetector) Issues() []interface{} {
+	result := make([]interface{}, len(d.issues))
+	for i, issue := range d.issues {
+		result[i] = issue
+	}
+	return result
+}
+
+// Detect performs inline JSON/XML blob detection
+func (d *InlineBlobDetector) Detect(ctx *DetectionContext) error {
+	d.issues = make([]*InlineBlob, 0)
+	content := ctx.Content
+	lineStarts := utils.CalculateLineStarts(ctx.Lines)
+
+	// A file that is nothing but one payload is data, not a prompt with an embedded blob
+	trimmed := len(strings.TrimSpace(content))
+
+	record := func(kind string, start, end int) {
+		if end-start >= trimmed {
+			return
+		}
+		d.issues = append(d.issues, &InlineBlob{
+			Kind:      kind,
+			StartLine: utils.FindLineForPosition(start, lineStarts) + 1,
+			EndLine:   utils.FindLineForPosition(end-1, lineStarts) + 1,
+			Length:    end - start,
+			TokenCost: tokensInRange(ctx.Tokens, start, end),
+			Preview:   utils.Truncate(strings.Join(strings.Fields(content[start:end]), " "), 60),
+		})
+	}
+
+	brackets := matchBrackets(content)
+	elements := matchXMLElements(content)
+
+	// Every start is looked up rather than rescanned, so the walk is linear in the content
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{', '[':
+			end, ok := brackets[i]
+			if !ok {
+				continue
+			}
+			if end-i >= d.minLength && json.Valid([]byte(content[i:end])) {
+				record("json", i, end)
+				i = end - 1
+			} else if end-i < d.minLength {
+				// Nothing inside a short balanced block can reach the threshold
+				i = end - 1
+			}
+
+		case '<':
+			element, ok := elements[i]
+			if ok && element.end-i >= d.minLength && element.tags >= minInlineXMLTags {
+				record("xml", i, element.end)
+				i = element.end - 1
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchBrackets maps the index of each balanced '{' or '[' to the index just past its closing
+// bracket, in one pass. Brackets inside JSON strings are skipped once a bracket is open; a
+// mismatched closer leaves every bracket still open unbalanced, as does reaching the end.
+func matchBrackets(content string) map[int]int {
+	matches := make(map[int]int)
+	var stack []int
+	inString := false
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"', '\n':
+				// JSON strings can't span lines, so a stray quote in prose ends with its line
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			// Quotes in prose outside any bracket don't start a string
+			inString = len(stack) > 0
+		case '{', '[':
+			stack = append(stack, i)
+		case '}', ']':
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			if closingBracket(content[open]) != c {
+				stack = stack[:0]
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			matches[open] = i + 1
+		}
+	}
+
+	return matches
+}
+
+// closingBracket returns the bracket closing open
+func closingBracket(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// xmlElement is a matched XML element: the index just past its closing tag and the number
+// of tags from its opening tag to there
+type xmlElement struct {
+	end  int
+	tags int
+}
+
+// matchXMLElements maps the index of each opening tag with a matching close tag of the same
+// name to the element it starts, in one pass over the tags. Self-closing tags are ignored.
+func matchXMLElements(content string) map[int]xmlElement {
+	elements := make(map[int]xmlElement)
+
+	// Positions of every '<', to count the tags within an element by binary search
+	var angles []int
+	for i := 0; i < len(content); i++ {
+		if content[i] == '<' {
+			angles = append(angles, i)
+		}
+	}
+
+	open := make(map[string][]int) // Unclosed opening tag positions by name
+	for _, loc := range xmlTagPattern.FindAllStringSubmatchIndex(content, -1) {
+		name := content[loc[4]:loc[5]]
+		isClose := loc[2] != -1
+		if !isClose {
+			if loc[6] == -1 || !strings.HasSuffix(content[loc[6]:loc[7]], "/") {
+				open[name] = append(open[name], loc[0])
+			}
+			continue
+		}
+		if loc[6] != -1 {
+			continue // "</name attr>" is not a close tag
+		}
+
+		starts := open[name]
+		if len(starts) == 0 {
+			continue
+		}
+		start := starts[len(starts)-1]
+		open[name] = starts[:len(starts)-1]
+		end := loc[1]
+		elements[start] = xmlElement{
+			end:  end,
+			tags: sort.SearchInts(angles, end) - sort.SearchInts(angles, start),
+		}
+	}
+
+	return elements
+}
+
+// tokensInRange counts the tokens starting within [start, end). Tokens are ordered by position.
+func tokensInRange(tokens []api.Token, start, end int) int {
+	first := sort.Search(len(tokens), func(i int) bool { return tokens[i].Position >= start })
+	last := sort.Search(len(tokens), func(i int) bool { return tokens[i].Position >= end })
+	return last - first
+}
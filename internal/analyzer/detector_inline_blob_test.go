@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// byteTokens splits content into one token per byte, which is enough for position-based tests
+func byteTokens(content string) []api.Token {
+	tokens := make([]api.Token, len(content))
+	for i := range content {
+		tokens[i] = api.Token{Text: content[i : i+1], Position: i, Length: 1}
+	}
+	return tokens
+}
+
+func detectInlineBlobs(t *testing.T, content string, minLength int) []*InlineBlob {
+	t.Helper()
+	d := NewInlineBlobDetector(minLength)
+	ctx := &DetectionContext{Content: content, Lines: strings.Split(content, "\n"), Tokens: byteTokens(content)}
+	if err := d.Detect(ctx); err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	return d.issues
+}
+
+func TestInlineBlobDetector(t *testing.T) {
+	payload, _ := json.Marshal(map[string][]int{"values": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+	xml := "<config><entry>1</entry><entry>2</entry><abbr>x</abbr></config>"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string // Kinds of the blobs found, in order
+	}{
+		{"json", "Use this: " + string(payload) + " and answer.", []string{"json"}},
+		{"xml", "Config:\n" + xml + "\nThat is all.", []string{"xml"}},
+		{"json after unbalanced prose", "if x < y { then [ and a \" quote\nPayload " + string(payload) + " end", []string{"json"}},
+		{"short block", "a {\"k\": 1} b", nil},
+		{"unclosed tags", strings.Repeat("x < y <br> ", 20), nil},
+		{"whole file is data", string(payload), nil},
+		// <abbr> must not count as a nested <a>, which would leave the outer <a> unclosed
+		{"tag name boundary", "See <a><abbr>b</abbr><i>c</i><i>d</i></a> here.", []string{"xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, blob := range detectInlineBlobs(t, tt.content, 20) {
+				got = append(got, blob.Kind)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineBlobDetectorTokenCost(t *testing.T) {
+	payload, _ := json.Marshal(map[string][]int{"values": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+	content := "Before\n" + string(payload) + "\nafter"
+	blobs := detectInlineBlobs(t, content, 20)
+	if len(blobs) != 1 {
+		t.Fatalf("got %d blobs, want 1", len(blobs))
+	}
+	if blobs[0].TokenCost != len(payload) || blobs[0].StartLine != 2 || blobs[0].EndLine != 2 {
+		t.Errorf("blob = %+v, want %d tokens on line 2", blobs[0], len(payload))
+	}
+}
+
+func TestInlineBlobDetectorLinear(t *testing.T) {
+	// Unclosed '<' and unbalanced brackets used to rescan to the end of the file from every start
+	content := strings.Repeat("if x < y then { do [ something <br> <a href='#'>link\n", 20000)
+	start := time.Now()
+	detectInlineBlobs(t, content, defaultInlineBlobLength)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("detection on %d bytes took %s", len(content), elapsed)
+	}
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+// LineEndingDetector finds files that mix LF and CRLF line endings
+type LineEndingDetector struct {
+	issues []*LineEndingMix
+}
+
+// NewLineEndingDetector creates a new line ending detector
+func NewLineEndingDetector() *LineEndingDetector {
+	return &LineEndingDetector{
+		issues: make([]*LineEndingMix, 0),
+	}
+}
+
+// Name returns the detector's identifier
+func (d *LineEndingDetector) Name() string {
+	return "line_ending"
+}
+
+// Priority returns execution priority (lower values execute first)
+func (d *LineEndingDetector) Priority() int {
+	return 16
+}
+
+// Issues returns the detected issues
+func (d *LineEndingDetector) Issues() []interface{} {
+	result := make([]interface{}, len(d.issues))
+	for i, issue := range d.issues {
+		result[i] = issue
+	}
+	return result
+}
+
+// Detect counts line endings on the raw content, before any line splitting
+func (d *LineEndingDetector) Detect(ctx *DetectionContext) error {
+	d.issues = make([]*LineEndingMix, 0)
+
+	mix := &LineEndingMix{}
+	lfLines := make([]int, 0)
+	crlfLines := make([]int, 0)
+	lineNumber := 1
+
+	content := ctx.Content
+	for i := 0; i < len(content); i++ {
+		if content[i] != '\n' {
+			continue
+		}
+		if i > 0 && content[i-1] == '\r' {
+			mix.CRLFCount++
+			crlfLines = append(crlfLines, lineNumber)
+		} else {
+			mix.LFCount++
+			lfLines = append(lfLines, lineNumber)
+		}
+		lineNumber++
+	}
+
+	// Only report when both styles are present
+	if mix.LFCount == 0 || mix.CRLFCount == 0 {
+		return nil
+	}
+
+	// Point at the minority style, since those are the lines to convert
+	if mix.CRLFCount <= mix.LFCount {
+		mix.Dominant = "LF"
+		mix.MinorityLines = crlfLines
+	} else {
+		mix.Dominant = "CRLF"
+		mix.MinorityLines = lfLines
+	}
+
+	d.issues = append(d.issues, mix)
+	return nil
+}
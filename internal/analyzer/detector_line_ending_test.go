@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLineEndingDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // "LF=n CRLF=n dominant minority-lines", or empty for no finding
+	}{
+		{"mixed, mostly LF", "one\ntwo\r\nthree\nfour\n", "LF=3 CRLF=1 LF [2]"},
+		{"mixed, mostly CRLF", "one\r\ntwo\r\nthree\n", "LF=1 CRLF=2 CRLF [3]"},
+		{"LF only", "one\ntwo\n", ""},
+		{"CRLF only", "one\r\ntwo\r\n", ""},
+		{"carriage return not before a newline", "one\rtwo\nthree\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewLineEndingDetector()
+			// Lines are split the way the analyzer does; the detector must use the raw content
+			ctx := &DetectionContext{Content: tt.content, Lines: strings.Split(tt.content, "\n")}
+			if err := d.Detect(ctx); err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			got := ""
+			for _, mix := range d.issues {
+				got = fmt.Sprintf("LF=%d CRLF=%d %s %v", mix.LFCount, mix.CRLFCount, mix.Dominant, mix.MinorityLines)
+			}
+			if got != tt.want {
+				t.Errorf("finding = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineEndingRecommendation(t *testing.T) {
+	analysis := analyzeOffline(t, "first line\nsecond line\r\nthird line\n", Options{})
+	if !hasRecommendation(analysis, "Standardize line endings") {
+		t.Error("no line ending recommendation for mixed line endings")
+	}
+	if got := len(analysis.AdvancedPatterns.LineEndings); got != 1 {
+		t.Errorf("line ending findings = %d, want 1", got)
+	}
+}
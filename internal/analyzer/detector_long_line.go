@@ -1,18 +1,25 @@
 package analyzer
 
 import (
+	"unicode/utf8"
+
 	"github.com/iota-uz/cc-token/internal/utils"
 )
 
 // LongLineDetector finds lines that are unusually long
 type LongLineDetector struct {
-	issues []*LongLine
+	threshold int
+	issues    []*LongLine
 }
 
-// NewLongLineDetector creates a new long line detector
-func NewLongLineDetector() *LongLineDetector {
+// NewLongLineDetector creates a new long line detector flagging lines longer than threshold characters
+func NewLongLineDetector(threshold int) *LongLineDetector {
+	if threshold <= 0 {
+		threshold = defaultLongLineThreshold
+	}
 	return &LongLineDetector{
-		issues: make([]*LongLine, 0),
+		threshold: threshold,
+		issues:    make([]*LongLine, 0),
 	}
 }
 
@@ -39,13 +46,14 @@ func (d *LongLineDetector) Issues() []interface{} {
 func (d *LongLineDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*LongLine, 0)
 
-	threshold := defaultLongLineThreshold
-
 	for _, insight := range ctx.LineInsights {
-		if insight.Chars > threshold && insight.Tokens > 0 {
+		// insight.Chars counts bytes; the threshold is in characters
+		length := utf8.RuneCountInString(insight.Content)
+		if length > d.threshold && insight.Tokens > 0 {
 			issue := &LongLine{
 				LineNumber: insight.LineNumber,
-				Length:     insight.Chars,
+				Length:     length,
+				WrapColumn: d.threshold + 1,
 				Tokens:     insight.Tokens,
 				Content:    utils.Truncate(insight.Content, 100),
 			}
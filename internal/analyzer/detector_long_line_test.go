@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLongLineThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		flagged bool
+	}{
+		{"just under", strings.Repeat("a", 39), false},
+		{"at the threshold", strings.Repeat("a", 40), false},
+		{"just over", strings.Repeat("a", 41), true},
+		// 40 characters but 80 bytes: the threshold is in characters
+		{"multibyte at the threshold", strings.Repeat("\u00e9", 40), false},
+		{"multibyte just over", strings.Repeat("\u00e9", 41), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewLongLineDetector(40)
+			insight := &LineInsight{LineNumber: 3, Content: tt.line, Tokens: 10, Chars: len(tt.line)}
+			if err := d.Detect(&DetectionContext{LineInsights: []*LineInsight{insight}}); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(d.issues) == 1; got != tt.flagged {
+				t.Fatalf("flagged = %v, want %v", got, tt.flagged)
+			}
+			if !tt.flagged {
+				return
+			}
+			issue := d.issues[0]
+			if issue.LineNumber != 3 || issue.Length != 41 || issue.WrapColumn != 41 {
+				t.Errorf("issue = line %d, length %d, wrap column %d; want line 3, length 41, wrap column 41",
+					issue.LineNumber, issue.Length, issue.WrapColumn)
+			}
+		})
+	}
+}
+
+func TestLongLineRecommendationPointsAtWrapColumn(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < 11; i++ {
+		content.WriteString(strings.Repeat("word ", 10) + "end\n") // 53 characters
+	}
+	content.WriteString(strings.Repeat("word ", 20) + "end\n") // 103 characters, on line 12
+
+	opts := Options{Thresholds: Thresholds{LongLineLength: 50}}
+	analysis := analyzeOffline(t, content.String(), opts)
+	if got := len(analysis.AdvancedPatterns.LongLines); got != 12 {
+		t.Fatalf("long lines = %d, want 12", got)
+	}
+
+	for _, rec := range analysis.Recommendations {
+		if rec.Title != "Wrap long lines" {
+			continue
+		}
+		if !strings.Contains(rec.Description, "longer than 50 characters") {
+			t.Errorf("description = %q, want the configured threshold", rec.Description)
+		}
+		if want := "Longest: line 12, 103 chars (over the limit from column 51)"; rec.BeforeExample != want {
+			t.Errorf("example = %q, want %q", rec.BeforeExample, want)
+		}
+		return
+	}
+	t.Error("no long line recommendation")
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iota-uz/cc-token/internal/utils"
+)
+
+// LongWordDetector finds unbroken runs of text (long identifiers, minified code) that split into many subword tokens
+type LongWordDetector struct {
+	minLength int
+	issues    []*LongWord
+}
+
+// NewLongWordDetector creates a new long word detector flagging words longer than minLength characters
+func NewLongWordDetector(minLength int) *LongWordDetector {
+	if minLength <= 0 {
+		minLength = defaultLongWordLength
+	}
+	return &LongWordDetector{
+		minLength: minLength,
+		issues:    make([]*LongWord, 0),
+	}
+}
+
+// Name returns the detector's identifier
+func (d *LongWordDetector) Name() string {
+	return "long_word"
+}
+
+// Priority returns execution priority (lower values execute first)
+func (d *LongWordDetector) Priority() int {
+	return 17
+}
+
+// Issues returns the detected issues
+func (d *LongWordDetector) Issues() []interface{} {
+	result := make([]interface{}, len(d.issues))
+	for i, issue := range d.issues {
+		result[i] = issue
+	}
+	return result
+}
+
+// Detect performs long word detection
+func (d *LongWordDetector) Detect(ctx *DetectionContext) error {
+	d.issues = make([]*LongWord, 0)
+
+	for lineNum, line := range ctx.Lines {
+		for _, field := range strings.Fields(line) {
+			word := strings.Trim(field, "\"'`()[]{}<>,;:.!?")
+			length := utf8.RuneCountInString(word)
+			if length <= d.minLength {
+				continue
+			}
+
+			// URLs, hashes and table delimiter rows are covered by other detectors
+			if strings.Contains(word, "://") || isHash(word) || tableDelimiterPattern.MatchString(field) {
+				continue
+			}
+
+			d.issues = append(d.issues, &LongWord{
+				Word:       utils.Truncate(word, 60),
+				Length:     length,
+				LineNumber: lineNum + 1,
+				TokenCost:  estimateTokenCost("long_word", word),
+				text:       word,
+			})
+		}
+	}
+
+	return nil
+}
+
+// withoutOOVStrings drops the long words that are mostly made of strings the OOV detector
+// already reported on the same line, so one identifier isn't reported, and its savings
+// counted, twice. Long minified runs that only contain a short OOV string are kept.
+func withoutOOVStrings(words []*LongWord, oovIssues []*OOVStringIssue) []*LongWord {
+	if len(oovIssues) == 0 {
+		return words
+	}
+
+	kept := make([]*LongWord, 0, len(words))
+	for _, word := range words {
+		covered := 0
+		for _, issue := range oovIssues {
+			if issue.LineNumber != word.LineNumber {
+				continue
+			}
+			if strings.Contains(issue.String, word.text) {
+				covered = len(word.text)
+				break
+			}
+			if strings.Contains(word.text, issue.String) {
+				covered += len(issue.String)
+			}
+		}
+		if covered*2 < len(word.text) {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
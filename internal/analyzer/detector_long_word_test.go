@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLongWordNotDoubleCountedWithOOV(t *testing.T) {
+	identifier := "processUserAuthenticationRequestWithExtendedValidationAndRetryLogic_v2"
+	if len(identifier) != 70 {
+		t.Fatalf("identifier is %d characters, want 70", len(identifier))
+	}
+	content := "Call " + identifier + " before saving.\n"
+
+	tests := []struct {
+		name         string
+		opts         Options
+		wantLongWord int
+		wantOOV      int
+	}{
+		{"reported once, by OOV", Options{}, 0, 1},
+		{"long word when OOV is disabled", Options{DisableDetectors: []string{"oov_strings"}}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := analyzeOffline(t, content, tt.opts)
+			oov := 0
+			for _, issue := range analysis.LLMSafetyAnalysis.OOVStringIssues {
+				if strings.Contains(issue.String, "Authentication") {
+					oov++
+				}
+			}
+			if got := len(analysis.AdvancedPatterns.LongWords); got != tt.wantLongWord || oov != tt.wantOOV {
+				t.Errorf("long words = %d, OOV strings = %d; want %d, %d", got, oov, tt.wantLongWord, tt.wantOOV)
+			}
+			if hasRecommendation(analysis, "Shorten or remove excessively long words") != (tt.wantLongWord > 0) {
+				t.Errorf("long word recommendation present = %v, want %v", !(tt.wantLongWord > 0), tt.wantLongWord > 0)
+			}
+		})
+	}
+}
+
+func TestLongWordKeepsMinifiedCode(t *testing.T) {
+	// Dotted runs have no 20-character identifier for the OOV detector, so only long_word sees them
+	minified := strings.Repeat("a.b(c);d.e=f;", 8)
+	analysis := analyzeOffline(t, "x\n"+minified+"\n", Options{})
+
+	if got := len(analysis.AdvancedPatterns.LongWords); got != 1 {
+		t.Fatalf("long words = %d, want 1", got)
+	}
+	// Trailing punctuation is trimmed from the word
+	want := len(strings.TrimRight(minified, ";"))
+	if word := analysis.AdvancedPatterns.LongWords[0]; word.LineNumber != 2 || word.Length != want {
+		t.Errorf("long word on line %d of length %d, want line 2 length %d", word.LineNumber, word.Length, want)
+	}
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/utils"
+)
+
+// tableDelimiterPattern matches a GFM table delimiter row such as "|:---|---:|" or "--- | ---"
+var tableDelimiterPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)+\|?\s*$`)
+
+// MarkdownTableDetector finds large GitHub-flavored Markdown tables, whose pipes and
+// delimiter rows cost many tokens
+type MarkdownTableDetector struct {
+	minRows int
+	issues  []*MarkdownTable
+}
+
+// NewMarkdownTableDetector creates a new Markdown table detector flagging tables with more than minRows data rows
+func NewMarkdownTableDetector(minRows int) *MarkdownTableDetector {
+	if minRows <= 0 {
+		minRows = defaultMinTableRows
+	}
+	return &MarkdownTableDetector{
+		minRows: minRows,
+		issues:  make([]*MarkdownTable, 0),
+	}
+}
+
+// Name returns the detector's identifier
+func (d *MarkdownTableDetector) Name() string {
+	return "markdown_table"
+}
+
+// Priority returns execution priority (lower values execute first)
+func (d *MarkdownTableDetector) Priority() int {
+	return 19
+}
+
+// Issues returns the detected issues
+func (d *MarkdownTableDetector) Issues() []interface{} {
+	result := make([]interface{}, len(d.issues))
+	for i, issue := range d.issues {
+		result[i] = issue
+	}
+	return result
+}
+
+// Detect performs Markdown table detection
+func (d *MarkdownTableDetector) Detect(ctx *DetectionContext) error {
+	d.issues = make([]*MarkdownTable, 0)
+
+	lineStarts := utils.CalculateLineStarts(ctx.Lines)
+	for _, span := range findMarkdownTables(ctx.Lines) {
+		rows := span.end - span.start - 1 // Excluding the header and delimiter rows
+		if rows <= d.minRows {
+			continue
+		}
+
+		start := lineStarts[span.start]
+		end := lineStarts[span.end] + len(ctx.Lines[span.end])
+		tokens, formatting := 0, 0
+		for _, token := range ctx.Tokens {
+			if token.Position < start || token.Position >= end {
+				continue
+			}
+			tokens++
+			if isTableFormattingToken(token) {
+				formatting++
+			}
+		}
+
+		d.issues = append(d.issues, &MarkdownTable{
+			StartLine:        span.start + 1,
+			EndLine:          span.end + 1,
+			Rows:             rows,
+			Columns:          span.columns,
+			TokenCost:        tokens,
+			FormattingTokens: formatting,
+		})
+	}
+
+	return nil
+}
+
+// tableSpan is a Markdown table's 0-based, inclusive line range
+type tableSpan struct {
+	start, end int
+	columns    int
+}
+
+// findMarkdownTables returns the GFM tables in lines: a header row containing a pipe, a
+// delimiter row, then every following non-blank row containing a pipe. Fenced code blocks
+// are skipped.
+func findMarkdownTables(lines []string) []tableSpan {
+	var spans []tableSpan
+	inCodeBlock := false
+	for i := 0; i+1 < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || !strings.Contains(lines[i], "|") || !tableDelimiterPattern.MatchString(lines[i+1]) {
+			continue
+		}
+
+		end := i + 1
+		for end+1 < len(lines) && strings.TrimSpace(lines[end+1]) != "" && strings.Contains(lines[end+1], "|") {
+			end++
+		}
+		columns := len(strings.Split(strings.Trim(strings.TrimSpace(lines[i+1]), "|"), "|"))
+		spans = append(spans, tableSpan{start: i, end: end, columns: columns})
+		i = end
+	}
+	return spans
+}
+
+// tableLineSet returns the 0-based line numbers that belong to a Markdown table
+func tableLineSet(lines []string) map[int]bool {
+	set := make(map[int]bool)
+	for _, span := range findMarkdownTables(lines) {
+		for line := span.start; line <= span.end; line++ {
+			set[line] = true
+		}
+	}
+	return set
+}
+
+// isTableFormattingToken reports whether a token is only table syntax: pipes, delimiter
+// dashes, alignment colons and cell padding
+func isTableFormattingToken(token api.Token) bool {
+	return token.Text != "" && strings.Trim(token.Text, "|-: \t") == ""
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkdownTableDetectorWideTable(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "wide-table.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis := analyzeOffline(t, string(content), Options{})
+
+	// The small table and the one inside a code block are not reported
+	tables := analysis.AdvancedPatterns.MarkdownTables
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables %+v, want only the wide one", len(tables), tables)
+	}
+	table := tables[0]
+	if table.StartLine != 5 || table.EndLine != 21 || table.Rows != 15 || table.Columns != 8 {
+		t.Errorf("table = lines %d-%d, %d rows x %d columns, want lines 5-21, 15 rows x 8 columns",
+			table.StartLine, table.EndLine, table.Rows, table.Columns)
+	}
+	if table.FormattingTokens <= 0 || table.FormattingTokens >= table.TokenCost {
+		t.Errorf("formatting tokens = %d of %d, want some but not all of the table", table.FormattingTokens, table.TokenCost)
+	}
+
+	// Table syntax is broken out of the category breakdown, separately from prose
+	breakdown := analysis.CategoryBreakdown
+	if breakdown.Tables < table.FormattingTokens || breakdown.Prose == 0 {
+		t.Errorf("breakdown tables = %d, prose = %d, want at least the wide table's %d formatting tokens and some prose",
+			breakdown.Tables, breakdown.Prose, table.FormattingTokens)
+	}
+	if !hasRecommendation(analysis, "Convert large Markdown table to CSV or a list") {
+		t.Error("no recommendation to convert the table")
+	}
+}
@@ -36,35 +36,42 @@ func (d *NumberFormattingDetector) Issues() []interface{} {
 	return result
 }
 
+var (
+	// unformattedNumberPattern matches numbers with 4+ digits (no commas)
+	unformattedNumberPattern = regexp.MustCompile(`\b\d{4,}\b`)
+	// currencyAdjacentPattern matches a currency symbol glued to a number, e.g. $1234567.
+	// Single digits are skipped so shell positional parameters like $1 aren't flagged.
+	currencyAdjacentPattern = regexp.MustCompile(`([$€£¥₹])(\d{2,}(?:[.,]\d+)*|\d(?:[.,]\d+)+)\b`)
+	// unitAdjacentPattern matches a number glued to a unit, e.g. 45kg or 512MB
+	unitAdjacentPattern = regexp.MustCompile(`\b(\d+(?:[.,]\d+)*)(kg|mg|g|lbs|lb|km|cm|mm|m|mi|ft|ml|ms|KB|MB|GB|TB|kHz|MHz|GHz|Hz|kW|mAh)\b`)
+)
+
 // Detect performs number formatting detection
 func (d *NumberFormattingDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*NumberFormatIssue, 0)
 
-	// Match unformatted numbers with 4+ digits (no commas)
-	numberPattern := regexp.MustCompile(`\b\d{4,}\b`)
-
 	for lineNum, line := range ctx.Lines {
-		matches := numberPattern.FindAllString(line, -1)
-		for _, match := range matches {
-			// Check if it's already formatted
-			if strings.Contains(match, ",") {
-				continue // Already formatted
+		// Numbers glued to units or currency are reported once, as adjacency issues
+		adjacent := d.detectUnitAdjacency(line, lineNum+1)
+
+		for _, loc := range unformattedNumberPattern.FindAllStringIndex(line, -1) {
+			if overlapsAny(loc, adjacent) {
+				continue
 			}
+			match := line[loc[0]:loc[1]]
 
 			// Check if it has proper comma formatting (if it should)
 			formatted := addCommasToNumber(match)
 			if formatted != match {
-				suggestion := formatted
-				saveEstimate := estimateNumberFormatTokenSave(match, formatted)
-
 				issue := &NumberFormatIssue{
+					Kind:         "unformatted",
 					Number:       match,
 					IsFormatted:  false,
 					LineNumber:   lineNum + 1,
 					LineContent:  line,
 					TokenCost:    len(strings.Split(match, "")), // Rough estimate
-					Suggestion:   suggestion,
-					SaveEstimate: saveEstimate,
+					Suggestion:   formatted,
+					SaveEstimate: estimateNumberFormatTokenSave(match, formatted),
 				}
 
 				d.issues = append(d.issues, issue)
@@ -75,6 +82,64 @@ func (d *NumberFormattingDetector) Detect(ctx *DetectionContext) error {
 	return nil
 }
 
+// detectUnitAdjacency records numbers glued to currency symbols or units on a line and
+// returns the byte ranges they cover
+func (d *NumberFormattingDetector) detectUnitAdjacency(line string, lineNumber int) [][]int {
+	var spans [][]int
+
+	add := func(loc []int, match, suggestion string) {
+		spans = append(spans, loc)
+		d.issues = append(d.issues, &NumberFormatIssue{
+			Kind:         "unit_adjacency",
+			Number:       match,
+			IsFormatted:  false,
+			LineNumber:   lineNumber,
+			LineContent:  line,
+			TokenCost:    len(match), // Rough estimate
+			Suggestion:   suggestion,
+			SaveEstimate: 0, // Spacing and grouping add tokens; the gain is in accuracy
+		})
+	}
+
+	for _, m := range currencyAdjacentPattern.FindAllStringSubmatchIndex(line, -1) {
+		symbol, digits := line[m[2]:m[3]], line[m[4]:m[5]]
+		add(m[:2], line[m[0]:m[1]], symbol+" "+groupDigits(digits))
+	}
+	for _, m := range unitAdjacentPattern.FindAllStringSubmatchIndex(line, -1) {
+		if overlapsAny(m[:2], spans) {
+			continue
+		}
+		digits, unit := line[m[2]:m[3]], line[m[4]:m[5]]
+		add(m[:2], line[m[0]:m[1]], groupDigits(digits)+" "+unit)
+	}
+
+	return spans
+}
+
+// groupDigits comma-groups the integer part of a number, leaving any decimal part intact.
+// Numbers that already contain commas are returned unchanged.
+func groupDigits(number string) string {
+	if strings.Contains(number, ",") {
+		return number
+	}
+	integer, fraction, hasFraction := strings.Cut(number, ".")
+	grouped := addCommasToNumber(integer)
+	if hasFraction {
+		grouped += "." + fraction
+	}
+	return grouped
+}
+
+// overlapsAny reports whether the byte range loc overlaps any of spans
+func overlapsAny(loc []int, spans [][]int) bool {
+	for _, span := range spans {
+		if loc[0] < span[1] && span[0] < loc[1] {
+			return true
+		}
+	}
+	return false
+}
+
 // addCommasToNumber adds comma grouping to a number string
 func addCommasToNumber(numStr string) string {
 	// Remove any existing formatting
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+const unitRecommendation = "Separate numbers from units and currency symbols"
+
+func TestNumberUnitAdjacency(t *testing.T) {
+	tests := []struct {
+		content        string
+		wantNumber     string
+		wantSuggestion string
+	}{
+		{"The deal closed at $1234567 last quarter.", "$1234567", "$ 1,234,567"},
+		{"Each crate weighs 45kg when full.", "45kg", "45 kg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantNumber, func(t *testing.T) {
+			d := NewNumberFormattingDetector()
+			if err := d.Detect(&DetectionContext{Content: tt.content, Lines: strings.Split(tt.content, "\n")}); err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			var adjacent []*NumberFormatIssue
+			for _, issue := range d.issues {
+				if issue.Kind == "unit_adjacency" {
+					adjacent = append(adjacent, issue)
+				}
+			}
+			if len(adjacent) != 1 {
+				t.Fatalf("unit adjacency issues = %d, want 1", len(adjacent))
+			}
+			if issue := adjacent[0]; issue.Number != tt.wantNumber || issue.Suggestion != tt.wantSuggestion {
+				t.Errorf("issue = %s -> %s, want %s -> %s", issue.Number, issue.Suggestion, tt.wantNumber, tt.wantSuggestion)
+			}
+		})
+	}
+}
+
+func TestNumberUnitRecommendationClaimsNoSavings(t *testing.T) {
+	analysis := analyzeOffline(t, "Revenue: $1234567 from 45kg crates.\n", Options{})
+
+	var rec *Recommendation
+	for _, r := range analysis.Recommendations {
+		if r.Title == unitRecommendation {
+			rec = r
+		}
+	}
+	if rec == nil {
+		t.Fatal("no unit adjacency recommendation")
+	}
+	// Adding spaces and commas costs tokens, so this is an accuracy fix, not a quick token win
+	if rec.EstimatedSave != 0 || rec.SavePercentage != 0 || rec.IsQuickWin {
+		t.Errorf("recommendation saves %d tokens (%.1f%%), quick win %v; want no savings and no quick win",
+			rec.EstimatedSave, rec.SavePercentage, rec.IsQuickWin)
+	}
+	for _, quickWin := range analysis.QuickWins {
+		if quickWin.Title == unitRecommendation {
+			t.Error("unit adjacency listed as a quick win")
+		}
+	}
+}
@@ -3,19 +3,25 @@ package analyzer
 import (
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/iota-uz/cc-token/internal/utils"
 )
 
 // RepeatedPhraseDetector finds phrases that appear multiple times in content
 type RepeatedPhraseDetector struct {
-	issues []*RepeatedPhrase
+	minRepetitions int
+	issues         []*RepeatedPhrase
 }
 
-// NewRepeatedPhraseDetector creates a new repeated phrase detector
-func NewRepeatedPhraseDetector() *RepeatedPhraseDetector {
+// NewRepeatedPhraseDetector creates a new repeated phrase detector flagging phrases that occur at least minRepetitions times
+func NewRepeatedPhraseDetector(minRepetitions int) *RepeatedPhraseDetector {
+	if minRepetitions <= 0 {
+		minRepetitions = defaultMinRepetitions
+	}
 	return &RepeatedPhraseDetector{
-		issues: make([]*RepeatedPhrase, 0),
+		minRepetitions: minRepetitions,
+		issues:         make([]*RepeatedPhrase, 0),
 	}
 }
 
@@ -38,48 +44,157 @@ func (d *RepeatedPhraseDetector) Issues() []interface{} {
 	return result
 }
 
-// Detect performs repeated phrase detection
+// phraseWord is a whitespace-separated word and where it starts in the content
+type phraseWord struct {
+	text   string
+	offset int // Byte offset in the content
+	line   int // 1-based line number
+}
+
+// Detect finds word sequences of at least minPhraseWords words that occur minRepetitions or
+// more times. Each repeated 3-word window is extended while every occurrence continues with
+// the same word, so a repeated paragraph is reported as a few long phrases rather than as
+// every n-gram inside it. Phrases are capped at maxPhraseChars characters.
 func (d *RepeatedPhraseDetector) Detect(ctx *DetectionContext) error {
 	d.issues = make([]*RepeatedPhrase, 0)
 
-	// Reconstruct content from lines for phrase searching
-	content := strings.Join(ctx.Lines, "\n")
-
-	// Common patterns to check
-	candidates := []string{
-		"github.com/iota-uz/cc-token",
-		"github.com/spf13/cobra",
-		"github.com/hupe1980/go-tiktoken",
-		"Renderer interface",
-		"token count",
-		"API key",
+	// Repeated table cells are covered by the Markdown table detector
+	words := splitPhraseWords(ctx.Lines, tableLineSet(ctx.Lines))
+	if len(words) < minPhraseWords {
+		return nil
+	}
+
+	// Index the start positions of every minPhraseWords-word window
+	starts := make(map[string][]int)
+	for i := 0; i+minPhraseWords <= len(words); i++ {
+		key := phraseKey(words[i : i+minPhraseWords])
+		starts[key] = append(starts[key], i)
 	}
 
-	// Track phrase occurrences in a map to avoid duplicates
-	phraseMap := make(map[string]*RepeatedPhrase)
+	covered := make([]bool, len(words))
+	seen := make(map[string]bool)
+	for i := 0; i+minPhraseWords <= len(words); i++ {
+		if covered[i] {
+			continue
+		}
+		key := phraseKey(words[i : i+minPhraseWords])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		positions := nonOverlapping(starts[key], covered, minPhraseWords)
+		if len(positions) < d.minRepetitions {
+			continue
+		}
 
-	for _, phrase := range candidates {
-		count := strings.Count(content, phrase)
-		if count >= minRepetitions {
-			// Estimate tokens (rough approximation)
-			estimatedTokens := utils.EstimateTokens(phrase) * count
+		length := extendPhrase(words, positions)
+		phrase := phraseKey(words[positions[0] : positions[0]+length])
+		if len(phrase) < minPhraseChars {
+			continue
+		}
 
-			phraseMap[phrase] = &RepeatedPhrase{
-				Phrase:      phrase,
-				Count:       count,
-				TotalTokens: estimatedTokens,
-				LineNumbers: findPhraseLines(ctx.Lines, phrase),
+		lineNumbers := make([]int, 0, len(positions))
+		for _, pos := range positions {
+			for j := pos; j < pos+length; j++ {
+				covered[j] = true
+			}
+			if len(lineNumbers) == 0 || lineNumbers[len(lineNumbers)-1] != words[pos].line {
+				lineNumbers = append(lineNumbers, words[pos].line)
 			}
 		}
-	}
 
-	// Convert to slice and sort by total tokens
-	for _, phrase := range phraseMap {
-		d.issues = append(d.issues, phrase)
+		first, last := words[positions[0]], words[positions[0]+length-1]
+		tokensPerOccurrence := tokensInRange(ctx.Tokens, first.offset, last.offset+len(last.text))
+		if tokensPerOccurrence == 0 {
+			tokensPerOccurrence = utils.EstimateTokens(phrase)
+		}
+
+		d.issues = append(d.issues, &RepeatedPhrase{
+			Phrase:      phrase,
+			Count:       len(positions),
+			TotalTokens: tokensPerOccurrence * len(positions),
+			LineNumbers: lineNumbers,
+		})
 	}
-	sort.Slice(d.issues, func(i, j int) bool {
+
+	sort.SliceStable(d.issues, func(i, j int) bool {
 		return d.issues[i].TotalTokens > d.issues[j].TotalTokens
 	})
 
 	return nil
 }
+
+// splitPhraseWords splits lines into words, recording each word's offset and line. Lines in
+// skip are left out.
+func splitPhraseWords(lines []string, skip map[int]bool) []phraseWord {
+	var words []phraseWord
+	lineStart := 0
+	for lineNum, line := range lines {
+		if skip[lineNum] {
+			lineStart += len(line) + 1
+			continue
+		}
+		start := -1
+		for i, r := range line + " " {
+			if unicode.IsSpace(r) {
+				if start >= 0 {
+					words = append(words, phraseWord{text: line[start:i], offset: lineStart + start, line: lineNum + 1})
+					start = -1
+				}
+			} else if start < 0 {
+				start = i
+			}
+		}
+		lineStart += len(line) + 1
+	}
+	return words
+}
+
+// phraseKey joins words with single spaces
+func phraseKey(words []phraseWord) string {
+	texts := make([]string, len(words))
+	for i, word := range words {
+		texts[i] = word.text
+	}
+	return strings.Join(texts, " ")
+}
+
+// nonOverlapping returns the start positions that are not already covered by a reported
+// phrase and do not overlap the previous occurrence
+func nonOverlapping(starts []int, covered []bool, length int) []int {
+	positions := make([]int, 0, len(starts))
+	for _, pos := range starts {
+		if covered[pos] || covered[pos+length-1] {
+			continue
+		}
+		if len(positions) > 0 && pos < positions[len(positions)-1]+length {
+			continue
+		}
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
+// extendPhrase returns how many words, starting at minPhraseWords, every occurrence has in
+// common, without running into the next occurrence or past maxPhraseChars
+func extendPhrase(words []phraseWord, positions []int) int {
+	length := minPhraseWords
+	chars := len(phraseKey(words[positions[0] : positions[0]+length]))
+	for {
+		next := positions[0] + length
+		if next >= len(words) || chars+1+len(words[next].text) > maxPhraseChars {
+			return length
+		}
+		for k, pos := range positions {
+			if pos+length >= len(words) || words[pos+length].text != words[next].text {
+				return length
+			}
+			if k+1 < len(positions) && pos+length >= positions[k+1] {
+				return length
+			}
+		}
+		chars += 1 + len(words[next].text)
+		length++
+	}
+}
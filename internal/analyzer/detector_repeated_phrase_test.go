@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// boilerplate is a disclaimer pasted after every section of a prompt
+const boilerplate = "This message is confidential and intended only for the named recipient."
+
+func detectRepeatedPhrases(t *testing.T, content string, minRepetitions int) []*RepeatedPhrase {
+	t.Helper()
+	tokens, err := api.NewOfflineCounter().ExtractTokensClientSide(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewRepeatedPhraseDetector(minRepetitions)
+	if err := d.Detect(&DetectionContext{Content: content, Lines: strings.Split(content, "\n"), Tokens: tokens}); err != nil {
+		t.Fatal(err)
+	}
+	return d.issues
+}
+
+func TestRepeatedPhraseFindsBoilerplateParagraph(t *testing.T) {
+	content := strings.Join([]string{
+		"Section one covers the quarterly revenue figures.",
+		boilerplate,
+		"Section two explains the hiring plan for next year.",
+		boilerplate,
+		"Section three lists open risks and their owners.",
+		boilerplate,
+	}, "\n")
+
+	issues := detectRepeatedPhrases(t, content, 3)
+	if len(issues) != 1 {
+		t.Fatalf("got %d repeated phrases %+v, want only the boilerplate", len(issues), issues)
+	}
+	issue := issues[0]
+	if issue.Phrase != boilerplate || issue.Count != 3 {
+		t.Errorf("phrase = %q x%d, want the whole boilerplate x3", issue.Phrase, issue.Count)
+	}
+	if got := issue.LineNumbers; len(got) != 3 || got[0] != 2 || got[1] != 4 || got[2] != 6 {
+		t.Errorf("line numbers = %v, want [2 4 6]", got)
+	}
+	if issue.TotalTokens <= 0 || issue.TotalTokens%3 != 0 {
+		t.Errorf("total tokens = %d, want three times the phrase's tokens", issue.TotalTokens)
+	}
+
+	if issues := detectRepeatedPhrases(t, content, 4); len(issues) != 0 {
+		t.Errorf("with 4 repetitions required, got %+v, want none", issues)
+	}
+}
+
+func TestRepeatedPhraseIgnoresUniqueText(t *testing.T) {
+	content := "Section one covers revenue.\nSection two covers hiring.\nSection three covers risks.\n"
+	if issues := detectRepeatedPhrases(t, content, 2); len(issues) != 0 {
+		t.Errorf("got %+v, want no repeated phrases in unique text", issues)
+	}
+}
+
+func TestRepeatedPhraseCapsLength(t *testing.T) {
+	paragraph := strings.Repeat("every word here is part of one very long repeated paragraph ", 10)
+	issues := detectRepeatedPhrases(t, paragraph+"\n"+paragraph+"\n"+paragraph, 3)
+	if len(issues) == 0 {
+		t.Fatal("found no repeated phrases")
+	}
+	for _, issue := range issues {
+		if len(issue.Phrase) > maxPhraseChars {
+			t.Errorf("phrase of %d characters exceeds the %d-character cap", len(issue.Phrase), maxPhraseChars)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"regexp"
+)
+
+const (
+	// minGenericSecretLength is the shortest value of a key = "value" assignment treated as a secret
+	minGenericSecretLength = 12
+	// minGenericSecretEntropy is the bits per character below which an assigned value is a placeholder
+	minGenericSecretEntropy = 3.0
+	// secretVisibleChars is how many leading characters of a secret are kept when masking it
+	secretVisibleChars = 4
+)
+
+// secretPattern matches one kind of credential
+type secretPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// secretPatterns are well-known credential formats, checked in order; earlier kinds win
+// when matches overlap
+var secretPatterns = []secretPattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"anthropic_api_key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{"openai_api_key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{32,}`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"stripe_key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}\b`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+// genericSecretPattern matches a credential-looking key assigned a quoted or bare value;
+// the value is submatch 1
+var genericSecretPattern = regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d|credentials?)["']?\s*[:=]\s*["']?([^\s"',;]+)`)
+
+// SecretDetector finds credentials such as API keys, access tokens and private keys, which
+// should never be sent to a model
+type SecretDetector struct {
+	issues []*SecretIssue
+}
+
+// NewSecretDetector creates a new secret detector
+func NewSecretDetector() *SecretDetector {
+	return &SecretDetector{
+		issues: make([]*SecretIssue, 0),
+	}
+}
+
+// Name returns the detector's identifier
+func (d *SecretDetector) Name() string {
+	return "secret"
+}
+
+// Priority returns execution priority (lower values execute first)
+func (d *SecretDetector) Priority() int {
+	return 11
+}
+
+// Issues returns the detected issues
+func (d *SecretDetector) Issues() []interface{} {
+	result := make([]interface{}, len(d.issues))
+	for i, issue := range d.issues {
+		result[i] = issue
+	}
+	return result
+}
+
+// Detect performs secret detection
+func (d *SecretDetector) Detect(ctx *DetectionContext) error {
+	d.issues = FindSecrets(ctx.Lines)
+	return nil
+}
+
+// FindSecrets returns the credentials found in lines. Matched values are masked in the
+// issues so reports don't leak them.
+func FindSecrets(lines []string) []*SecretIssue {
+	issues := make([]*SecretIssue, 0)
+	for lineNum, line := range lines {
+		var spans [][2]int
+		overlaps := func(start, end int) bool {
+			for _, span := range spans {
+				if start < span[1] && end > span[0] {
+					return true
+				}
+			}
+			return false
+		}
+		add := func(kind string, start, end int) {
+			spans = append(spans, [2]int{start, end})
+			issues = append(issues, &SecretIssue{
+				Kind:       kind,
+				LineNumber: lineNum + 1,
+				Position:   start,
+				Masked:     maskSecret(line[start:end]),
+				Severity:   "critical",
+			})
+		}
+
+		for _, secret := range secretPatterns {
+			for _, match := range secret.pattern.FindAllStringIndex(line, -1) {
+				if !overlaps(match[0], match[1]) {
+					add(secret.kind, match[0], match[1])
+				}
+			}
+		}
+		for _, match := range genericSecretPattern.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[2], match[3]
+			value := line[start:end]
+			if len(value) < minGenericSecretLength || shannonEntropy(value) < minGenericSecretEntropy || overlaps(start, end) {
+				continue
+			}
+			add("generic_secret", start, end)
+		}
+	}
+	return issues
+}
+
+// maskSecret keeps the first few characters of a secret, enough to identify which key it
+// is, and replaces the rest
+func maskSecret(secret string) string {
+	if len(secret) <= secretVisibleChars*2 {
+		return "****"
+	}
+	return secret[:secretVisibleChars] + "****"
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// LineDelta is a line added or removed between two versions of a file
+type LineDelta struct {
+	OldLine int    // Line number in the old version (0 for added lines)
+	NewLine int    // Line number in the new version (0 for removed lines)
+	Content string // Line content
+	Tokens  int    // Client-side tokens gained (positive) or saved (negative)
+}
+
+// DiffLines compares two versions of a file line by line and returns the added and removed
+// lines in file order, each with the tokens it gained or saved. Per-line counts come from the
+// client-side tokenizer, so their sum can differ slightly from the difference of the totals.
+func DiffLines(oldContent, newContent string, apiClient api.TokenCounter) ([]*LineDelta, error) {
+	oldInsights, err := lineInsights(oldContent, apiClient)
+	if err != nil {
+		return nil, err
+	}
+	newInsights, err := lineInsights(newContent, apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edits are usually local, so trim the unchanged head and tail before the LCS
+	prefix := 0
+	for prefix < len(oldInsights) && prefix < len(newInsights) &&
+		oldInsights[prefix].Content == newInsights[prefix].Content {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldInsights)-prefix && suffix < len(newInsights)-prefix &&
+		oldInsights[len(oldInsights)-1-suffix].Content == newInsights[len(newInsights)-1-suffix].Content {
+		suffix++
+	}
+
+	diff := &lineDiff{deltas: make([]*LineDelta, 0)}
+	diff.diff(oldInsights[prefix:len(oldInsights)-suffix], newInsights[prefix:len(newInsights)-suffix])
+	return diff.deltas, nil
+}
+
+// lineDiff accumulates the deltas of a diff in file order
+type lineDiff struct {
+	deltas []*LineDelta
+}
+
+func (d *lineDiff) removed(lines []*LineInsight) {
+	for _, line := range lines {
+		d.deltas = append(d.deltas, &LineDelta{OldLine: line.LineNumber, Content: line.Content, Tokens: -line.Tokens})
+	}
+}
+
+func (d *lineDiff) added(lines []*LineInsight) {
+	for _, line := range lines {
+		d.deltas = append(d.deltas, &LineDelta{NewLine: line.LineNumber, Content: line.Content, Tokens: line.Tokens})
+	}
+}
+
+// diff appends the deltas turning oldLines into newLines along a longest common subsequence,
+// found with Hirschberg's algorithm so memory stays linear in the number of lines
+func (d *lineDiff) diff(oldLines, newLines []*LineInsight) {
+	switch {
+	case len(oldLines) == 0:
+		d.added(newLines)
+		return
+	case len(newLines) == 0:
+		d.removed(oldLines)
+		return
+	case len(oldLines) == 1:
+		for j, line := range newLines {
+			if line.Content == oldLines[0].Content {
+				d.added(newLines[:j])
+				d.added(newLines[j+1:])
+				return
+			}
+		}
+		d.removed(oldLines)
+		d.added(newLines)
+		return
+	}
+
+	// Split the new lines where the LCS of the old halves' prefix and suffix is longest
+	mid := len(oldLines) / 2
+	head := lcsLengths(oldLines[:mid], newLines, false)
+	tail := lcsLengths(oldLines[mid:], newLines, true)
+	split, best := 0, int32(-1)
+	for k := 0; k <= len(newLines); k++ {
+		if length := head[k] + tail[len(newLines)-k]; length > best {
+			split, best = k, length
+		}
+	}
+
+	d.diff(oldLines[:mid], newLines[:split])
+	d.diff(oldLines[mid:], newLines[split:])
+}
+
+// lcsLengths returns, for each j, the length of the longest common subsequence of a and the
+// first j lines of b, in a single row. With reverse both are read back to front, so entry j
+// covers the last j lines of b instead.
+func lcsLengths(a, b []*LineInsight, reverse bool) []int32 {
+	row := make([]int32, len(b)+1)
+	for i := range a {
+		line := a[i]
+		if reverse {
+			line = a[len(a)-1-i]
+		}
+		diagonal := int32(0) // row[j-1] before this pass
+		for j := 1; j <= len(b); j++ {
+			other := b[j-1]
+			if reverse {
+				other = b[len(b)-j]
+			}
+			above := row[j]
+			if line.Content == other.Content {
+				row[j] = diagonal + 1
+			} else if row[j-1] > above {
+				row[j] = row[j-1]
+			}
+			diagonal = above
+		}
+	}
+	return row
+}
+
+// lineInsights tokenizes content client-side and maps the tokens to its lines
+func lineInsights(content string, apiClient api.TokenCounter) ([]*LineInsight, error) {
+	tokens, err := apiClient.ExtractTokensClientSide(content)
+	if err != nil {
+		return nil, err
+	}
+	return mapTokensToLines(content, strings.Split(content, "\n"), tokens), nil
+}
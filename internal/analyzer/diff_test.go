@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// insightsOf returns one LineInsight per line with a token per byte
+func insightsOf(lines []string) []*LineInsight {
+	insights := make([]*LineInsight, len(lines))
+	for i, line := range lines {
+		insights[i] = &LineInsight{LineNumber: i + 1, Content: line, Tokens: len(line)}
+	}
+	return insights
+}
+
+// applyDeltas rebuilds the new version from the old one and the deltas, and returns the
+// number of unchanged lines
+func applyDeltas(t *testing.T, oldLines []string, deltas []*LineDelta) ([]string, int) {
+	t.Helper()
+	var result []string
+	next, kept := 1, 0 // Next old line to copy
+	for _, delta := range deltas {
+		if delta.OldLine != 0 {
+			for ; next < delta.OldLine; next++ {
+				result = append(result, oldLines[next-1])
+				kept++
+			}
+			if oldLines[delta.OldLine-1] != delta.Content {
+				t.Fatalf("removed line %d is %q, delta says %q", delta.OldLine, oldLines[delta.OldLine-1], delta.Content)
+			}
+			next++
+			continue
+		}
+		// Unchanged lines fill the new version up to the added line
+		for ; len(result) < delta.NewLine-1; next++ {
+			result = append(result, oldLines[next-1])
+			kept++
+		}
+		if len(result) != delta.NewLine-1 {
+			t.Fatalf("added line %d out of order", delta.NewLine)
+		}
+		result = append(result, delta.Content)
+	}
+	for ; next <= len(oldLines); next++ {
+		result = append(result, oldLines[next-1])
+		kept++
+	}
+	return result, kept
+}
+
+// naiveLCS is the textbook quadratic-space LCS length
+func naiveLCS(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				table[i][j] = table[i+1][j+1] + 1
+			case table[i+1][j] >= table[i][j+1]:
+				table[i][j] = table[i+1][j]
+			default:
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	return table[0][0]
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldContent string
+		newContent string
+		want       []string // "-N content" for removed, "+N content" for added
+	}{
+		{"unchanged", "a\nb\nc", "a\nb\nc", nil},
+		{"added line", "a\nc", "a\nb\nc", []string{"+2 b"}},
+		{"removed line", "a\nb\nc", "a\nc", []string{"-2 b"}},
+		{"replaced line", "a\nb\nc", "a\nx\nc", []string{"-2 b", "+2 x"}},
+		{"appended lines", "a", "a\nb\nc", []string{"+2 b", "+3 c"}},
+		{"moved line", "a\nb\nc\nd", "b\nc\nd\na", []string{"-1 a", "+4 a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas, err := DiffLines(tt.oldContent, tt.newContent, api.NewOfflineCounter())
+			if err != nil {
+				t.Fatalf("DiffLines: %v", err)
+			}
+			var got []string
+			for _, delta := range deltas {
+				if delta.OldLine != 0 {
+					got = append(got, fmt.Sprintf("-%d %s", delta.OldLine, delta.Content))
+				} else {
+					got = append(got, fmt.Sprintf("+%d %s", delta.NewLine, delta.Content))
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("DiffLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiffFindsLongestCommonSubsequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		oldLines, newLines := randomLines(), randomLines()
+		diff := &lineDiff{}
+		diff.diff(insightsOf(oldLines), insightsOf(newLines))
+
+		rebuilt, kept := applyDeltas(t, oldLines, diff.deltas)
+		if strings.Join(rebuilt, "\n") != strings.Join(newLines, "\n") {
+			t.Fatalf("diff of %q -> %q rebuilds %q", oldLines, newLines, rebuilt)
+		}
+		if want := naiveLCS(oldLines, newLines); kept != want {
+			t.Fatalf("diff of %q -> %q keeps %d lines, want %d", oldLines, newLines, kept, want)
+		}
+	}
+}
+
+func TestLineDiffLinearMemory(t *testing.T) {
+	const n = 4000
+	oldLines := make([]string, n)
+	newLines := make([]string, n)
+	for i := range oldLines {
+		oldLines[i] = fmt.Sprintf("old %d", i)
+		newLines[i] = fmt.Sprintf("new %d", i)
+	}
+	oldInsights, newInsights := insightsOf(oldLines), insightsOf(newLines)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diff := &lineDiff{}
+	diff.diff(oldInsights, newInsights)
+	runtime.ReadMemStats(&after)
+
+	if len(diff.deltas) != 2*n {
+		t.Fatalf("got %d deltas, want %d", len(diff.deltas), 2*n)
+	}
+	// A full LCS table would take n*n*4 = 64 MB
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 8<<20 {
+		t.Errorf("diff allocated %d bytes, want linear memory", allocated)
+	}
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// BudgetFit describes where content must be cut to fit a token budget. Offsets are in bytes.
+type BudgetFit struct {
+	Budget         int
+	TotalTokens    int
+	TotalLines     int
+	Fits           bool // The whole content is within the budget
+	CutOffset      int  // End of the last token within the budget (the content length if it fits)
+	CutLine        int  // 1-based line the last token within the budget starts on
+	LineOffset     int  // End of the last line that fits completely
+	FittingLines   int  // Complete lines before LineOffset
+	FittingTokens  int  // Tokens before LineOffset
+	LeftoverTokens int  // Tokens past the budget
+	LeftoverLines  int  // Lines that don't fit completely
+}
+
+// FitBudget finds where the cumulative count of the client-side tokens of content reaches
+// budget, and the last line boundary before it. budget must be positive.
+func FitBudget(content string, tokens []api.Token, budget int) *BudgetFit {
+	fit := &BudgetFit{
+		Budget:      budget,
+		TotalTokens: len(tokens),
+		TotalLines:  countLines(content),
+		Fits:        len(tokens) <= budget,
+		CutOffset:   len(content),
+	}
+	if fit.Fits {
+		fit.CutLine = fit.TotalLines
+		fit.LineOffset = len(content)
+	} else {
+		last := tokens[budget-1]
+		fit.CutOffset = last.Position + len(last.Text)
+		fit.CutLine = strings.Count(content[:last.Position], "\n") + 1
+		fit.LineOffset = strings.LastIndex(content[:fit.CutOffset], "\n") + 1
+	}
+	fit.FittingLines = countLines(content[:fit.LineOffset])
+	for _, token := range tokens {
+		if token.Position+len(token.Text) <= fit.LineOffset {
+			fit.FittingTokens++
+		}
+	}
+	fit.LeftoverTokens = fit.TotalTokens - min(budget, fit.TotalTokens)
+	fit.LeftoverLines = fit.TotalLines - fit.FittingLines
+	return fit
+}
+
+// countLines returns the number of lines in content, not counting an empty final line after a
+// trailing newline
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// tokensOf returns consecutive tokens with the given texts, positioned as they appear in order
+func tokensOf(texts ...string) (string, []api.Token) {
+	content := ""
+	tokens := make([]api.Token, 0, len(texts))
+	for _, text := range texts {
+		tokens = append(tokens, api.Token{Text: text, Position: len(content)})
+		content += text
+	}
+	return content, tokens
+}
+
+func TestFitBudget(t *testing.T) {
+	// 8 tokens over 3 lines: "aa bb\n" (bytes 0-5), "cc dd\n" (6-11), "ee\n" (12-14)
+	content, tokens := tokensOf("aa", " bb", "\n", "cc", " dd", "\n", "ee", "\n")
+
+	tests := []struct {
+		name   string
+		budget int
+		want   BudgetFit
+	}{
+		{
+			name:   "budget ends mid-line",
+			budget: 5,
+			want: BudgetFit{Budget: 5, TotalTokens: 8, TotalLines: 3, CutOffset: 11, CutLine: 2,
+				LineOffset: 6, FittingLines: 1, FittingTokens: 3, LeftoverTokens: 3, LeftoverLines: 2},
+		},
+		{
+			name:   "budget ends on a newline",
+			budget: 6,
+			want: BudgetFit{Budget: 6, TotalTokens: 8, TotalLines: 3, CutOffset: 12, CutLine: 2,
+				LineOffset: 12, FittingLines: 2, FittingTokens: 6, LeftoverTokens: 2, LeftoverLines: 1},
+		},
+		{
+			name:   "first line doesn't fit",
+			budget: 1,
+			want: BudgetFit{Budget: 1, TotalTokens: 8, TotalLines: 3, CutOffset: 2, CutLine: 1,
+				LineOffset: 0, FittingLines: 0, FittingTokens: 0, LeftoverTokens: 7, LeftoverLines: 3},
+		},
+		{
+			name:   "whole file fits",
+			budget: 8,
+			want: BudgetFit{Budget: 8, TotalTokens: 8, TotalLines: 3, Fits: true, CutOffset: 15, CutLine: 3,
+				LineOffset: 15, FittingLines: 3, FittingTokens: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FitBudget(content, tokens, tt.budget); *got != tt.want {
+				t.Errorf("FitBudget(%d) = %+v, want %+v", tt.budget, *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// FixResult holds content cleaned by Fix and what was changed
+type FixResult struct {
+	Content           string
+	InvisibleRemoved  int // Zero-width and other invisible characters stripped
+	BiDiRemoved       int // Bidirectional control characters stripped
+	NormalizedLines   int // Lines rewritten to NFC
+	BlankLinesRemoved int // Blank lines dropped from consecutive runs
+}
+
+// Changed reports whether Fix modified the content
+func (r *FixResult) Changed() bool {
+	return r.InvisibleRemoved+r.BiDiRemoved+r.NormalizedLines+r.BlankLinesRemoved > 0
+}
+
+// Fix applies the issue fixes that are safe and deterministic: it strips invisible and
+// bidirectional control characters, normalizes each line to NFC and collapses runs of blank
+// lines to one. Zero-width joiners and non-joiners between two non-ASCII letters or symbols
+// (emoji sequences, Persian and Indic scripts) are kept. Emoji, OOV strings, confusables and
+// other issues that need judgment are left unchanged. Line endings are preserved.
+func Fix(content string) *FixResult {
+	result := &FixResult{}
+
+	lines := strings.Split(content, "\n")
+	fixed := make([]string, 0, len(lines))
+	previousBlank := false
+	for i, line := range lines {
+		line = result.stripControls(line)
+
+		if nfc := norm.NFC.String(line); nfc != line {
+			line = nfc
+			result.NormalizedLines++
+		}
+
+		// The final element after a trailing newline is not a line of its own
+		blank := strings.TrimSpace(line) == "" && i < len(lines)-1
+		if blank && previousBlank {
+			result.BlankLinesRemoved++
+			continue
+		}
+		previousBlank = blank
+		fixed = append(fixed, line)
+	}
+
+	result.Content = strings.Join(fixed, "\n")
+	return result
+}
+
+// stripControls removes invisible and bidirectional control characters from line
+func (r *FixResult) stripControls(line string) string {
+	runes := []rune(line)
+	var sb strings.Builder
+	sb.Grow(len(line))
+	for i, ch := range runes {
+		if _, isBiDi := bidiControlCharMap[ch]; isBiDi {
+			r.BiDiRemoved++
+			continue
+		}
+		if _, isInvisible := zeroWidthCharMap[ch]; isInvisible && !isJoinerInContext(runes, i) {
+			r.InvisibleRemoved++
+			continue
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String()
+}
+
+// isJoinerInContext reports whether runes[i] is a ZWJ or ZWNJ between two non-ASCII letters or
+// symbols, where it changes how the text renders rather than hiding anything
+func isJoinerInContext(runes []rune, i int) bool {
+	if runes[i] != 0x200D && runes[i] != 0x200C {
+		return false
+	}
+	if i == 0 || i == len(runes)-1 {
+		return false
+	}
+	return isJoinable(runes[i-1]) && isJoinable(runes[i+1])
+}
+
+// isJoinable reports whether r is a non-ASCII letter, mark or symbol. Variation selectors are
+// marks and skin tone modifiers are symbols, so emoji sequences qualify.
+func isJoinable(r rune) bool {
+	return r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsSymbol(r))
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFixGolden runs Fix on each testdata/fix/<name>.input.txt and compares the result with
+// <name>.golden.txt
+func TestFixGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "fix", "*.input.txt"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no fix fixtures found: %v", err)
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".input.txt")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", "fix", name+".golden.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Fix(string(content))
+			if result.Content != string(want) {
+				t.Errorf("Fix(%q) =\n%q\nwant\n%q", content, result.Content, want)
+			}
+			if result.Changed() != (string(content) != string(want)) {
+				t.Errorf("Changed() = %v for %+v", result.Changed(), result)
+			}
+			// Fixing is idempotent
+			if again := Fix(result.Content); again.Changed() {
+				t.Errorf("second Fix changed the content again: %+v", again)
+			}
+		})
+	}
+}
+
+func TestFixCounts(t *testing.T) {
+	result := Fix("a\u200b\u200bb\u202e\ncafe\u0301\n\n\n\nend\n")
+	if result.InvisibleRemoved != 2 || result.BiDiRemoved != 1 || result.NormalizedLines != 1 || result.BlankLinesRemoved != 2 {
+		t.Errorf("Fix counts = %+v, want 2 invisible, 1 BiDi, 1 normalized line and 2 blank lines", result)
+	}
+}
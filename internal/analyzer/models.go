@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"encoding/json"
+
 	"github.com/iota-uz/cc-token/internal/api"
 )
 
@@ -22,6 +24,7 @@ type Analysis struct {
 	QuickWins         []*Recommendation // Subset of recommendations that are easy + high impact
 	PotentialSavings  int
 	WasteTokens       int // Total tokens considered "waste"
+	SystemTokens      int // Client-side token count of the --system prompt, not included in TotalTokens
 }
 
 // LineInsight contains detailed metrics for a single line
@@ -57,147 +60,182 @@ type RepeatedPhrase struct {
 
 // Recommendation provides actionable optimization advice
 type Recommendation struct {
-	Title          string
-	Description    string
-	AffectedLines  []int
-	EstimatedSave  int
-	SavePercentage float64
-	Priority       int    // 1-3: 1=high, 2=medium, 3=low
-	Difficulty     string // "easy", "medium", "hard"
-	BeforeExample  string // Example of current state
-	AfterExample   string // Example of optimized state
-	IsQuickWin     bool   // Easy + high impact
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	AffectedLines  []int   `json:"affected_lines"`
+	EstimatedSave  int     `json:"estimated_save"`
+	SavePercentage float64 `json:"save_percentage"`
+	Priority       int     `json:"priority"`       // 1-3: 1=high, 2=medium, 3=low
+	Difficulty     string  `json:"difficulty"`     // "easy", "medium", "hard"
+	BeforeExample  string  `json:"before_example"` // Example of current state
+	AfterExample   string  `json:"after_example"`  // Example of optimized state
+	IsQuickWin     bool    `json:"is_quick_win"`   // Easy + high impact
 }
 
 // LLMSafetyAnalysis holds detected LLM-harmful token patterns
 type LLMSafetyAnalysis struct {
-	EmojiIssues         []*EmojiIssue
-	InvisibleCharIssues []*InvisibleCharIssue
-	NumberFormatIssues  []*NumberFormatIssue
-	OOVStringIssues     []*OOVStringIssue
-	BiDiControlIssues   []*BiDiControlIssue
-	ConfusableIssues    []*ConfusableIssue
-	EncodingIssues      []*EncodingIssue
-	NormalizationIssues []*NormalizationIssue
-	GlitchTokenIssues   []*GlitchTokenIssue
-	ContextIssues       []*ContextPlacementIssue
-	AmbiguityIssues     []*AmbiguityIssue
-	TotalIssues         int
-	TokensSaved         int // Estimated tokens that could be saved
-	ReliabilityScore    int // 0-100, higher is better
+	EmojiIssues         []*EmojiIssue            `json:"emoji_issues"`
+	InvisibleCharIssues []*InvisibleCharIssue    `json:"invisible_char_issues"`
+	NumberFormatIssues  []*NumberFormatIssue     `json:"number_format_issues"`
+	OOVStringIssues     []*OOVStringIssue        `json:"oov_string_issues"`
+	BiDiControlIssues   []*BiDiControlIssue      `json:"bidi_control_issues"`
+	ConfusableIssues    []*ConfusableIssue       `json:"confusable_issues"`
+	EncodingIssues      []*EncodingIssue         `json:"encoding_issues"`
+	NormalizationIssues []*NormalizationIssue    `json:"normalization_issues"`
+	GlitchTokenIssues   []*GlitchTokenIssue      `json:"glitch_token_issues"`
+	ContextIssues       []*ContextPlacementIssue `json:"context_issues"`
+	AmbiguityIssues     []*AmbiguityIssue        `json:"ambiguity_issues"`
+	InjectionIssues     []*InjectionIssue        `json:"injection_issues"`
+	SecretIssues        []*SecretIssue           `json:"secret_issues"`
+	TotalIssues         int                      `json:"total_issues"`
+	TokensSaved         int                      `json:"tokens_saved"`      // Estimated tokens that could be saved
+	ReliabilityScore    int                      `json:"reliability_score"` // 0-100, higher is better
 }
 
 // EmojiIssue represents emoji usage that affects tokenization
 type EmojiIssue struct {
-	Emoji       string
-	EmojiType   string // "standard", "zwj_sequence", "skin_tone", "flag"
-	LineNumber  int
-	Count       int
-	LineContent string
-	TokenCost   int // Estimated tokens this emoji costs
+	Emoji       string `json:"emoji"`
+	EmojiType   string `json:"emoji_type"` // "standard", "zwj_sequence", "skin_tone", "flag"
+	LineNumber  int    `json:"line_number"`
+	Count       int    `json:"count"`
+	LineContent string `json:"line_content"`
+	TokenCost   int    `json:"token_cost"` // Estimated tokens this emoji costs
 }
 
 // InvisibleCharIssue represents zero-width or control characters
 type InvisibleCharIssue struct {
-	CharType   string // "zwsp", "zwj", "lrm", "rlm", "zwnj", "shy", "bom"
-	LineNumber int
-	Position   int
-	Context    string // Surrounding text for context
-	Count      int
-	IsEvasion  bool // Likely being used for prompt injection
+	CharType   string `json:"char_type"` // "zwsp", "zwj", "lrm", "rlm", "zwnj", "shy", "bom"
+	LineNumber int    `json:"line_number"`
+	Position   int    `json:"position"` // Byte offset within the line
+	Context    string `json:"context"`  // Surrounding text for context
+	Count      int    `json:"count"`
+	IsEvasion  bool   `json:"is_evasion"` // Likely being used for prompt injection
 }
 
-// NumberFormatIssue represents unformatted large numbers
+// NumberFormatIssue represents unformatted large numbers and numbers glued to units or
+// currency symbols
 type NumberFormatIssue struct {
-	Number       string
-	IsFormatted  bool
-	LineNumber   int
-	LineContent  string
-	TokenCost    int
-	Suggestion   string
-	SaveEstimate int
+	Kind         string `json:"kind"` // "unformatted" or "unit_adjacency"
+	Number       string `json:"number"`
+	IsFormatted  bool   `json:"is_formatted"`
+	LineNumber   int    `json:"line_number"`
+	LineContent  string `json:"line_content"`
+	TokenCost    int    `json:"token_cost"`
+	Suggestion   string `json:"suggestion"`
+	SaveEstimate int    `json:"save_estimate"`
 }
 
 // OOVStringIssue represents out-of-vocabulary strings
 type OOVStringIssue struct {
-	String         string
-	StringType     string // "url", "uuid", "hash", "id", "token", "other"
-	LineNumber     int
-	TokenCount     int
-	Context        string
-	Recommendation string
+	String         string `json:"string"`
+	StringType     string `json:"string_type"` // "url", "uuid", "hash", "id", "token", "other"
+	LineNumber     int    `json:"line_number"`
+	TokenCount     int    `json:"token_count"`
+	Context        string `json:"context"`
+	Recommendation string `json:"recommendation"`
 }
 
 // BiDiControlIssue represents bidirectional text control characters (Trojan Source)
 type BiDiControlIssue struct {
-	ControlType    string // "lre", "rle", "pdf", "lro", "rlo", "lri", "rli", "fsi", "pdi"
-	LineNumber     int
-	Position       int
-	Context        string // Surrounding text for debugging
-	Count          int
-	IsTrojanSource bool // Detected as Trojan Source attack pattern
+	ControlType    string `json:"control_type"` // "lre", "rle", "pdf", "lro", "rlo", "lri", "rli", "fsi", "pdi"
+	LineNumber     int    `json:"line_number"`
+	Position       int    `json:"position"` // Byte offset within the line
+	Context        string `json:"context"`  // Surrounding text for debugging
+	Count          int    `json:"count"`
+	IsTrojanSource bool   `json:"is_trojan_source"` // Detected as Trojan Source attack pattern
 }
 
 // ConfusableIssue represents homoglyphs or visually similar characters
 type ConfusableIssue struct {
-	OriginalChar   rune
-	ConfusableChar rune
-	CharName       string // e.g., "Cyrillic 'а' vs Latin 'a'"
-	LineNumber     int
-	Position       int
-	Context        string
-	Count          int
-	IsMixedScript  bool // Mixed scripts in identifier/word
+	OriginalChar   rune   `json:"original_char"`
+	ConfusableChar rune   `json:"confusable_char"`
+	CharName       string `json:"char_name"` // e.g., "Cyrillic 'а' vs Latin 'a'"
+	LineNumber     int    `json:"line_number"`
+	Position       int    `json:"position"` // Byte offset within the line
+	Context        string `json:"context"`
+	Count          int    `json:"count"`
+	IsMixedScript  bool   `json:"is_mixed_script"` // Mixed scripts in identifier/word
+}
+
+// MarshalJSON renders the characters as strings rather than code point numbers
+func (c *ConfusableIssue) MarshalJSON() ([]byte, error) {
+	type plain ConfusableIssue
+	return json.Marshal(&struct {
+		*plain
+		OriginalChar   string `json:"original_char"`
+		ConfusableChar string `json:"confusable_char"`
+	}{
+		plain:          (*plain)(c),
+		OriginalChar:   string(c.OriginalChar),
+		ConfusableChar: string(c.ConfusableChar),
+	})
 }
 
 // EncodingIssue represents encoded or obfuscated text
 type EncodingIssue struct {
-	EncodingType string // "base64", "hex", "rot13", "leetspeak"
-	EncodedText  string
-	DecodedText  string // If decodable
-	LineNumber   int
-	Position     int
-	Length       int
-	TokenCost    int
+	EncodingType string `json:"encoding_type"` // "base64", "hex", "rot13", "leetspeak"
+	EncodedText  string `json:"encoded_text"`
+	DecodedText  string `json:"decoded_text"` // If decodable
+	LineNumber   int    `json:"line_number"`
+	Position     int    `json:"position"` // Byte offset within the line
+	Length       int    `json:"length"`
+	TokenCost    int    `json:"token_cost"`
 }
 
 // NormalizationIssue represents non-normalized Unicode text
 type NormalizationIssue struct {
-	OriginalText   string
-	NormalizedText string
-	FormExpected   string // "NFC", "NFKC"
-	LineNumber     int
-	Position       int
-	IssueType      string // "composed_decomposed", "compatibility_variant"
+	OriginalText   string `json:"original_text"`
+	NormalizedText string `json:"normalized_text"`
+	FormExpected   string `json:"form_expected"` // "NFC", "NFKC"
+	LineNumber     int    `json:"line_number"`
+	Position       int    `json:"position"`   // Byte offset within the line
+	IssueType      string `json:"issue_type"` // "composed_decomposed", "compatibility_variant"
 }
 
 // GlitchTokenIssue represents known problematic tokens
 type GlitchTokenIssue struct {
-	Token      string
-	TokenID    string // If available from tokenizer
-	LineNumber int
-	Position   int
-	KnownIssue string // Description of known problem
-	Severity   string // "critical", "high", "medium"
-	Context    string
+	Token      string `json:"token"`
+	TokenID    string `json:"token_id"` // If available from tokenizer
+	LineNumber int    `json:"line_number"`
+	Position   int    `json:"position"`    // Byte offset within the line
+	KnownIssue string `json:"known_issue"` // Description of known problem
+	Severity   string `json:"severity"`    // "critical", "high", "medium"
+	Context    string `json:"context"`
 }
 
 // ContextPlacementIssue represents long-context attention issues
 type ContextPlacementIssue struct {
-	TotalTokens        int
-	ImportantAtStart   bool
-	ImportantAtEnd     bool
-	ImportantInMiddle  bool // Lost-in-the-middle warning
-	RecommendedChanges string
+	TotalTokens        int    `json:"total_tokens"`
+	ImportantAtStart   bool   `json:"important_at_start"`
+	ImportantAtEnd     bool   `json:"important_at_end"`
+	ImportantInMiddle  bool   `json:"important_in_middle"` // Lost-in-the-middle warning
+	RecommendedChanges string `json:"recommended_changes"`
 }
 
 // AmbiguityIssue represents prompt ambiguity patterns
 type AmbiguityIssue struct {
-	Pattern     string // "conflicting_instructions", "nested_quotes", "role_confusion", "sycophantic_frame"
-	LineNumber  int
-	Description string
-	Example     string
-	Severity    string // "high", "medium", "low"
+	Pattern     string `json:"pattern"` // "conflicting_instructions", "nested_quotes", "role_confusion", "sycophantic_frame"
+	LineNumber  int    `json:"line_number"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+	Severity    string `json:"severity"` // "high", "medium", "low"
+}
+
+// InjectionIssue represents a known prompt-injection trigger phrase
+type InjectionIssue struct {
+	Phrase     string `json:"phrase"`
+	LineNumber int    `json:"line_number"`
+	Context    string `json:"context"`
+	Severity   string `json:"severity"` // Always "high" for explicit injection phrases
+}
+
+// SecretIssue represents a credential such as an API key, access token or private key
+type SecretIssue struct {
+	Kind       string `json:"kind"` // "anthropic_api_key", "aws_access_key", "private_key", "generic_secret", ...
+	LineNumber int    `json:"line_number"`
+	Position   int    `json:"position"` // Byte offset within the line
+	Masked     string `json:"masked"`   // The secret with all but its first characters hidden
+	Severity   string `json:"severity"` // Always "critical"
 }
 
 // ========================================
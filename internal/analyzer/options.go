@@ -0,0 +1,204 @@
+package analyzer
+
+// Options controls which detectors AnalyzeFile runs and how sensitive they are
+type Options struct {
+	EnableDetectors  []string   // Run only these detectors, by Name(); empty runs all of them
+	DisableDetectors []string   // Skip these detectors, by Name()
+	Thresholds       Thresholds // Detection thresholds; zero fields use the defaults
+}
+
+// Thresholds tunes how sensitive the pattern detectors and recommendations are
+type Thresholds struct {
+	HighRatio           float64 // Multiple of the file's average token/char ratio above which a line is flagged
+	MinRepetitions      int     // Occurrences before a phrase counts as repeated
+	MinURLLength        int     // Length above which a repeated URL is worth a reference link
+	MinConsecutiveEmpty int     // Consecutive empty lines before the run is flagged
+	LongLineLength      int     // Characters above which a line is long
+	LongWordLength      int     // Characters above which an unbroken word is long
+	InlineBlobLength    int     // Characters above which an inline JSON/XML block is large
+	MinBase64Entropy    float64 // Bits per character below which a Base64-looking string is not reported
+	MinTableRows        int     // Data rows above which a Markdown table is worth converting
+	FormattingRatio     float64 // Fraction of tokens spent on formatting above which simplification is recommended
+}
+
+// DefaultThresholds returns the thresholds used when none are configured
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		HighRatio:           highRatioThreshold,
+		MinRepetitions:      defaultMinRepetitions,
+		MinURLLength:        minURLLengthForOptimization,
+		MinConsecutiveEmpty: minConsecutiveEmptyLines,
+		LongLineLength:      defaultLongLineThreshold,
+		LongWordLength:      defaultLongWordLength,
+		InlineBlobLength:    defaultInlineBlobLength,
+		MinBase64Entropy:    defaultMinBase64Entropy,
+		MinTableRows:        defaultMinTableRows,
+		FormattingRatio:     defaultFormattingRatioThreshold,
+	}
+}
+
+// withDefaults fills zero (or negative) thresholds with their defaults
+func (t Thresholds) withDefaults() Thresholds {
+	defaults := DefaultThresholds()
+	if t.HighRatio <= 0 {
+		t.HighRatio = defaults.HighRatio
+	}
+	if t.MinRepetitions <= 0 {
+		t.MinRepetitions = defaults.MinRepetitions
+	}
+	if t.MinURLLength <= 0 {
+		t.MinURLLength = defaults.MinURLLength
+	}
+	if t.MinConsecutiveEmpty <= 0 {
+		t.MinConsecutiveEmpty = defaults.MinConsecutiveEmpty
+	}
+	if t.LongLineLength <= 0 {
+		t.LongLineLength = defaults.LongLineLength
+	}
+	if t.LongWordLength <= 0 {
+		t.LongWordLength = defaults.LongWordLength
+	}
+	if t.InlineBlobLength <= 0 {
+		t.InlineBlobLength = defaults.InlineBlobLength
+	}
+	if t.MinBase64Entropy <= 0 {
+		t.MinBase64Entropy = defaults.MinBase64Entropy
+	}
+	if t.MinTableRows <= 0 {
+		t.MinTableRows = defaults.MinTableRows
+	}
+	if t.FormattingRatio <= 0 {
+		t.FormattingRatio = defaults.FormattingRatio
+	}
+	return t
+}
+
+// SecurityDetectors are the detectors for characters and phrases that can hide or smuggle
+// instructions, selected by --only-security
+var SecurityDetectors = []string{
+	"bidi_control",
+	"confusables",
+	"encoding",
+	"injection_phrase",
+	"invisible_char",
+	"normalization",
+	"secret",
+}
+
+// defaultDetectors returns a fresh instance of every detector configured with t, in
+// registration order
+func defaultDetectors(t Thresholds) []Detector {
+	return []Detector{
+		// LLM Safety detectors (priorities 1-11)
+		NewEmojiDetector(),
+		NewInvisibleCharDetector(),
+		NewNumberFormattingDetector(),
+		NewOOVStringsDetector(),
+		NewBiDiControlDetector(),
+		NewConfusablesDetector(),
+		NewEncodingDetector(t.MinBase64Entropy),
+		NewNormalizationDetector(),
+		NewGlitchTokenDetector(),
+		NewContextPlacementDetector(),
+		NewPromptAmbiguityDetector(),
+		NewInjectionPhraseDetector(nil),
+		NewSecretDetector(),
+		// Pattern detectors (priorities 12-19)
+		NewURLDetector(),
+		NewConsecutiveEmptyDetector(t.MinConsecutiveEmpty),
+		NewLongLineDetector(t.LongLineLength),
+		NewRepeatedPhraseDetector(t.MinRepetitions),
+		NewLineEndingDetector(),
+		NewLongWordDetector(t.LongWordLength),
+		NewInlineBlobDetector(t.InlineBlobLength),
+		NewMarkdownTableDetector(t.MinTableRows),
+	}
+}
+
+// DetectorNames returns the names of all detectors, in registration order
+func DetectorNames() []string {
+	detectors := defaultDetectors(DefaultThresholds())
+	names := make([]string, 0, len(detectors))
+	for _, detector := range detectors {
+		names = append(names, detector.Name())
+	}
+	return names
+}
+
+// IsDetectorName reports whether name matches a detector's Name()
+func IsDetectorName(name string) bool {
+	for _, known := range DetectorNames() {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOptions builds detector options from the --enable-detector, --disable-detector and
+// --only-security flags. The security preset adds to any explicitly enabled detectors.
+func NewOptions(enable, disable []string, onlySecurity bool) Options {
+	opts := Options{EnableDetectors: enable, DisableDetectors: disable}
+	if onlySecurity {
+		opts.EnableDetectors = append(append([]string{}, enable...), SecurityDetectors...)
+	}
+	return opts
+}
+
+// selectDetectors filters detectors by the enable and disable lists
+func (o Options) selectDetectors(detectors []Detector) []Detector {
+	enabled := make(map[string]bool, len(o.EnableDetectors))
+	for _, name := range o.EnableDetectors {
+		enabled[name] = true
+	}
+	disabled := make(map[string]bool, len(o.DisableDetectors))
+	for _, name := range o.DisableDetectors {
+		disabled[name] = true
+	}
+
+	selected := make([]Detector, 0, len(detectors))
+	for _, detector := range detectors {
+		if len(enabled) > 0 && !enabled[detector.Name()] {
+			continue
+		}
+		if disabled[detector.Name()] {
+			continue
+		}
+		selected = append(selected, detector)
+	}
+	return selected
+}
+
+// safetyIssueCounts maps each LLM safety detector to the number of issues it reported
+var safetyIssueCounts = map[string]func(*LLMSafetyAnalysis) int{
+	"emoji":             func(s *LLMSafetyAnalysis) int { return len(s.EmojiIssues) },
+	"invisible_char":    func(s *LLMSafetyAnalysis) int { return len(s.InvisibleCharIssues) },
+	"number_formatting": func(s *LLMSafetyAnalysis) int { return len(s.NumberFormatIssues) },
+	"oov_strings":       func(s *LLMSafetyAnalysis) int { return len(s.OOVStringIssues) },
+	"bidi_control":      func(s *LLMSafetyAnalysis) int { return len(s.BiDiControlIssues) },
+	"confusables":       func(s *LLMSafetyAnalysis) int { return len(s.ConfusableIssues) },
+	"encoding":          func(s *LLMSafetyAnalysis) int { return len(s.EncodingIssues) },
+	"normalization":     func(s *LLMSafetyAnalysis) int { return len(s.NormalizationIssues) },
+	"glitch_token":      func(s *LLMSafetyAnalysis) int { return len(s.GlitchTokenIssues) },
+	"context_placement": func(s *LLMSafetyAnalysis) int { return len(s.ContextIssues) },
+	"prompt_ambiguity":  func(s *LLMSafetyAnalysis) int { return len(s.AmbiguityIssues) },
+	"injection_phrase":  func(s *LLMSafetyAnalysis) int { return len(s.InjectionIssues) },
+	"secret":            func(s *LLMSafetyAnalysis) int { return len(s.SecretIssues) },
+}
+
+// IsSafetyDetectorName reports whether name is an LLM safety detector, i.e. one whose
+// issues are listed in LLMSafetyAnalysis
+func IsSafetyDetectorName(name string) bool {
+	_, ok := safetyIssueCounts[name]
+	return ok
+}
+
+// IssueCount returns the number of issues the named LLM safety detector reported
+// (0 for unknown or non-safety detectors)
+func (s *LLMSafetyAnalysis) IssueCount(detector string) int {
+	count, ok := safetyIssueCounts[detector]
+	if !ok || s == nil {
+		return 0
+	}
+	return count(s)
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisabledDetectorReportsNoIssues(t *testing.T) {
+	content := "Launch day \U0001F680 is here.\nApprove\u200b the refund.\n"
+
+	tests := []struct {
+		name          string
+		opts          Options
+		wantEmoji     bool
+		wantInvisible bool
+	}{
+		{"all detectors", Options{}, true, true},
+		{"invisible_char disabled", Options{DisableDetectors: []string{"invisible_char"}}, true, false},
+		{"only emoji enabled", Options{EnableDetectors: []string{"emoji"}}, true, false},
+		{"only security", NewOptions(nil, nil, true), false, true},
+		{"only security without invisible_char", NewOptions(nil, []string{"invisible_char"}, true), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safety := analyzeOffline(t, content, tt.opts).LLMSafetyAnalysis
+			if got := len(safety.EmojiIssues) > 0; got != tt.wantEmoji {
+				t.Errorf("emoji issues = %d, want any: %v", len(safety.EmojiIssues), tt.wantEmoji)
+			}
+			if got := len(safety.InvisibleCharIssues) > 0; got != tt.wantInvisible {
+				t.Errorf("invisible character issues = %d, want any: %v", len(safety.InvisibleCharIssues), tt.wantInvisible)
+			}
+		})
+	}
+}
+
+func TestLoweredHighRatioFlagsMoreLines(t *testing.T) {
+	content := strings.Repeat("The quick brown fox jumps over the lazy dog near the river bank.\n", 10) +
+		"Order 4821-7730-1954 shipped on 2024-03-18 to warehouse B-17.\n" +
+		"Contact support@example.com or call +1 (415) 555-0199 today.\n" +
+		"id=9f86d081884c7d65 sha=5e884898da28047151d0e56f8dc62927\n"
+
+	defaults := analyzeOffline(t, content, Options{}).Patterns.HighRatioLines
+	lowered := analyzeOffline(t, content, Options{Thresholds: Thresholds{HighRatio: 1.05}}).Patterns.HighRatioLines
+	if len(lowered) <= len(defaults) {
+		t.Errorf("HighRatio 1.05 flagged %d lines, want more than the %d flagged at the default %.1f", len(lowered), len(defaults), highRatioThreshold)
+	}
+}
@@ -9,6 +9,20 @@ const (
 	minConsecutiveEmptyLines = 2
 	// Default long line threshold in characters
 	defaultLongLineThreshold = 120
+	// Default length in characters above which a word is considered excessively long
+	defaultLongWordLength = 40
+	// Default length in characters above which an inline JSON/XML block is considered large
+	defaultInlineBlobLength = 300
+	// Minimum number of tags for an inline block to count as structured XML rather than markup
+	minInlineXMLTags = 4
+	// Minimum words in a repeated phrase
+	minPhraseWords = 3
+	// Minimum characters in a repeated phrase, to skip short filler like "it is a"
+	minPhraseChars = 15
+	// Maximum characters in a repeated phrase; longer repeats are reported in pieces
+	maxPhraseChars = 200
+	// Default number of data rows above which a Markdown table is worth converting
+	defaultMinTableRows = 10
 )
 
 // AdvancedPatterns holds detected advanced patterns
@@ -16,6 +30,10 @@ type AdvancedPatterns struct {
 	URLs             []*URLPattern
 	ConsecutiveEmpty []*ConsecutiveEmptyLines
 	LongLines        []*LongLine
+	LineEndings      []*LineEndingMix
+	LongWords        []*LongWord
+	InlineBlobs      []*InlineBlob
+	MarkdownTables   []*MarkdownTable
 }
 
 // URLPattern represents a detected URL
@@ -37,7 +55,45 @@ type ConsecutiveEmptyLines struct {
 // LongLine represents a line that's unusually long
 type LongLine struct {
 	LineNumber int
-	Length     int
+	Length     int // Characters (runes) in the line
+	WrapColumn int // 1-based column of the first character past the threshold
 	Tokens     int
 	Content    string
 }
+
+// LineEndingMix represents a file that mixes LF and CRLF line endings
+type LineEndingMix struct {
+	LFCount       int
+	CRLFCount     int
+	Dominant      string // "LF" or "CRLF", whichever occurs more often
+	MinorityLines []int  // Lines terminated with the less common ending
+}
+
+// InlineBlob represents a large serialized JSON or XML payload embedded in prose
+type InlineBlob struct {
+	Kind      string // "json" or "xml"
+	StartLine int
+	EndLine   int
+	Length    int // Characters
+	TokenCost int
+	Preview   string
+}
+
+// MarkdownTable represents a large GitHub-flavored Markdown table
+type MarkdownTable struct {
+	StartLine        int
+	EndLine          int
+	Rows             int // Data rows, excluding the header and delimiter rows
+	Columns          int
+	TokenCost        int // Tokens in the whole table
+	FormattingTokens int // Tokens that are only pipes, delimiter dashes, colons or padding
+}
+
+// LongWord represents an unbroken word run that is unusually long
+type LongWord struct {
+	Word       string
+	Length     int
+	LineNumber int
+	TokenCost  int
+	text       string // The untruncated word, for matching against OOV strings
+}
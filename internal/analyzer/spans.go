@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"unicode/utf8"
+
+	"github.com/mtibben/confusables"
+)
+
+// IssueSpan is a byte range of content flagged by a character-level detector
+type IssueSpan struct {
+	Start int
+	End   int
+	Kind  string // "invisible", "emoji" or "confusable"
+	Label string // Character type or name, e.g. "zwsp" or "Cyrillic 'а' vs Latin 'a'"
+}
+
+// FindIssueSpans returns the byte ranges of invisible characters, emoji and confusables in
+// content, in order. It applies the same checks as the invisible, emoji and confusables
+// detectors but keeps every occurrence instead of merging them per line, so issues can be
+// mapped onto individual tokens.
+func FindIssueSpans(content string) []IssueSpan {
+	var spans []IssueSpan
+
+	for pos, r := range content {
+		end := pos + utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			end = pos + 1
+		}
+
+		switch {
+		case zeroWidthCharMap[r] != "":
+			spans = append(spans, IssueSpan{Start: pos, End: end, Kind: "invisible", Label: zeroWidthCharMap[r]})
+		case isEmoji(r):
+			spans = append(spans, IssueSpan{Start: pos, End: end, Kind: "emoji", Label: string(r)})
+		case r >= 128:
+			original := string(r)
+			skeleton := confusables.Skeleton(original)
+			if skeleton == original {
+				continue
+			}
+			target, _ := utf8.DecodeRuneInString(skeleton)
+			spans = append(spans, IssueSpan{Start: pos, End: end, Kind: "confusable", Label: getConfusableCharNameHelper(r, target)})
+		}
+	}
+
+	return spans
+}
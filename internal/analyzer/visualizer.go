@@ -32,14 +32,15 @@ type DensityBlock struct {
 
 // PercentileStats holds statistical distribution of tokens per line
 type PercentileStats struct {
-	Min          int
-	Percentile25 int
-	Median       int
-	Percentile75 int
-	Percentile90 int
-	Percentile95 int
-	Max          int
-	Top10Pct     float64 // Percentage of total tokens in top 10% of lines
+	Min          int     `json:"min"`
+	Percentile25 int     `json:"p25"`
+	Median       int     `json:"median"`
+	Percentile75 int     `json:"p75"`
+	Percentile90 int     `json:"p90"`
+	Percentile95 int     `json:"p95"`
+	Max          int     `json:"max"`
+	Mean         float64 `json:"mean"`
+	Top10Pct     float64 `json:"top_10_pct"` // Percentage of total tokens in top 10% of values
 }
 
 // RenderTokenDensityMap creates ASCII visualization of token distribution
@@ -141,16 +142,25 @@ func (m *TokenDensityMap) FormatHeatmap() string {
 
 // CalculatePercentiles computes statistical distribution of tokens per line
 func CalculatePercentiles(insights []*LineInsight) *PercentileStats {
-	if len(insights) == 0 {
+	tokenCounts := make([]int, len(insights))
+	for i, insight := range insights {
+		tokenCounts[i] = insight.Tokens
+	}
+	return PercentilesOf(tokenCounts)
+}
+
+// PercentilesOf computes statistical distribution of arbitrary token counts (e.g. tokens per file)
+func PercentilesOf(values []int) *PercentileStats {
+	if len(values) == 0 {
 		return &PercentileStats{}
 	}
 
-	// Extract and sort token counts
-	tokenCounts := make([]int, len(insights))
+	// Copy and sort token counts so the caller's slice is left untouched
+	tokenCounts := make([]int, len(values))
+	copy(tokenCounts, values)
 	totalTokens := 0
-	for i, insight := range insights {
-		tokenCounts[i] = insight.Tokens
-		totalTokens += insight.Tokens
+	for _, v := range tokenCounts {
+		totalTokens += v
 	}
 	sortInts(tokenCounts)
 
@@ -163,10 +173,11 @@ func CalculatePercentiles(insights []*LineInsight) *PercentileStats {
 		Percentile75: percentile(tokenCounts, 0.75),
 		Percentile90: percentile(tokenCounts, 0.90),
 		Percentile95: percentile(tokenCounts, 0.95),
+		Mean:         float64(totalTokens) / float64(len(tokenCounts)),
 	}
 
 	// Calculate top 10% concentration
-	top10Index := int(float64(len(insights)) * 0.9)
+	top10Index := int(float64(len(tokenCounts)) * 0.9)
 	top10Tokens := 0
 	for i := top10Index; i < len(tokenCounts); i++ {
 		top10Tokens += tokenCounts[i]
@@ -2,10 +2,12 @@ package api
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -14,72 +16,199 @@ import (
 )
 
 const (
-	countTokensURL = "https://api.anthropic.com/v1/messages/count_tokens"
-	apiVersion     = "2023-06-01"
-	defaultTimeout = 30 * time.Second
+	// DefaultBaseURL is the official Anthropic API endpoint
+	DefaultBaseURL  = "https://api.anthropic.com"
+	countTokensPath = "/v1/messages/count_tokens"
+	apiVersion      = "2023-06-01"
+	// PDFMediaType is the media type of PDF document blocks
+	PDFMediaType = "application/pdf"
 )
 
 // Client handles HTTP communication with Anthropic API and token encoding
 type Client struct {
 	apiKey     string
+	baseURL    string // API endpoint without a trailing slash, e.g. a gateway or proxy
 	httpClient *http.Client
 	encoding   *tiktoken.Encoding
+	maxRetries int
+	observer   func(RateLimit) // Called with the rate limit headers of each response, if set
+
+	// retryRateLimits is false when the caller retries 429 responses itself
+	retryRateLimits bool
 }
 
 // NewClient creates a new API client with the given API key and initializes the Claude tokenizer
 func NewClient(apiKey string) *Client {
-	// Initialize Claude tokenizer for client-side token extraction
+	return NewClientWithOptions(apiKey)
+}
+
+// NewClientWithOptions creates a new API client like NewClient, configured by opts
+func NewClientWithOptions(apiKey string, opts ...Option) *Client {
+	options := clientOptions{baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	httpClient := &http.Client{Timeout: DefaultTimeout}
+	if options.httpClient != nil {
+		// Copy so WithTimeout doesn't change a client the caller may share
+		copied := *options.httpClient
+		httpClient = &copied
+	}
+	if options.timeout > 0 {
+		httpClient.Timeout = options.timeout
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(options.baseURL, "/"),
+		httpClient: httpClient,
+		encoding:   newEncoding(),
+		maxRetries: DefaultMaxRetries,
+
+		retryRateLimits: true,
+	}
+}
+
+// newEncoding initializes the Claude tokenizer for client-side token extraction. It returns nil
+// if initialization fails: token visualization will not work but token counting will.
+func newEncoding() *tiktoken.Encoding {
 	codec, err := tiktoken.NewClaude()
 	if err != nil {
-		// Fallback to nil encoding if initialization fails
-		// Token visualization will not work but token counting will
 		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize Claude tokenizer codec: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Token visualization features will be unavailable.\n")
-		return &Client{
-			apiKey: apiKey,
-			httpClient: &http.Client{
-				Timeout: defaultTimeout,
-			},
-			encoding: nil,
-		}
+		return nil
 	}
 
 	encoding, err := tiktoken.NewEncoding(codec)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize tokenizer encoding: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Token visualization features will be unavailable.\n")
-		return &Client{
-			apiKey: apiKey,
-			httpClient: &http.Client{
-				Timeout: defaultTimeout,
-			},
-			encoding: nil,
-		}
-	}
-
-	return &Client{
-		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: defaultTimeout},
-		encoding:   encoding,
+		return nil
 	}
+	return encoding
 }
 
 // CountTokens calls the Anthropic API to count tokens in the given content using the specified model.
 // It returns the number of input tokens or an error if the API request fails.
 func (c *Client) CountTokens(content, model string) (int, error) {
-	reqBody := Request{
-		Model: model,
+	return c.CountTokensWithSystem(content, "", model)
+}
+
+// CountTokensWithSystem counts tokens for a user message sent together with a system prompt.
+// An empty system prompt is omitted from the request.
+func (c *Client) CountTokensWithSystem(content, system, model string) (int, error) {
+	return c.countRequest(Request{
+		Model:  model,
+		System: system,
+		Messages: []MessageInput{
+			{Role: "user", Content: TextContent(content)},
+		},
+	})
+}
+
+// CountPDFTokens counts tokens for a PDF sent as a document block in a user message, together
+// with an optional system prompt. The API extracts the text and page images itself.
+func (c *Client) CountPDFTokens(pdf []byte, system, model string) (int, error) {
+	return c.countBlock(base64Block("document", PDFMediaType, pdf), system, model)
+}
+
+// countBlock counts tokens for a user message made of a single content block
+func (c *Client) countBlock(block ContentBlock, system, model string) (int, error) {
+	return c.countRequest(Request{
+		Model:  model,
+		System: system,
 		Messages: []MessageInput{
-			{Role: "user", Content: content},
+			{Role: "user", Content: MessageContent{Blocks: []ContentBlock{block}}},
+		},
+	})
+}
+
+// base64Block builds a content block of the given type carrying data inline
+func base64Block(blockType, mediaType string, data []byte) ContentBlock {
+	return ContentBlock{
+		Type: blockType,
+		Source: &Base64Source{
+			Type:      "base64",
+			MediaType: mediaType,
+			Data:      base64.StdEncoding.EncodeToString(data),
 		},
 	}
+}
 
+// CountSystemTokens returns the tokens a system prompt adds to every request. The API requires
+// a user message, so the overhead is measured against a placeholder message counted with and
+// without the system prompt.
+func (c *Client) CountSystemTokens(system, model string) (int, error) {
+	with, err := c.CountTokensWithSystem(systemPlaceholderMessage, system, model)
+	if err != nil {
+		return 0, err
+	}
+	without, err := c.CountTokens(systemPlaceholderMessage, model)
+	if err != nil {
+		return 0, err
+	}
+	return with - without, nil
+}
+
+// systemPlaceholderMessage is the user message used when measuring system prompt overhead
+const systemPlaceholderMessage = "."
+
+// SetRateLimitObserver registers a function called with the request rate limit reported by
+// each API response. It must be safe for concurrent use; nil removes the observer.
+func (c *Client) SetRateLimitObserver(observe func(RateLimit)) {
+	c.observer = observe
+}
+
+// ValidateBaseURL checks that baseURL is an absolute http or https URL. Errors leave out the
+// URL's userinfo and query, which may hold credentials.
+func ValidateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid API base URL: not a URL")
+	}
+	shown := *u
+	shown.User, shown.RawQuery, shown.Fragment = nil, "", ""
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid API base URL %q: must be an absolute http or https URL", shown.String())
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid API base URL %q: must not have a query or fragment", shown.String())
+	}
+	return nil
+}
+
+// SetMaxRetries sets how many times transient failures (429, 5xx, network errors) are retried
+func (c *Client) SetMaxRetries(maxRetries int) {
+	c.maxRetries = maxRetries
+}
+
+// SetRetryRateLimits controls whether rate-limited (429) requests are retried. Disable it when
+// the caller retries them itself, e.g. through an adaptive limiter that must see each 429.
+func (c *Client) SetRetryRateLimits(retry bool) {
+	c.retryRateLimits = retry
+}
+
+// countRequest sends a token counting request and returns the number of input tokens,
+// retrying transient failures with exponential backoff. Other errors fail immediately.
+func (c *Client) countRequest(reqBody Request) (int, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", countTokensURL, bytes.NewReader(jsonData))
+	for attempt := 0; ; attempt++ {
+		tokens, err := c.sendCount(jsonData)
+		if err == nil || attempt >= c.maxRetries || !isRetryable(err) || (!c.retryRateLimits && IsRateLimited(err)) {
+			return tokens, err
+		}
+		time.Sleep(RetryDelay(attempt, err))
+	}
+}
+
+// sendCount performs a single token counting HTTP request
+func (c *Client) sendCount(jsonData []byte) (int, error) {
+	req, err := http.NewRequest("POST", c.baseURL+countTokensPath, bytes.NewReader(jsonData))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -90,16 +219,26 @@ func (c *Client) CountTokens(content, model string) (int, error) {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("API request failed: %w", err)
+		return 0, &networkError{err: err, timeout: c.httpClient.Timeout}
 	}
 	defer resp.Body.Close()
 
+	if c.observer != nil {
+		if rateLimit, ok := parseRateLimit(resp.Header); ok {
+			c.observer(rateLimit)
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, readErr := io.ReadAll(resp.Body)
 		if readErr != nil {
 			return 0, fmt.Errorf("API returned status %d (failed to read response body: %w)", resp.StatusCode, readErr)
 		}
-		return 0, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return 0, &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	var apiResp Response
@@ -113,42 +252,37 @@ func (c *Client) CountTokens(content, model string) (int, error) {
 // ExtractTokensClientSide uses the client-side Claude tokenizer to extract individual tokens
 // without making API calls. This is faster, cheaper, and works offline.
 func (c *Client) ExtractTokensClientSide(content string) ([]Token, error) {
-	if c.encoding == nil {
+	return extractTokens(c.encoding, content)
+}
+
+// extractTokens splits content into tokens with encoding, which is nil if the tokenizer
+// failed to initialize
+func extractTokens(encoding *tiktoken.Encoding, content string) ([]Token, error) {
+	if encoding == nil {
 		return nil, fmt.Errorf("tokenizer not initialized")
 	}
 
 	// Encode the content to get token IDs and token strings
-	_, tokenStrings, err := c.encoding.Encode(content, nil, nil)
+	_, tokenStrings, err := encoding.Encode(content, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode content: %w", err)
 	}
 
-	// Build Token structs with position and length information
+	// Token strings are the raw byte pieces of the content, in order, so each token starts
+	// where the previous one ended
 	tokens := make([]Token, 0, len(tokenStrings))
 	position := 0
-
 	for _, tokenText := range tokenStrings {
-		// Note: Token text might not match exactly in content due to BPE encoding (spaces, special chars)
-		remainingContent := content[position:]
-		idx := strings.Index(remainingContent, tokenText)
-
-		if idx == -1 {
-			// Token text doesn't appear literally in content (common with BPE encoding)
-			tokenLength := len(tokenText)
-			tokens = append(tokens, Token{
-				Text:     tokenText,
-				Position: position,
-				Length:   tokenLength,
-			})
-			position += tokenLength
-		} else {
-			tokens = append(tokens, Token{
-				Text:     tokenText,
-				Position: position + idx,
-				Length:   len(tokenText),
-			})
-			position += idx + len(tokenText)
-		}
+		tokens = append(tokens, Token{
+			Text:     tokenText,
+			Position: position,
+			Length:   len(tokenText),
+		})
+		position += len(tokenText)
+	}
+
+	if position != len(content) {
+		return nil, fmt.Errorf("tokenizer output covers %d of %d bytes", position, len(content))
 	}
 
 	return tokens, nil
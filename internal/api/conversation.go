@@ -0,0 +1,75 @@
+package api
+
+import "fmt"
+
+// RoleSystem is the pseudo-role used for the top-level system prompt in per-role counts
+const RoleSystem = "system"
+
+// Conversation is a system prompt plus a list of messages in the Messages API format
+type Conversation struct {
+	System   string         `json:"system,omitempty"`
+	Messages []MessageInput `json:"messages"`
+}
+
+// ConversationCount holds the API total for a conversation and per-role content subtotals
+type ConversationCount struct {
+	Total  int            // Exact API count, including per-message overhead
+	ByRole map[string]int // Offline content token counts keyed by role ("system", "user", "assistant")
+}
+
+// CountConversation calls the Anthropic API to count tokens for a full conversation,
+// including the system prompt and message framing.
+func (c *Client) CountConversation(conv *Conversation, model string) (int, error) {
+	if len(conv.Messages) == 0 {
+		return 0, fmt.Errorf("conversation has no messages")
+	}
+
+	return c.countRequest(Request{
+		Model:    model,
+		System:   conv.System,
+		Messages: conv.Messages,
+	})
+}
+
+// CountConversationByRole returns the API total for a conversation together with per-role
+// content token subtotals from the offline tokenizer. Subtotals exclude message framing,
+// so they sum to slightly less than the total.
+func (c *Client) CountConversationByRole(conv *Conversation, model string) (*ConversationCount, error) {
+	total, err := c.CountConversation(conv, model)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole, err := countByRole(c, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConversationCount{
+		Total:  total,
+		ByRole: byRole,
+	}, nil
+}
+
+// countByRole sums offline content token counts per role, including the system prompt
+func countByRole(counter TokenCounter, conv *Conversation) (map[string]int, error) {
+	byRole := make(map[string]int)
+
+	if conv.System != "" {
+		tokens, err := counter.ExtractTokensClientSide(conv.System)
+		if err != nil {
+			return nil, err
+		}
+		byRole[RoleSystem] += len(tokens)
+	}
+
+	for _, msg := range conv.Messages {
+		tokens, err := counter.ExtractTokensClientSide(msg.Content.String())
+		if err != nil {
+			return nil, err
+		}
+		byRole[msg.Role] += len(tokens)
+	}
+
+	return byRole, nil
+}
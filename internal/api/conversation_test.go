@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCountConversationByRole(t *testing.T) {
+	var received Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"input_tokens":100}`))
+	}))
+	defer srv.Close()
+	client := NewClientWithOptions("key", WithBaseURL(srv.URL))
+
+	conv := &Conversation{
+		System: "You are a concise assistant.",
+		Messages: []MessageInput{
+			{Role: "user", Content: TextContent("What is the capital of France?")},
+			{Role: "assistant", Content: TextContent("Paris.")},
+			{Role: "user", Content: TextContent("And of Italy?")},
+		},
+	}
+	count, err := client.CountConversationByRole(conv, "claude-sonnet-4-5")
+	if err != nil {
+		t.Fatalf("CountConversationByRole: %v", err)
+	}
+
+	if received.System != conv.System || len(received.Messages) != 3 {
+		t.Errorf("request had system %q and %d messages, want the whole conversation", received.System, len(received.Messages))
+	}
+	if count.Total != 100 {
+		t.Errorf("total = %d, want the API count 100", count.Total)
+	}
+
+	offline := func(texts ...string) int {
+		n := 0
+		for _, text := range texts {
+			tokens, err := client.ExtractTokensClientSide(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n += len(tokens)
+		}
+		return n
+	}
+	want := map[string]int{
+		RoleSystem:  offline(conv.System),
+		"user":      offline("What is the capital of France?", "And of Italy?"),
+		"assistant": offline("Paris."),
+	}
+	sum := 0
+	for role, tokens := range want {
+		if count.ByRole[role] != tokens {
+			t.Errorf("%s = %d tokens, want %d", role, count.ByRole[role], tokens)
+		}
+		sum += count.ByRole[role]
+	}
+	if len(count.ByRole) != len(want) {
+		t.Errorf("roles = %v, want system, user and assistant", count.ByRole)
+	}
+	// Subtotals exclude message framing, so they stay under the API total
+	if sum == 0 || sum > count.Total {
+		t.Errorf("subtotals sum to %d, want between 1 and the total %d", sum, count.Total)
+	}
+}
+
+func TestCountConversationRequiresMessages(t *testing.T) {
+	if _, err := NewClient("key").CountConversation(&Conversation{System: "only a system prompt"}, "claude-sonnet-4-5"); err == nil {
+		t.Error("counted a conversation with no messages")
+	}
+}
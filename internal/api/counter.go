@@ -0,0 +1,38 @@
+package api
+
+// TokenCounter counts and extracts tokens. Client implements it against the Anthropic API;
+// other implementations can be substituted to drive the processor, analyzer or visualizer
+// without HTTP.
+type TokenCounter interface {
+	// CountTokens returns the exact token count for content
+	CountTokens(content, model string) (int, error)
+	// CountTokensWithSystem returns the token count for content sent with a system prompt
+	CountTokensWithSystem(content, system, model string) (int, error)
+	// CountSystemTokens returns the tokens contributed by a system prompt alone
+	CountSystemTokens(system, model string) (int, error)
+	// CountPDFTokens returns the token count for a PDF document sent with a system prompt
+	CountPDFTokens(pdf []byte, system, model string) (int, error)
+	// CountImageTokens returns the token count for a PNG or JPEG image sent with a system prompt
+	CountImageTokens(image []byte, mediaType, system, model string) (int, error)
+	// ExtractTokensClientSide splits content into approximate tokens with their positions
+	ExtractTokensClientSide(content string) ([]Token, error)
+}
+
+// ConversationCounter counts whole conversations with per-role content subtotals
+type ConversationCounter interface {
+	CountConversationByRole(conv *Conversation, model string) (*ConversationCount, error)
+}
+
+// RateLimitObservable is implemented by counters that see API rate limit headers
+type RateLimitObservable interface {
+	SetRateLimitObserver(observe func(RateLimit))
+}
+
+// Ensure both counting backends satisfy the interfaces
+var (
+	_ TokenCounter        = (*Client)(nil)
+	_ ConversationCounter = (*Client)(nil)
+	_ TokenCounter        = (*OfflineCounter)(nil)
+	_ ConversationCounter = (*OfflineCounter)(nil)
+	_ RateLimitObservable = (*Client)(nil)
+)
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG for image.DecodeConfig
+	_ "image/png"  // Register PNG for image.DecodeConfig
+	"math"
+)
+
+// Media types of the image blocks cc-token sends
+const (
+	PNGMediaType  = "image/png"
+	JPEGMediaType = "image/jpeg"
+)
+
+// Claude's image sizing: images whose long edge exceeds maxImageEdge, or that would cost more
+// than maxImageTokens, are scaled down first, and each token covers about pixelsPerImageToken
+// pixels
+const (
+	maxImageEdge        = 1568
+	maxImageTokens      = 1600
+	pixelsPerImageToken = 750
+)
+
+// CountImageTokens counts tokens for an image sent as an image block in a user message,
+// together with an optional system prompt
+func (c *Client) CountImageTokens(image []byte, mediaType, system, model string) (int, error) {
+	return c.countBlock(base64Block("image", mediaType, image), system, model)
+}
+
+// EstimateImageTokens approximates the tokens an image of the given size costs, using Claude's
+// formula of width × height / 750 after any downscaling
+func EstimateImageTokens(width, height int) int {
+	w, h := float64(width), float64(height)
+	if long := math.Max(w, h); long > maxImageEdge {
+		scale := maxImageEdge / long
+		w, h = w*scale, h*scale
+	}
+	if w*h/pixelsPerImageToken > maxImageTokens {
+		scale := math.Sqrt(maxImageTokens * pixelsPerImageToken / (w * h))
+		w, h = w*scale, h*scale
+	}
+	return int(math.Ceil(w * h / pixelsPerImageToken))
+}
+
+// estimateImageBytes reads the dimensions of a PNG or JPEG image and estimates its tokens
+func estimateImageBytes(data []byte) (int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read image: %w", err)
+	}
+	return EstimateImageTokens(config.Width, config.Height), nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestEstimateImageTokens(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		want          int
+	}{
+		{"small image", 200, 100, 27},
+		{"at the size limit", 1092, 1092, 1590},
+		{"long edge scaled down first", 3136, 100, 105},
+		{"large image capped", 3000, 2000, 1600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateImageTokens(tt.width, tt.height); got != tt.want {
+				t.Errorf("EstimateImageTokens(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOfflineCountImageTokens(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 200, 100))); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := NewOfflineCounter().CountImageTokens(buf.Bytes(), PNGMediaType, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 27 + OfflineMessageOverhead; tokens != want {
+		t.Errorf("CountImageTokens = %d, want %d", tokens, want)
+	}
+	if _, err := NewOfflineCounter().CountImageTokens([]byte("not an image"), PNGMediaType, "", ""); err == nil {
+		t.Error("CountImageTokens accepted data that isn't an image")
+	}
+}
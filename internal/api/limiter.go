@@ -0,0 +1,119 @@
+package api
+
+import "sync"
+
+const (
+	// minAdaptiveLimit is the lowest in-flight limit the adaptive limiter backs off to
+	minAdaptiveLimit = 1
+	// backoffFactor is the multiplicative decrease applied on a rate-limit response
+	backoffFactor = 0.5
+	// lowCapacityFraction is the share of the rate limit window below which remaining
+	// requests count as scarce, and the limit backs off before the API starts returning 429s
+	lowCapacityFraction = 0.1
+	// ampleCapacityFraction is the share of the window above which remaining requests count
+	// as ample, and the limit grows by a whole request per response
+	ampleCapacityFraction = 0.5
+)
+
+// AdaptiveLimiter bounds in-flight API requests using AIMD (additive increase, multiplicative
+// decrease): the limit grows by roughly one request per window of successes and halves on 429s.
+type AdaptiveLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    float64
+	max      int
+	inFlight int
+}
+
+// NewAdaptiveLimiter creates a limiter starting at initial in-flight requests, never exceeding max
+func NewAdaptiveLimiter(initial, max int) *AdaptiveLimiter {
+	if max < minAdaptiveLimit {
+		max = minAdaptiveLimit
+	}
+	if initial < minAdaptiveLimit {
+		initial = minAdaptiveLimit
+	}
+	if initial > max {
+		initial = max
+	}
+
+	l := &AdaptiveLimiter{
+		limit: float64(initial),
+		max:   max,
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until a request slot is available under the current limit
+func (l *AdaptiveLimiter) Acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.inFlight >= int(l.limit) {
+		l.cond.Wait()
+	}
+	l.inFlight++
+}
+
+// Release frees a request slot and adjusts the limit based on the request outcome
+func (l *AdaptiveLimiter) Release(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	switch {
+	case IsRateLimited(err):
+		l.limit *= backoffFactor
+		if l.limit < minAdaptiveLimit {
+			l.limit = minAdaptiveLimit
+		}
+	case err == nil:
+		l.limit += 1 / l.limit
+		if l.limit > float64(l.max) {
+			l.limit = float64(l.max)
+		}
+	}
+
+	l.cond.Broadcast()
+}
+
+// ObserveRateLimit adjusts the limit to the API's remaining request capacity. Scarce capacity
+// halves the limit and the limit never exceeds the remaining requests; ample capacity ramps
+// the limit up faster than AIMD's additive increase.
+func (l *AdaptiveLimiter) ObserveRateLimit(rateLimit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rateLimit.Limit > 0 {
+		switch remaining := float64(rateLimit.Remaining) / float64(rateLimit.Limit); {
+		case remaining < lowCapacityFraction:
+			l.limit *= backoffFactor
+		case remaining >= ampleCapacityFraction:
+			l.limit++
+		}
+	}
+	if remaining := float64(rateLimit.Remaining); l.limit > remaining {
+		l.limit = remaining
+	}
+
+	if l.limit < minAdaptiveLimit {
+		l.limit = minAdaptiveLimit
+	}
+	if l.limit > float64(l.max) {
+		l.limit = float64(l.max)
+	}
+	l.cond.Broadcast()
+}
+
+// Limit returns the current in-flight request limit
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// Max returns the ceiling the limit can grow to
+func (l *AdaptiveLimiter) Max() int {
+	return l.max
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyCapServer answers 429 to any request arriving while more than capacity are in flight
+func concurrencyCapServer(t *testing.T, capacity int32) (*httptest.Server, *int32) {
+	t.Helper()
+	var inFlight, rateLimited int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer atomic.AddInt32(&inFlight, -1)
+		if atomic.AddInt32(&inFlight, 1) > capacity {
+			atomic.AddInt32(&rateLimited, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+			return
+		}
+		// Long enough that client overhead between requests (slow under -race) doesn't let
+		// the limiter's concurrency outrun what the server sees
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"input_tokens":1}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &rateLimited
+}
+
+func TestAdaptiveLimiterSettlesBelowRateLimit(t *testing.T) {
+	const capacity = 4
+	srv, rateLimited := concurrencyCapServer(t, capacity)
+	client := NewClientWithOptions("key", WithBaseURL(srv.URL))
+	// The limiter must see every 429 rather than the client retrying it
+	client.SetRetryRateLimits(false)
+
+	limiter := NewAdaptiveLimiter(1, 32)
+	const workers, requestsPerWorker = 32, 20
+	var mu sync.Mutex
+	var limits []int
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requestsPerWorker; j++ {
+				limiter.Acquire()
+				_, err := client.CountTokens("hello", "claude-sonnet-4-5")
+				limiter.Release(err)
+
+				mu.Lock()
+				limits = append(limits, limiter.Limit())
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(rateLimited) == 0 {
+		t.Fatal("server never rate limited; the limiter didn't probe above capacity")
+	}
+	// AIMD oscillates around the capacity, so check where the limit spent the middle of the
+	// run: at the start it is still ramping up, and at the end fewer workers are left to
+	// probe the capacity
+	settled := limits[len(limits)/3 : 2*len(limits)/3]
+	sum := 0
+	for _, limit := range settled {
+		// Probing past the capacity by one request is how AIMD finds it
+		if limit > capacity+1 {
+			t.Fatalf("limit reached %d, want at most one above the capacity of %d", limit, capacity)
+		}
+		sum += limit
+	}
+	if mean := float64(sum) / float64(len(settled)); mean > capacity {
+		t.Errorf("settled limit averages %.1f, want at most the capacity of %d", mean, capacity)
+	}
+}
+
+func TestAdaptiveLimiterAIMD(t *testing.T) {
+	l := NewAdaptiveLimiter(4, 8)
+	for i := 0; i < 4; i++ {
+		l.Acquire()
+		l.Release(nil)
+	}
+	if got := l.Limit(); got != 4 {
+		t.Errorf("limit after 4 successes at 4 = %d, want roughly one more window to grow", got)
+	}
+	for i := 0; i < 20; i++ {
+		l.Acquire()
+		l.Release(nil)
+	}
+	if got := l.Limit(); got != 8 {
+		t.Errorf("limit after many successes = %d, want the ceiling 8", got)
+	}
+
+	l.Acquire()
+	l.Release(&StatusError{StatusCode: http.StatusTooManyRequests})
+	if got := l.Limit(); got != 4 {
+		t.Errorf("limit after a 429 = %d, want halved to 4", got)
+	}
+	for i := 0; i < 5; i++ {
+		l.Acquire()
+		l.Release(&StatusError{StatusCode: http.StatusTooManyRequests})
+	}
+	if got := l.Limit(); got != minAdaptiveLimit {
+		t.Errorf("limit after repeated 429s = %d, want the floor %d", got, minAdaptiveLimit)
+	}
+}
+
+func TestAdaptiveLimiterFollowsRateLimitHeaders(t *testing.T) {
+	// Each response reports 5 fewer requests left in a 100-request window
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining := 100 - 5*atomic.AddInt32(&requests, 1)
+		w.Header().Set("anthropic-ratelimit-requests-limit", "100")
+		w.Header().Set("anthropic-ratelimit-requests-remaining", strconv.Itoa(int(remaining)))
+		w.Write([]byte(`{"input_tokens":1}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	limiter := NewAdaptiveLimiter(4, 32)
+	client := NewClientWithOptions("key", WithBaseURL(srv.URL))
+	client.SetRateLimitObserver(limiter.ObserveRateLimit)
+
+	limits := make(map[int]int) // Remaining requests -> limit after observing them
+	for remaining := 95; remaining >= 0; remaining -= 5 {
+		if _, err := client.CountTokens("hello", "claude-sonnet-4-5"); err != nil {
+			t.Fatal(err)
+		}
+		limits[remaining] = limiter.Limit()
+	}
+
+	// Ample capacity ramps the limit up, one step per response
+	if limits[50] != 4+10 {
+		t.Errorf("limit with half the window left = %d, want 14 after ten ample responses", limits[50])
+	}
+	// Scarce capacity backs off, and the limit never exceeds the requests left
+	for remaining, limit := range limits {
+		if limit > remaining && limit > 1 {
+			t.Errorf("limit = %d with %d requests remaining", limit, remaining)
+		}
+	}
+	if limits[5] >= limits[10] || limits[0] != 1 {
+		t.Errorf("limits near exhaustion = %d, %d, %d, want them falling to 1", limits[10], limits[5], limits[0])
+	}
+}
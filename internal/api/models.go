@@ -1,16 +1,85 @@
 // Package api provides HTTP client functionality for interacting with Anthropic's Claude API.
 package api
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
 // Request represents the token counting API request
 type Request struct {
 	Model    string         `json:"model"`
+	System   string         `json:"system,omitempty"`
 	Messages []MessageInput `json:"messages"`
 }
 
 // MessageInput represents a message in the API request
 type MessageInput struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string         `json:"role"`
+	Content MessageContent `json:"content"`
+}
+
+// MessageContent is a message's content: either plain text or a list of content blocks.
+// It marshals to a JSON string when it has no blocks, matching the API's shorthand.
+type MessageContent struct {
+	Text   string
+	Blocks []ContentBlock
+}
+
+// ContentBlock is a single "text", "document" or "image" block of message content
+type ContentBlock struct {
+	Type   string        `json:"type"`
+	Text   string        `json:"text,omitempty"`
+	Source *Base64Source `json:"source,omitempty"`
+}
+
+// Base64Source holds the base64-encoded data of a document or image block
+type Base64Source struct {
+	Type      string `json:"type"` // Always "base64"
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// TextContent returns message content consisting of plain text
+func TextContent(text string) MessageContent {
+	return MessageContent{Text: text}
+}
+
+// MarshalJSON encodes plain text as a string and blocks as an array
+func (c MessageContent) MarshalJSON() ([]byte, error) {
+	if c.Blocks != nil {
+		return json.Marshal(c.Blocks)
+	}
+	return json.Marshal(c.Text)
+}
+
+// UnmarshalJSON accepts either a string or an array of content blocks
+func (c *MessageContent) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		c.Text = ""
+		return json.Unmarshal(data, &c.Blocks)
+	}
+	c.Blocks = nil
+	return json.Unmarshal(data, &c.Text)
+}
+
+// String returns the text of the content, joining the text of its text blocks. Document
+// and image blocks have no text.
+func (c MessageContent) String() string {
+	if c.Blocks == nil {
+		return c.Text
+	}
+	var texts []string
+	for _, block := range c.Blocks {
+		if block.Type == "text" {
+			texts = append(texts, block.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
 }
 
 // Response represents the token counting API response
@@ -18,6 +87,43 @@ type Response struct {
 	InputTokens int `json:"input_tokens"`
 }
 
+// Sentinel errors a StatusError unwraps to, so callers can match API failures with errors.Is
+var (
+	ErrUnauthorized   = errors.New("API key rejected")            // 401 and 403
+	ErrRateLimited    = errors.New("rate limited")                // 429
+	ErrInvalidRequest = errors.New("request rejected as invalid") // 400, 404, 413 and 422
+)
+
+// StatusError is returned when the API responds with a non-200 status code
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Parsed Retry-After header, zero if absent
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// Unwrap returns the sentinel error for the status code, or nil for codes without one
+func (e *StatusError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// IsRateLimited reports whether err is a 429 Too Many Requests response from the API
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
 // Token represents a single token with its text and position
 type Token struct {
 	Text     string
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/hupe1980/go-tiktoken"
+)
+
+// OfflineMessageOverhead approximates the framing tokens the API adds to a single-message
+// request, so offline counts line up with API counts (the client-side tokenizer typically
+// trails the API by 6-8 tokens).
+const OfflineMessageOverhead = 7
+
+// OfflineCounter counts tokens with the client-side tokenizer only, making no network
+// requests. Counts are approximate.
+type OfflineCounter struct {
+	encoding *tiktoken.Encoding // Client-side tokenizer; there is no HTTP client
+}
+
+// NewOfflineCounter creates a counter backed by the client-side Claude tokenizer
+func NewOfflineCounter() *OfflineCounter {
+	return &OfflineCounter{encoding: newEncoding()}
+}
+
+// CountTokens approximates the API count for content sent as a single user message
+func (o *OfflineCounter) CountTokens(content, model string) (int, error) {
+	return o.CountTokensWithSystem(content, "", model)
+}
+
+// CountTokensWithSystem approximates the API count for content sent with a system prompt
+func (o *OfflineCounter) CountTokensWithSystem(content, system, model string) (int, error) {
+	tokens, err := o.count(content)
+	if err != nil {
+		return 0, err
+	}
+	systemTokens, err := o.CountSystemTokens(system, model)
+	if err != nil {
+		return 0, err
+	}
+	return tokens + systemTokens + OfflineMessageOverhead, nil
+}
+
+// CountSystemTokens approximates the tokens contributed by a system prompt
+func (o *OfflineCounter) CountSystemTokens(system, model string) (int, error) {
+	if system == "" {
+		return 0, nil
+	}
+	return o.count(system)
+}
+
+// CountPDFTokens fails: the client-side tokenizer can't read PDFs, so only the API counts them
+func (o *OfflineCounter) CountPDFTokens(pdf []byte, system, model string) (int, error) {
+	return 0, fmt.Errorf("counting PDF tokens requires the API")
+}
+
+// CountImageTokens estimates the API count for an image from its dimensions
+func (o *OfflineCounter) CountImageTokens(image []byte, mediaType, system, model string) (int, error) {
+	tokens, err := estimateImageBytes(image)
+	if err != nil {
+		return 0, err
+	}
+	systemTokens, err := o.CountSystemTokens(system, model)
+	if err != nil {
+		return 0, err
+	}
+	return tokens + systemTokens + OfflineMessageOverhead, nil
+}
+
+// ExtractTokensClientSide splits content into tokens with the client-side tokenizer
+func (o *OfflineCounter) ExtractTokensClientSide(content string) ([]Token, error) {
+	return extractTokens(o.encoding, content)
+}
+
+// CountConversationByRole approximates a conversation's total as its per-role content
+// subtotals plus the overhead of each message
+func (o *OfflineCounter) CountConversationByRole(conv *Conversation, model string) (*ConversationCount, error) {
+	if len(conv.Messages) == 0 {
+		return nil, fmt.Errorf("conversation has no messages")
+	}
+
+	byRole, err := countByRole(o, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(conv.Messages) * OfflineMessageOverhead
+	for _, tokens := range byRole {
+		total += tokens
+	}
+	return &ConversationCount{Total: total, ByRole: byRole}, nil
+}
+
+// count returns the number of client-side tokens in content
+func (o *OfflineCounter) count(content string) (int, error) {
+	tokens, err := o.ExtractTokensClientSide(content)
+	if err != nil {
+		return 0, err
+	}
+	return len(tokens), nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds each API request, including reading the response
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a Client created with NewClientWithOptions
+type Option func(*clientOptions)
+
+// clientOptions collects the settings applied by Options
+type clientOptions struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	baseURL    string
+}
+
+// WithHTTPClient sends requests with a copy of httpClient, e.g. one with a custom transport,
+// proxy or TLS settings. Its timeout is kept unless WithTimeout is also given.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds each request to timeout instead of the default 30 seconds
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithBaseURL sends requests to baseURL (see ValidateBaseURL) instead of the official endpoint.
+// Paths are appended to it, so a gateway prefix such as https://gateway.internal/anthropic is kept.
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
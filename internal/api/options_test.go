@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTransport answers every request with 42 input tokens, without a network, and
+// records the requests it was given
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, req)
+	rt.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"input_tokens":42}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestWithHTTPClientUsesTransport(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewClientWithOptions("test-key",
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithBaseURL("https://gateway.example.com/anthropic"))
+
+	tokens, err := client.CountTokens("hello", "claude-sonnet-4-5")
+	if err != nil || tokens != 42 {
+		t.Fatalf("CountTokens = %d, %v; want 42, nil", tokens, err)
+	}
+	if len(transport.requests) != 1 {
+		t.Fatalf("transport saw %d requests, want 1", len(transport.requests))
+	}
+	req := transport.requests[0]
+	if req.URL.Host != "gateway.example.com" || !strings.HasPrefix(req.URL.Path, "/anthropic/") {
+		t.Errorf("request URL = %s, want one under the base URL", req.URL)
+	}
+	if got := req.Header.Get("x-api-key"); got != "test-key" {
+		t.Errorf("x-api-key = %q, want test-key", got)
+	}
+}
+
+func TestWithTimeoutDoesNotChangeSharedClient(t *testing.T) {
+	shared := &http.Client{Timeout: time.Minute}
+
+	client := NewClientWithOptions("key", WithHTTPClient(shared), WithTimeout(5*time.Second))
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("timeout = %s, want 5s", client.httpClient.Timeout)
+	}
+	if shared.Timeout != time.Minute {
+		t.Errorf("shared client timeout changed to %s", shared.Timeout)
+	}
+
+	if client := NewClientWithOptions("key", WithHTTPClient(shared)); client.httpClient.Timeout != time.Minute {
+		t.Errorf("without WithTimeout: timeout = %s, want the client's 1m", client.httpClient.Timeout)
+	}
+	if client := NewClient("key"); client.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("NewClient timeout = %s, want %s", client.httpClient.Timeout, DefaultTimeout)
+	}
+}
+
+func TestWithTimeoutAgainstSlowServer(t *testing.T) {
+	fastRetries(t)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"input_tokens":42}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	client := NewClientWithOptions("key", WithBaseURL(srv.URL), WithTimeout(time.Millisecond))
+	_, err := client.CountTokens("hello", "claude-sonnet-4-5")
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("err = %v, want a timeout error", err)
+	}
+}
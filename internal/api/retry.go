@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries for transient API failures, so a
+	// request is attempted at most 3 times
+	DefaultMaxRetries = 2
+	// retryMaxDelay caps the delay between retries, including Retry-After values
+	retryMaxDelay = 30 * time.Second
+)
+
+// retryBaseDelay is the delay before the first retry; it doubles on each attempt. It is a
+// variable so tests can shorten it.
+var retryBaseDelay = 500 * time.Millisecond
+
+// isRetryable reports whether a failed request may succeed if retried: rate limits,
+// server errors and network failures are transient, other client errors are not.
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr *networkError
+	return errors.As(err, &netErr)
+}
+
+// RetryDelay returns how long to wait before retry number attempt (0-based). A Retry-After
+// value from the server takes precedence; otherwise exponential backoff with jitter is used.
+func RetryDelay(attempt int, err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > retryMaxDelay {
+			return retryMaxDelay
+		}
+		return statusErr.RetryAfter
+	}
+
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+
+	// Jitter between 50% and 100% of the delay so concurrent workers don't retry in lockstep
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RateLimit is the request rate limit reported by the anthropic-ratelimit-requests-* headers
+type RateLimit struct {
+	Limit     int       // Requests allowed per window
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the window replenishes; zero if not reported
+}
+
+// parseRateLimit reads the request rate limit headers of a response. It reports false when
+// the remaining count is missing.
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get("anthropic-ratelimit-requests-remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	rateLimit := RateLimit{Remaining: remaining}
+	rateLimit.Limit, _ = strconv.Atoi(header.Get("anthropic-ratelimit-requests-limit"))
+	rateLimit.Reset, _ = time.Parse(time.RFC3339, header.Get("anthropic-ratelimit-requests-reset"))
+	return rateLimit, true
+}
+
+// networkError wraps transport-level failures (connection refused, timeouts) so they can be retried
+type networkError struct {
+	err     error
+	timeout time.Duration // Client timeout in effect, reported when the request timed out
+}
+
+// Error implements the error interface
+func (e *networkError) Error() string {
+	var netErr net.Error
+	if e.timeout > 0 && errors.As(e.err, &netErr) && netErr.Timeout() {
+		return fmt.Sprintf("API request timed out after %s: %v", e.timeout, e.err)
+	}
+	return "API request failed: " + e.err.Error()
+}
+
+// Unwrap returns the underlying transport error
+func (e *networkError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusSequenceServer answers with the given statuses in turn, then 200 with 42 input tokens
+func statusSequenceServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			w.Write([]byte(`{"error":{"message":"test"}}`))
+			return
+		}
+		w.Write([]byte(`{"input_tokens":42}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func fastRetries(t *testing.T) {
+	t.Helper()
+	saved := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = saved })
+}
+
+func TestCountRequestRetries(t *testing.T) {
+	fastRetries(t)
+
+	tests := []struct {
+		name            string
+		statuses        []int
+		retryRateLimits bool
+		wantRequests    int32
+		wantErr         error
+	}{
+		{"recovers after two 429s", []int{429, 429}, true, 3, nil},
+		{"default allows 3 attempts", []int{429, 429, 429}, true, 3, ErrRateLimited},
+		{"5xx retried", []int{500, 503}, true, 3, nil},
+		{"401 fails fast", []int{401}, true, 1, ErrUnauthorized},
+		{"400 fails fast", []int{400}, true, 1, ErrInvalidRequest},
+		{"429 left to caller", []int{429}, false, 1, ErrRateLimited},
+		{"5xx still retried when 429s are left to caller", []int{502}, false, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := statusSequenceServer(t, tt.statuses...)
+			client := NewClientWithOptions("key", WithBaseURL(srv.URL))
+			client.SetRetryRateLimits(tt.retryRateLimits)
+
+			tokens, err := client.CountTokens("hello", "claude-sonnet-4-5")
+			if got := atomic.LoadInt32(requests); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || tokens != 42 {
+				t.Errorf("CountTokens = %d, %v; want 42, nil", tokens, err)
+			}
+		})
+	}
+}
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	err := &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second}
+	if got := RetryDelay(0, err); got != 7*time.Second {
+		t.Errorf("RetryDelay = %s, want 7s", got)
+	}
+	err.RetryAfter = time.Hour
+	if got := RetryDelay(0, err); got != retryMaxDelay {
+		t.Errorf("RetryDelay = %s, want cap %s", got, retryMaxDelay)
+	}
+}
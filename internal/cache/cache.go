@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 )
@@ -16,6 +17,19 @@ const (
 	cacheDirPerm = 0755
 	// FilePerm is the default file permission for cache and export files
 	FilePerm = 0644
+
+	// defaultCacheDirName is the cache directory under the user's home directory
+	defaultCacheDirName = ".cc-token"
+	// cacheFileName is the name of the cache file inside the cache directory
+	cacheFileName = "cache.json"
+	// cacheDirEnv is the environment variable overriding the cache directory
+	cacheDirEnv = "CC_TOKEN_CACHE_DIR"
+	// formatVersion is the current cache file format; entries of older files are discarded on load
+	formatVersion = 2
+
+	// DefaultMaxEntries is the default limit on cached entries before the least recently
+	// used are evicted on save
+	DefaultMaxEntries = 10000
 )
 
 // Entry represents a cached token count
@@ -23,92 +37,330 @@ type Entry struct {
 	Tokens   int       `json:"tokens"`
 	Hash     string    `json:"hash"`
 	Modified time.Time `json:"modified"`
+	CachedAt time.Time `json:"cached_at,omitempty"` // When the entry was written; zero for older entries
+	// LastAccessed is when the entry was last read or written, used for LRU eviction
+	LastAccessed time.Time `json:"last_accessed,omitempty"`
+}
+
+// lastUsed returns when the entry was last used, falling back to when it was cached for
+// entries written before access times were recorded
+func (e Entry) lastUsed() time.Time {
+	if !e.LastAccessed.IsZero() {
+		return e.LastAccessed
+	}
+	return e.CachedAt
+}
+
+// Stats summarizes the contents of a cache
+type Stats struct {
+	Path        string
+	Entries     int
+	Models      int // Number of models with cached entries
+	TotalTokens int
+	Oldest      time.Time // Zero if no entry records when it was cached
+	Newest      time.Time
+	SizeBytes   int64 // Size of the cache file, zero if it doesn't exist
+}
+
+// entryKey identifies a cache entry by model and file path
+type entryKey struct {
+	model string
+	path  string
+}
+
+// fileFormat is the on-disk cache layout, with entries nested by model then path
+type fileFormat struct {
+	Version int                         `json:"version"`
+	Entries map[string]map[string]Entry `json:"entries"`
 }
 
 // Cache holds the token count cache
 type Cache struct {
 	mu      sync.RWMutex
-	entries map[string]Entry
-	path    string
+	entries map[string]map[string]Entry // model -> path -> entry
+	dirty   map[entryKey]bool           // Entries set during this run, merged into the on-disk cache on save
+	// accessed records when entries were read during this run, merged into the on-disk cache on save
+	accessed   map[entryKey]time.Time
+	maxEntries int // Zero disables eviction
+	path       string
+	discarded  int // Entries of a legacy cache file dropped on load
 }
 
-// Load loads the token count cache from disk, creating a new cache if one doesn't exist.
-// The cache is stored in ~/.cc-token/cache.json.
-func Load() (*Cache, error) {
+// ResolveDir returns the cache directory to use: the explicit dir if set, then the
+// CC_TOKEN_CACHE_DIR environment variable, then ~/.cc-token.
+func ResolveDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	if envDir := os.Getenv(cacheDirEnv); envDir != "" {
+		return envDir, nil
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, defaultCacheDirName), nil
+}
 
-	cacheDir := filepath.Join(homeDir, ".cc-token")
-	cachePath := filepath.Join(cacheDir, "cache.json")
+// Load loads the token count cache from cacheDir/cache.json, creating a new cache if one
+// doesn't exist. The file is read under a lock so a shared directory can be used safely.
+func Load(cacheDir string) (*Cache, error) {
+	cachePath := filepath.Join(cacheDir, cacheFileName)
 
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, cacheDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	c := &Cache{
-		entries: make(map[string]Entry),
-		path:    cachePath,
+	unlock, err := acquireLock(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, discarded, err := readEntries(cachePath)
+	if err != nil {
+		return nil, err
 	}
 
-	// Load existing cache
+	return &Cache{
+		entries:    entries,
+		dirty:      make(map[entryKey]bool),
+		accessed:   make(map[entryKey]time.Time),
+		maxEntries: DefaultMaxEntries,
+		path:       cachePath,
+		discarded:  discarded,
+	}, nil
+}
+
+// readEntries reads cache entries from disk, returning an empty map if the file doesn't exist.
+// Legacy (version 1) files are a flat path -> entry map that doesn't record which model
+// produced each count, so their entries are discarded rather than trusted; the number
+// discarded is returned.
+func readEntries(cachePath string) (map[string]map[string]Entry, int, error) {
+	entries := make(map[string]map[string]Entry)
+
 	data, err := os.ReadFile(cachePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return c, nil
+			return entries, 0, nil
 		}
-		return nil, fmt.Errorf("failed to read cache file: %w", err)
+		return nil, 0, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
-	if err := json.Unmarshal(data, &c.entries); err != nil {
-		return nil, fmt.Errorf("failed to parse cache file: %w", err)
+	var file fileFormat
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+	if file.Version != formatVersion {
+		var legacy map[string]Entry
+		if json.Unmarshal(data, &legacy) != nil {
+			return entries, 0, nil
+		}
+		return entries, len(legacy), nil
 	}
+	if file.Entries == nil {
+		return entries, 0, nil
+	}
+
+	return file.Entries, 0, nil
+}
+
+// Discarded returns the number of entries dropped on load because the cache file predates
+// per-model entries. They are recounted, and the next save rewrites the file.
+func (c *Cache) Discarded() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.discarded
+}
 
-	return c, nil
+// SetMaxEntries sets the number of entries kept on save; the least recently used entries
+// beyond the limit are evicted. Zero disables eviction.
+func (c *Cache) SetMaxEntries(maxEntries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = maxEntries
+}
+
+// Get retrieves the cache entry for the given model and path in a thread-safe manner,
+// recording the access for LRU eviction.
+func (c *Cache) Get(model, path string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[model][path]
+	if ok {
+		c.accessed[entryKey{model: model, path: path}] = time.Now()
+	}
+	return entry, ok
 }
 
-// Get retrieves a cache entry for the given path in a thread-safe manner.
-func (c *Cache) Get(path string) (Entry, bool) {
+// Peek retrieves the cache entry for the given model and path without recording the access,
+// for lookups that shouldn't keep the entry from LRU eviction, such as estimates.
+func (c *Cache) Peek(model, path string) (Entry, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	entry, ok := c.entries[path]
+	entry, ok := c.entries[model][path]
 	return entry, ok
 }
 
-// Set stores a cache entry for the given path in a thread-safe manner.
-func (c *Cache) Set(path string, entry Entry) {
+// Set stores a cache entry for the given model and path in a thread-safe manner.
+func (c *Cache) Set(model, path string, entry Entry) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries[path] = entry
+	if entry.CachedAt.IsZero() {
+		entry.CachedAt = time.Now()
+	}
+	entry.LastAccessed = entry.CachedAt
+	setEntry(c.entries, model, path, entry)
+	c.dirty[entryKey{model: model, path: path}] = true
 }
 
-// Save persists the cache to disk in JSON format.
-func (c *Cache) Save() error {
+// Stats returns the number of entries, total cached tokens, the oldest and newest entry
+// timestamps and the size of the cache file on disk. Entries written before timestamps were
+// recorded are counted but don't affect Oldest/Newest.
+func (c *Cache) Stats() Stats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	data, err := json.MarshalIndent(c.entries, "", "  ")
+	stats := Stats{Path: c.path, Models: len(c.entries)}
+	for _, paths := range c.entries {
+		for _, entry := range paths {
+			stats.Entries++
+			stats.TotalTokens += entry.Tokens
+
+			if entry.CachedAt.IsZero() {
+				continue
+			}
+			if stats.Oldest.IsZero() || entry.CachedAt.Before(stats.Oldest) {
+				stats.Oldest = entry.CachedAt
+			}
+			if entry.CachedAt.After(stats.Newest) {
+				stats.Newest = entry.CachedAt
+			}
+		}
+	}
+
+	if info, err := os.Stat(c.path); err == nil {
+		stats.SizeBytes = info.Size()
+	}
+	return stats
+}
+
+// setEntry stores an entry in a nested model map, creating the model's map if needed
+func setEntry(entries map[string]map[string]Entry, model, path string, entry Entry) {
+	if entries[model] == nil {
+		entries[model] = make(map[string]Entry)
+	}
+	entries[model][path] = entry
+}
+
+// Save persists the cache to disk in JSON format. Under the lock it re-reads the file and
+// merges in entries set and accessed during this run, so concurrent runs sharing a cache
+// don't lose entries, then evicts the least recently used entries beyond the size limit.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unlock, err := acquireLock(c.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	merged, _, err := readEntries(c.path)
+	if err != nil {
+		// A corrupt file shouldn't block saving; start over from this run's entries
+		merged = make(map[string]map[string]Entry)
+		for model, paths := range c.entries {
+			for path, entry := range paths {
+				setEntry(merged, model, path, entry)
+			}
+		}
+	}
+	for key := range c.dirty {
+		setEntry(merged, key.model, key.path, c.entries[key.model][key.path])
+	}
+	for key, accessedAt := range c.accessed {
+		// Only bump the access time; another run may have stored a newer count for the path
+		if entry, ok := merged[key.model][key.path]; ok && accessedAt.After(entry.LastAccessed) {
+			entry.LastAccessed = accessedAt
+			merged[key.model][key.path] = entry
+		}
+	}
+	evictLeastRecentlyUsed(merged, c.maxEntries)
+
+	data, err := json.MarshalIndent(fileFormat{Version: formatVersion, Entries: merged}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache data: %w", err)
 	}
 
-	if err := os.WriteFile(c.path, data, FilePerm); err != nil {
+	if err := writeFileAtomic(c.path, data); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
+	c.entries = merged
+	c.dirty = make(map[entryKey]bool)
+	c.accessed = make(map[entryKey]time.Time)
 	return nil
 }
 
-// Clear removes the cache file from disk and prints a confirmation message.
-func Clear() error {
-	homeDir, err := os.UserHomeDir()
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers never see a partially written cache
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, FilePerm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
 	}
+	return nil
+}
+
+// evictLeastRecentlyUsed removes the least recently used entries until at most maxEntries
+// remain. Entries with no recorded access time are evicted first.
+func evictLeastRecentlyUsed(entries map[string]map[string]Entry, maxEntries int) {
+	var keys []entryKey
+	for model, paths := range entries {
+		for path := range paths {
+			keys = append(keys, entryKey{model: model, path: path})
+		}
+	}
+	if maxEntries <= 0 || len(keys) <= maxEntries {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i].model][keys[i].path].lastUsed().Before(entries[keys[j].model][keys[j].path].lastUsed())
+	})
+	for _, key := range keys[:len(keys)-maxEntries] {
+		delete(entries[key.model], key.path)
+		if len(entries[key.model]) == 0 {
+			delete(entries, key.model)
+		}
+	}
+}
 
-	cachePath := filepath.Join(homeDir, ".cc-token", "cache.json")
+// Clear removes the cache file in cacheDir and prints a confirmation message.
+func Clear(cacheDir string) error {
+	cachePath := filepath.Join(cacheDir, cacheFileName)
 
 	if err := os.Remove(cachePath); err != nil {
 		if os.IsNotExist(err) {
@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// reload saves c and loads its directory again, as the next run would
+func reload(t *testing.T, c *Cache, dir string) *Cache {
+	t.Helper()
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return loaded
+}
+
+func TestPeekDoesNotRecordAccess(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cachedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	c.Set("model", "a.txt", Entry{Tokens: 5, Hash: "h", CachedAt: cachedAt})
+	c = reload(t, c, dir)
+
+	entry, ok := c.Peek("model", "a.txt")
+	if !ok || entry.Tokens != 5 {
+		t.Fatalf("Peek = %+v, %v; want the stored entry", entry, ok)
+	}
+	if _, ok := c.Peek("other-model", "a.txt"); ok {
+		t.Error("Peek found an entry cached for another model")
+	}
+	c = reload(t, c, dir)
+	if entry, _ := c.Peek("model", "a.txt"); !entry.LastAccessed.Equal(cachedAt) {
+		t.Errorf("LastAccessed after Peek = %v, want unchanged %v", entry.LastAccessed, cachedAt)
+	}
+
+	c.Get("model", "a.txt")
+	c = reload(t, c, dir)
+	if entry, _ := c.Peek("model", "a.txt"); !entry.LastAccessed.After(cachedAt) {
+		t.Errorf("LastAccessed after Get = %v, want later than %v", entry.LastAccessed, cachedAt)
+	}
+}
+
+func TestPeekDoesNotAffectEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	now := time.Now()
+	c.Set("model", "old.txt", Entry{Tokens: 1, CachedAt: now.Add(-2 * time.Hour)})
+	c.Set("model", "new.txt", Entry{Tokens: 2, CachedAt: now.Add(-time.Hour)})
+	c = reload(t, c, dir)
+
+	c.SetMaxEntries(1)
+	c.Peek("model", "old.txt")
+	c = reload(t, c, dir)
+
+	if _, ok := c.Peek("model", "old.txt"); ok {
+		t.Error("peeked entry was kept over a more recently used one")
+	}
+	if _, ok := c.Peek("model", "new.txt"); !ok {
+		t.Error("more recently used entry was evicted")
+	}
+}
+
+func TestSaveEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	now := time.Now()
+	paths := []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"}
+	for i, path := range paths {
+		// a.txt is the oldest, e.txt the newest
+		c.Set("model", path, Entry{Tokens: i, CachedAt: now.Add(time.Duration(i-len(paths)) * time.Hour)})
+	}
+	c = reload(t, c, dir)
+
+	// Reading the oldest entry makes it the most recently used
+	c.Get("model", "a.txt")
+	c.SetMaxEntries(3)
+	c = reload(t, c, dir)
+
+	if got := c.Stats().Entries; got != 3 {
+		t.Errorf("entries after save = %d, want 3", got)
+	}
+	for _, path := range []string{"a.txt", "d.txt", "e.txt"} {
+		if _, ok := c.Peek("model", path); !ok {
+			t.Errorf("%s was evicted, want kept", path)
+		}
+	}
+	for _, path := range []string{"b.txt", "c.txt"} {
+		if _, ok := c.Peek("model", path); ok {
+			t.Errorf("%s was kept, want evicted as least recently used", path)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	dir := t.TempDir()
+	empty, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if stats := empty.Stats(); stats.Entries != 0 || stats.TotalTokens != 0 || stats.SizeBytes != 0 || !stats.Oldest.IsZero() {
+		t.Errorf("missing cache stats = %+v, want zeros", stats)
+	}
+
+	oldest := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newest := oldest.Add(48 * time.Hour)
+	empty.Set("sonnet", "a.txt", Entry{Tokens: 10, CachedAt: oldest})
+	empty.Set("sonnet", "b.txt", Entry{Tokens: 20, CachedAt: newest})
+	empty.Set("haiku", "a.txt", Entry{Tokens: 12, CachedAt: oldest.Add(time.Hour)})
+	c := reload(t, empty, dir)
+
+	stats := c.Stats()
+	if stats.Entries != 3 || stats.Models != 2 || stats.TotalTokens != 42 {
+		t.Errorf("stats = %d entries across %d models, %d tokens; want 3, 2, 42", stats.Entries, stats.Models, stats.TotalTokens)
+	}
+	if !stats.Oldest.Equal(oldest) || !stats.Newest.Equal(newest) {
+		t.Errorf("oldest %v, newest %v; want %v, %v", stats.Oldest, stats.Newest, oldest, newest)
+	}
+	if stats.SizeBytes == 0 || stats.Path != filepath.Join(dir, cacheFileName) {
+		t.Errorf("file %s of %d bytes, want the saved cache file", stats.Path, stats.SizeBytes)
+	}
+}
+
+func TestConcurrentSavesKeepEntries(t *testing.T) {
+	dir := t.TempDir()
+	const runs, filesPerRun = 2, 50
+
+	// Both runs load before either saves, as two CI runners sharing the directory would
+	caches := make([]*Cache, runs)
+	for i := range caches {
+		c, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		for j := 0; j < filesPerRun; j++ {
+			c.Set("model", fmt.Sprintf("run%d/file%d.txt", i, j), Entry{Tokens: i*filesPerRun + j, Hash: "h"})
+		}
+		caches[i] = c
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	for _, c := range caches {
+		wg.Add(1)
+		go func(c *Cache) {
+			defer wg.Done()
+			errs <- c.Save()
+		}(c)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.Stats().Entries; got != runs*filesPerRun {
+		t.Errorf("entries after concurrent saves = %d, want %d", got, runs*filesPerRun)
+	}
+	for i := 0; i < runs; i++ {
+		for j := 0; j < filesPerRun; j++ {
+			if entry, ok := loaded.Peek("model", fmt.Sprintf("run%d/file%d.txt", i, j)); !ok || entry.Tokens != i*filesPerRun+j {
+				t.Fatalf("run %d file %d = %+v, %v; want the saved entry", i, j, entry, ok)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, cacheFileName+".lock")); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestStaleLockIsBrokenBeforeTimeout(t *testing.T) {
+	if staleLockAge >= lockTimeout {
+		t.Fatalf("staleLockAge %s must be shorter than lockTimeout %s", staleLockAge, lockTimeout)
+	}
+
+	// A lock left by a crashed process, old enough to be stale
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, cacheFileName+".lock")
+	if err := os.WriteFile(lockPath, []byte("12345\n"), FilePerm); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-staleLockAge - time.Second)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load with a stale lock: %v", err)
+	}
+	c.Set("model", "a.txt", Entry{Tokens: 1, Hash: "h"})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s to break the stale lock, want no waiting", elapsed)
+	}
+}
+
+func TestLoadVersion1CacheFile(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "cache-v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, cacheFileName), fixture, FilePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// The v1 counts don't say which model produced them, so none is served
+	if got := c.Discarded(); got != 2 {
+		t.Errorf("Discarded = %d, want the 2 v1 entries", got)
+	}
+	if got := c.Stats().Entries; got != 0 {
+		t.Errorf("entries = %d, want 0", got)
+	}
+	if _, ok := c.Peek("claude-sonnet-4-5", "/home/user/project/README.md"); ok {
+		t.Error("a v1 entry was served for a model")
+	}
+
+	// Saving rewrites the file in the current format, so the reset happens once
+	c.Set("claude-sonnet-4-5", "/home/user/project/README.md", Entry{Tokens: 1300, Hash: "h"})
+	c = reload(t, c, dir)
+	if got := c.Discarded(); got != 0 {
+		t.Errorf("Discarded after save = %d, want 0", got)
+	}
+	if entry, ok := c.Peek("claude-sonnet-4-5", "/home/user/project/README.md"); !ok || entry.Tokens != 1300 {
+		t.Errorf("entry after save = %+v, %v; want the new count", entry, ok)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, cacheFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"version": 2`) {
+		t.Errorf("saved file isn't version 2:\n%s", data)
+	}
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// lockRetryInterval is how often a held lock is re-checked
+	lockRetryInterval = 50 * time.Millisecond
+	// lockTimeout is how long to wait for another process to release the lock
+	lockTimeout = 10 * time.Second
+	// staleLockAge is the age after which a leftover lock file is assumed abandoned. It is
+	// shorter than lockTimeout so a waiter breaks a crashed process's lock instead of timing
+	// out; the lock is only held to read or merge and write the cache file.
+	staleLockAge = 5 * time.Second
+)
+
+// acquireLock takes an exclusive lock on path by creating path+".lock" with O_EXCL.
+// Lock files work on network filesystems where flock is unreliable. The returned
+// function releases the lock.
+func acquireLock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, FilePerm)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		// Break locks left behind by crashed processes
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for cache lock %s", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
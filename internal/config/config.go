@@ -1,7 +1,16 @@
 // Package config provides configuration structures for cc-token CLI tool.
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
 
 var (
 	// ValidVisualizationModes defines all supported visualization modes
@@ -14,22 +23,121 @@ var (
 	}
 )
 
+const (
+	// FormatTree prints human-readable trees and summaries (the default)
+	FormatTree = "tree"
+	// FormatJSON prints machine-readable JSON
+	FormatJSON = "json"
+	// FormatCSV prints one CSV row per counted file
+	FormatCSV = "csv"
+	// FormatMarkdown prints a GitHub-flavored Markdown table of count results
+	FormatMarkdown = "markdown"
+	// FormatSARIF prints LLM safety findings from --analyze as SARIF 2.1.0, for code scanning
+	FormatSARIF = "sarif"
+
+	// GroupByExtension groups count summaries by file extension
+	GroupByExtension = "ext"
+
+	// SortName lists a directory's files by path (the default)
+	SortName = "name"
+	// SortTokens lists a directory's files by token count, most first
+	SortTokens = "tokens"
+	// SortCost lists a directory's files by estimated cost, most first
+	SortCost = "cost"
+
+	// ContentTypeHTML treats content as HTML for --strip-html
+	ContentTypeHTML = "html"
+	// ContentTypeMarkdown treats content as markdown for --strip-frontmatter
+	ContentTypeMarkdown = "markdown"
+	// ContentTypeText treats content as plain text with no preprocessing
+	ContentTypeText = "text"
+
+	// minTimeout is the shortest --timeout accepted; no API request completes in less
+	minTimeout = time.Millisecond
+)
+
 // Config holds CLI configuration
 type Config struct {
-	Model            string
-	Extensions       []string
-	MaxSize          int64
-	Concurrency      int
-	ShowCost         bool
-	JSONOutput       bool
-	Verbose          bool
-	NoCache          bool
-	Visualize        string // "basic", "interactive", "html", "json", "plain", or empty string
-	SkipConfirmation bool   // Skip cost confirmation prompts (for automation)
-	Plain            bool   // Use plain text output (no ANSI colors)
-	OutputFile       string // Output file path for HTML export
-	NoBrowser        bool   // Skip auto-opening browser for web modes
-	Analyze          bool   // Perform comprehensive token optimization analysis
+	Model               string        `json:"model"`
+	AllowUnknownModel   bool          `json:"allow_unknown_model"` // Accept a model without known pricing instead of rejecting it
+	Extensions          []string      `json:"extensions"`
+	Exclude             []string      `json:"exclude"`         // Glob patterns (relative to the walk root) for paths to skip
+	MaxDepth            int           `json:"max_depth"`       // Levels of subdirectories to descend into (0 = root files only, -1 = unlimited)
+	FollowSymlinks      bool          `json:"follow_symlinks"` // Walk into symlinked directories, visiting each real directory once
+	IncludeBinary       bool          `json:"include_binary"`  // Count files that look like binary data instead of skipping them
+	MaxSize             int64         `json:"max_size"`
+	ChunkSize           int64         `json:"chunk_size"` // Count files over MaxSize in pieces of this many bytes (0 = reject them)
+	Concurrency         int           `json:"concurrency"`
+	Timeout             time.Duration `json:"timeout"` // Timeout for each API request attempt
+	ShowCost            bool          `json:"show_cost"`
+	PricingFile         string        `json:"pricing_file"` // JSON file of per-model input prices overriding the built-in table
+	Currency            string        `json:"currency"`     // ISO code costs are converted to and displayed in (default USD)
+	MaxCost             float64       `json:"max_cost"`     // Abort (or confirm) a directory run whose estimated cost exceeds this, in Currency
+	CacheWrites         int           `json:"cache_writes"` // Requests writing the counted content to the prompt cache, for cost estimates
+	CacheReads          int           `json:"cache_reads"`  // Requests reading the counted content from the prompt cache, for cost estimates
+	Format              string        `json:"format"`       // Output format: tree, json, csv or markdown
+	JSONOutput          bool          `json:"json_output"`  // Deprecated --json flag, folded into Format
+	Verbose             bool          `json:"verbose"`
+	NoCache             bool          `json:"no_cache"`
+	Offline             bool          `json:"offline"`              // Count with the client-side tokenizer only; no API key or network needed
+	PrintConfig         bool          `json:"-"`                    // Print the resolved configuration as JSON and exit
+	Refresh             bool          `json:"refresh"`              // Ignore cache hits but still write fresh counts to the cache
+	CacheDir            string        `json:"cache_dir"`            // Cache directory (resolved from --cache-dir, CC_TOKEN_CACHE_DIR or ~/.cc-token)
+	CacheMaxEntries     int           `json:"cache_max_entries"`    // Entries kept on save before LRU eviction (0 = unlimited)
+	Visualize           string        `json:"visualize"`            // "basic", "interactive", "html", "json", "plain", or empty string
+	HighlightIssues     bool          `json:"highlight_issues"`     // Highlight tokens overlapping safety issues in visualize output
+	SkipConfirmation    bool          `json:"skip_confirmation"`    // Skip cost confirmation prompts (for automation)
+	Plain               bool          `json:"plain"`                // Use plain text output (no ANSI colors)
+	NoColor             bool          `json:"no_color"`             // Disable ANSI colors without switching to the plain layout
+	OutputFile          string        `json:"output_file"`          // Output file path for HTML export
+	NoBrowser           bool          `json:"no_browser"`           // Skip auto-opening browser for web modes
+	Host                string        `json:"host"`                 // Address the interactive visualization server listens on
+	Port                int           `json:"port"`                 // Port for the interactive visualization server (0 = first free port from 8080)
+	Analyze             bool          `json:"analyze"`              // Perform comprehensive token optimization analysis
+	EnableDetectors     []string      `json:"enable_detectors"`     // Run only these analysis detectors (by name)
+	DisableDetectors    []string      `json:"disable_detectors"`    // Skip these analysis detectors (by name)
+	OnlySecurity        bool          `json:"only_security"`        // Run only the security-related analysis detectors
+	MinRepetitions      int           `json:"min_repetitions"`      // Analysis: occurrences before a phrase counts as repeated
+	LongLineLength      int           `json:"long_line_length"`     // Analysis: characters above which a line is long
+	MinURLLength        int           `json:"min_url_length"`       // Analysis: length above which a repeated URL is worth a reference link
+	FailUnder           int           `json:"fail_under"`           // With --analyze, fail when a file's reliability score is below this (0 disables)
+	FailOnDetectors     []string      `json:"fail_on_detectors"`    // With --analyze, fail when any of these safety detectors reports an issue
+	Fix                 bool          `json:"fix"`                  // With --analyze, rewrite the safe-to-fix issues (invisible/BiDi characters, NFC, blank lines)
+	WriteFixes          bool          `json:"write_fixes"`          // With --fix, write the fixed content back to the file instead of stdout
+	GroupBy             string        `json:"group_by"`             // Group count summaries ("ext" for per-extension totals, or empty)
+	Sort                string        `json:"sort"`                 // Order of a directory's files in tree output: name, tokens or cost
+	Top                 int           `json:"top"`                  // Show only the N files with the most tokens (0 = all files)
+	ByExtension         bool          `json:"by_extension"`         // Shorthand for GroupBy = "ext"
+	EmitHash            bool          `json:"emit_hash"`            // Include SHA-256 content hashes in output
+	JSONTree            bool          `json:"json_tree"`            // Emit nested JSON mirroring the directory tree
+	CostPrecision       int           `json:"cost_precision"`       // Decimal places for costs in human-readable output
+	CostOnly            bool          `json:"cost_only"`            // Print only the total cost as a bare number
+	Quiet               bool          `json:"quiet"`                // Print only the total token count as a bare number
+	PerLine             bool          `json:"per_line"`             // Print a single file with each line's client-side token count
+	Budget              int           `json:"budget"`               // Token budget the fit command finds the cut point for
+	Stats               bool          `json:"stats"`                // Report per-file token distribution statistics
+	Progress            bool          `json:"progress"`             // Always report directory scan progress on stderr
+	NoProgress          bool          `json:"no_progress"`          // Never report directory scan progress
+	TUI                 bool          `json:"tui"`                  // Browse count results in an interactive terminal UI
+	ParseMIME           bool          `json:"parse_mime"`           // Count only text body parts of .eml files
+	JSONPath            string        `json:"json_path"`            // Count only string values selected by this path in .json files
+	StdinDelimiter      string        `json:"stdin_delimiter"`      // Split stdin into separately counted documents on this delimiter
+	StdinName           string        `json:"stdin_name"`           // Path reported for stdin input
+	FilesFrom           string        `json:"files_from"`           // Count only the paths listed in this file ("-" for stdin)
+	Texts               []string      `json:"texts"`                // Literal strings to count, each reported separately
+	StripHTML           bool          `json:"strip_html"`           // Strip tags, scripts and styles from HTML content
+	StripFrontmatter    bool          `json:"strip_frontmatter"`    // Strip leading YAML front matter from markdown content
+	ContentType         string        `json:"content_type"`         // Override content type detection ("html", "markdown", "text", or empty)
+	ByRole              bool          `json:"by_role"`              // Count a conversation file with per-role subtotals
+	CountRuns           int           `json:"count_runs"`           // Repeat the count of a single file N times and report statistics
+	AdaptiveConcurrency bool          `json:"adaptive_concurrency"` // Adjust in-flight API requests based on rate-limit responses
+	MaxRetries          int           `json:"max_retries"`          // Retries for transient API failures
+	APIBaseURL          string        `json:"api_base_url"`         // Anthropic API endpoint, e.g. an internal gateway or proxy
+	MaxTokens           int           `json:"max_tokens"`           // Truncate content to its first N tokens before counting (0 = no limit)
+	Annotate            string        `json:"annotate"`             // YAML manifest whose entries get token counts written back
+	Strict              bool          `json:"strict"`               // CI mode: reject unknown models and fail on secrets, context overflow and priority-1 issues
+	System              string        `json:"system"`               // --system value: a file path or a literal system prompt
+	SystemPrompt        string        `json:"system_prompt"`        // Resolved system prompt text sent with every request
 }
 
 // IsValidVisualizationMode checks if the given mode is a valid visualization mode
@@ -45,8 +153,180 @@ func (c *Config) Validate() error {
 	if c.MaxSize <= 0 {
 		return fmt.Errorf("max-size must be greater than 0")
 	}
+	if c.ChunkSize < 0 {
+		return fmt.Errorf("chunk-size must not be negative")
+	}
+	if c.ChunkSize > c.MaxSize {
+		return fmt.Errorf("--chunk-size (%d) cannot exceed --max-size (%d)", c.ChunkSize, c.MaxSize)
+	}
+	if c.CostPrecision < 0 || c.CostPrecision > pricing.MaxCostPrecision {
+		return fmt.Errorf("cost precision must be between 0 and %d", pricing.MaxCostPrecision)
+	}
+	if c.Timeout < minTimeout {
+		return fmt.Errorf("timeout must be at least %s, got %s", minTimeout, c.Timeout)
+	}
+	if c.Host == "" {
+		return fmt.Errorf("host must not be empty")
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("port must be between 0 and 65535, got %d", c.Port)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max retries must be non-negative")
+	}
+	if c.APIBaseURL != "" {
+		if err := api.ValidateBaseURL(c.APIBaseURL); err != nil {
+			return err
+		}
+	}
+	if c.CacheWrites < 0 || c.CacheReads < 0 {
+		return fmt.Errorf("--cache-write and --cache-read must be non-negative")
+	}
+	if _, ok := pricing.LookupCurrency(c.Currency); !ok {
+		return fmt.Errorf("unsupported currency %q (supported: %s)", c.Currency, strings.Join(pricing.SupportedCurrencies(), ", "))
+	}
+	for _, name := range append(append([]string{}, c.EnableDetectors...), c.DisableDetectors...) {
+		if !analyzer.IsDetectorName(name) {
+			return fmt.Errorf("unknown detector %q (available: %s)", name, strings.Join(analyzer.DetectorNames(), ", "))
+		}
+	}
+	if c.MinRepetitions <= 0 || c.LongLineLength <= 0 || c.MinURLLength <= 0 {
+		return fmt.Errorf("--min-repetitions, --long-line-length and --min-url-length must be positive")
+	}
+	for _, name := range c.FailOnDetectors {
+		if !analyzer.IsSafetyDetectorName(name) {
+			return fmt.Errorf("--fail-on-detector: %q is not an LLM safety detector", name)
+		}
+	}
+	if c.FailUnder < 0 || c.FailUnder > 100 {
+		return fmt.Errorf("--fail-under must be between 0 and 100, got %d", c.FailUnder)
+	}
+	if c.Fix && !c.Analyze {
+		return fmt.Errorf("--fix requires --analyze")
+	}
+	if c.Fix && c.Format != FormatTree {
+		return fmt.Errorf("--fix cannot be combined with --format %s", c.Format)
+	}
+	if c.WriteFixes && !c.Fix {
+		return fmt.Errorf("--write requires --fix")
+	}
+	if (c.FailUnder > 0 || len(c.FailOnDetectors) > 0) && !c.Analyze {
+		return fmt.Errorf("--fail-under and --fail-on-detector require --analyze")
+	}
+	if c.MaxCost < 0 {
+		return fmt.Errorf("max cost must be non-negative")
+	}
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("max tokens must be non-negative")
+	}
+	if c.MaxDepth < -1 {
+		return fmt.Errorf("max depth must be -1 (unlimited) or non-negative")
+	}
+	if c.CacheMaxEntries < 0 {
+		return fmt.Errorf("cache max entries must be non-negative")
+	}
+	if c.CountRuns < 0 {
+		return fmt.Errorf("count runs must be non-negative")
+	}
+	if c.Refresh && c.NoCache {
+		return fmt.Errorf("--refresh cannot be combined with --no-cache")
+	}
+	if c.Offline && c.Refresh {
+		return fmt.Errorf("--refresh cannot be combined with --offline")
+	}
+	if c.ByRole && c.System != "" {
+		return fmt.Errorf("--by-role cannot be combined with --system (use the conversation's system field)")
+	}
+	if c.ByRole && c.Analyze {
+		return fmt.Errorf("--by-role cannot be combined with --analyze")
+	}
+	if c.Format != FormatTree && c.Format != FormatJSON && c.Format != FormatCSV && c.Format != FormatMarkdown && c.Format != FormatSARIF {
+		return fmt.Errorf("invalid format: %s (must be 'tree', 'json', 'csv', 'markdown', or 'sarif')", c.Format)
+	}
+	if c.CostOnly && (c.Format != FormatTree || c.TUI || c.Analyze) {
+		return fmt.Errorf("--cost-only cannot be combined with --format %s, --tui or --analyze", c.Format)
+	}
+	if c.Quiet && (c.Format != FormatTree || c.CostOnly || c.Top > 0 || c.Stats || c.TUI || c.Analyze || c.ByRole || c.CountRuns > 1) {
+		return fmt.Errorf("--quiet cannot be combined with --format %s, --cost-only, --top, --stats, --tui, --analyze, --by-role or --count-runs", c.Format)
+	}
+	if c.PerLine && (c.Format != FormatTree || c.Analyze || c.Annotate != "" || c.ByRole || c.CountRuns > 1 || c.FilesFrom != "" || c.TUI || c.CostOnly || c.Quiet || c.Top > 0) {
+		return fmt.Errorf("--per-line cannot be combined with --format %s, --analyze, --annotate, --by-role, --count-runs, --files-from, --tui, --cost-only, --quiet or --top", c.Format)
+	}
+	if len(c.Texts) > 0 && (c.Analyze || c.Annotate != "" || c.ByRole || c.CountRuns > 1 || c.PerLine) {
+		return fmt.Errorf("--text cannot be combined with --analyze, --annotate, --by-role, --count-runs or --per-line")
+	}
+	if c.FilesFrom != "" && (c.Analyze || c.Annotate != "" || c.ByRole || c.CountRuns > 1 || c.TUI) {
+		return fmt.Errorf("--files-from cannot be combined with --analyze, --annotate, --by-role, --count-runs or --tui")
+	}
+	if c.Progress && c.NoProgress {
+		return fmt.Errorf("--progress cannot be combined with --no-progress")
+	}
+	if c.TUI && c.Format != FormatTree {
+		return fmt.Errorf("--tui cannot be combined with --format %s", c.Format)
+	}
+	if (c.Format == FormatCSV || c.Format == FormatMarkdown) && (c.Analyze || c.ByRole || c.CountRuns > 1 || c.Visualize != "") {
+		return fmt.Errorf("--format %s is only supported for count results", c.Format)
+	}
+	if c.Format == FormatSARIF && (!c.Analyze || c.Visualize != "") {
+		return fmt.Errorf("--format sarif requires --analyze")
+	}
+	if c.GroupBy != "" && c.GroupBy != GroupByExtension {
+		return fmt.Errorf("invalid group-by value: %s (must be 'ext')", c.GroupBy)
+	}
+	if c.Top < 0 {
+		return fmt.Errorf("top must be non-negative")
+	}
+	if c.Top > 0 && ((c.Format != FormatTree && c.Format != FormatJSON) || c.TUI || c.CostOnly || c.JSONTree || c.Analyze) {
+		return fmt.Errorf("--top supports tree and json output and cannot be combined with --tui, --cost-only, --json-tree or --analyze")
+	}
+	if c.Sort != "" && c.Sort != SortName && c.Sort != SortTokens && c.Sort != SortCost {
+		return fmt.Errorf("invalid sort: %s (must be 'name', 'tokens' or 'cost')", c.Sort)
+	}
+	if c.ContentType != "" && c.ContentType != ContentTypeHTML && c.ContentType != ContentTypeMarkdown && c.ContentType != ContentTypeText {
+		return fmt.Errorf("invalid content type: %s (must be 'html', 'markdown', or 'text')", c.ContentType)
+	}
 	if c.Visualize != "" && !IsValidVisualizationMode(c.Visualize) {
 		return fmt.Errorf("invalid visualization mode: %s (must be 'basic', 'interactive', 'html', 'json', or 'plain')", c.Visualize)
 	}
 	return nil
 }
+
+// Redacted returns a copy of the configuration that is safe to print: the system prompt,
+// which may contain confidential instructions, is replaced by its length, and credentials
+// in the API base URL are masked.
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.APIBaseURL = redactURL(c.APIBaseURL)
+	if c.SystemPrompt != "" {
+		placeholder := fmt.Sprintf("<redacted: %d characters>", len(c.SystemPrompt))
+		redacted.SystemPrompt = placeholder
+		// A literal --system value is the prompt itself
+		if c.System == c.SystemPrompt {
+			redacted.System = placeholder
+		}
+	}
+	return redacted
+}
+
+// redactURL masks the userinfo and query parameter values of a URL, where gateways often
+// carry tokens. A URL that doesn't parse is hidden entirely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<redacted>"
+	}
+	if u.User != nil {
+		u.User = url.User("redacted")
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"redacted"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
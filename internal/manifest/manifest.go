@@ -0,0 +1,132 @@
+// Package manifest reads YAML manifests of prompt files and writes token counts back into them.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// pathKey is the entry field holding the file path
+	pathKey = "path"
+	// indent is the indentation used when writing the manifest back
+	indent = 2
+	// filePerm is the permission used when rewriting the manifest
+	filePerm = 0644
+)
+
+// Manifest is a parsed YAML manifest. The document tree is kept as-is so fields and comments
+// other than the annotations survive a round trip.
+type Manifest struct {
+	path string
+	doc  yaml.Node
+}
+
+// Entry is a manifest mapping with a path field
+type Entry struct {
+	// Path is the entry's file path as written in the manifest
+	Path string
+	// ResolvedPath is Path resolved relative to the manifest's directory
+	ResolvedPath string
+
+	node *yaml.Node
+}
+
+// Load reads and parses a YAML manifest
+func Load(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	m := &Manifest{path: path}
+	if err := yaml.Unmarshal(content, &m.doc); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Entries returns every mapping in the manifest that has a scalar path field, in document order.
+// Entries may appear in a top-level list or in lists nested under any key.
+func (m *Manifest) Entries() []*Entry {
+	var entries []*Entry
+	baseDir := filepath.Dir(m.path)
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			if value := mappingValue(node, pathKey); value != nil && value.Kind == yaml.ScalarNode && value.Value != "" {
+				resolved := value.Value
+				if !filepath.IsAbs(resolved) {
+					resolved = filepath.Join(baseDir, resolved)
+				}
+				entries = append(entries, &Entry{Path: value.Value, ResolvedPath: resolved, node: node})
+				return
+			}
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(&m.doc)
+
+	return entries
+}
+
+// Annotate sets the tokens, cost and counted_at fields of the entry, replacing existing values
+func (e *Entry) Annotate(tokens int, cost float64, countedAt time.Time) {
+	setScalar(e.node, "tokens", strconv.Itoa(tokens), "!!int")
+	setScalar(e.node, "cost", strconv.FormatFloat(cost, 'f', -1, 64), "!!float")
+	setScalar(e.node, "counted_at", countedAt.UTC().Format(time.RFC3339), "!!timestamp")
+}
+
+// Save writes the manifest back to the file it was loaded from
+func (m *Manifest) Save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(&m.doc); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(m.path, buf.Bytes(), filePerm); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setScalar sets key to a scalar value in a mapping node, appending the key if missing
+func setScalar(node *yaml.Node, key, value, tag string) {
+	if existing := mappingValue(node, key); existing != nil {
+		existing.Kind = yaml.ScalarNode
+		existing.Tag = tag
+		existing.Value = value
+		existing.Style = 0
+		existing.Content = nil
+		return
+	}
+
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+	)
+}
@@ -2,11 +2,13 @@ package output
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/iota-uz/cc-token/internal/analyzer"
 	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/utils"
 )
 
 const (
@@ -67,6 +69,9 @@ func (f *AnalysisFormatter) printHeader(filename string, analysis *analyzer.Anal
 	subtitle := fmt.Sprintf("Total: %d tokens across %d lines (%.1f tokens/line)",
 		analysis.TotalTokens, analysis.TotalLines, analysis.AvgTokensPerLine)
 	efficiency := fmt.Sprintf("Efficiency Score: %d/100", analysis.EfficiencyScore)
+	if analysis.SystemTokens > 0 {
+		subtitle += fmt.Sprintf("\nSystem prompt: %d tokens (client-side estimate, added to every request)", analysis.SystemTokens)
+	}
 
 	if f.useColor {
 		color.New(color.Bold, color.FgCyan).Println(title)
@@ -119,6 +124,7 @@ func (f *AnalysisFormatter) printCategoryBreakdown(analysis *analyzer.Analysis)
 		{"Code Blocks", analysis.CategoryBreakdown.CodeBlocks, stats.CodeBlocks},
 		{"URLs", analysis.CategoryBreakdown.URLs, stats.URLs},
 		{"Formatting", analysis.CategoryBreakdown.Formatting, stats.Formatting},
+		{"Tables", analysis.CategoryBreakdown.Tables, stats.Tables},
 		{"Whitespace", analysis.CategoryBreakdown.Whitespace, stats.Whitespace},
 	}
 
@@ -185,7 +191,7 @@ func (f *AnalysisFormatter) printQuickWins(analysis *analyzer.Analysis) {
 	for _, rec := range analysis.QuickWins {
 		if f.useColor {
 			title := fmt.Sprintf("• %s", rec.Title)
-			savings := fmt.Sprintf("  Savings: ~%d tokens (%.1f%%)", rec.EstimatedSave, rec.SavePercentage)
+			savings := "  Savings: " + formatSavings(rec)
 			color.New(color.Bold, color.FgGreen).Println(title)
 			color.New(color.Faint).Println(savings)
 
@@ -203,8 +209,7 @@ func (f *AnalysisFormatter) printQuickWins(analysis *analyzer.Analysis) {
 			}
 		} else {
 			// Plain mode: compact single-line format
-			fmt.Printf("%s (Impact: %d tokens, %.1f%%)\n",
-				rec.Title, rec.EstimatedSave, rec.SavePercentage)
+			fmt.Printf("%s (Impact: %s)\n", rec.Title, formatSavings(rec))
 		}
 	}
 }
@@ -268,8 +273,7 @@ func (f *AnalysisFormatter) printRecommendations(analysis *analyzer.Analysis) {
 	for _, rec := range analysis.Recommendations {
 		if f.useColor {
 			title := fmt.Sprintf("• %s", rec.Title)
-			savings := fmt.Sprintf("  Savings: ~%d tokens (%.1f%%)",
-				rec.EstimatedSave, rec.SavePercentage)
+			savings := "  Savings: " + formatSavings(rec)
 
 			if rec.Priority == 1 {
 				color.New(color.Bold, color.FgGreen).Println(title)
@@ -313,8 +317,7 @@ func (f *AnalysisFormatter) printRecommendations(analysis *analyzer.Analysis) {
 				priority = "[LOW] "
 			}
 
-			fmt.Printf("%s%s (Impact: %d tokens, %.1f%%",
-				priority, rec.Title, rec.EstimatedSave, rec.SavePercentage)
+			fmt.Printf("%s%s (Impact: %s", priority, rec.Title, formatSavings(rec))
 
 			if len(rec.AffectedLines) > 0 {
 				fmt.Printf(", %d lines", len(rec.AffectedLines))
@@ -324,6 +327,15 @@ func (f *AnalysisFormatter) printRecommendations(analysis *analyzer.Analysis) {
 	}
 }
 
+// formatSavings describes a recommendation's estimated token savings. Recommendations that
+// improve accuracy or safety rather than size say so instead of claiming ~0 tokens.
+func formatSavings(rec *analyzer.Recommendation) string {
+	if rec.EstimatedSave <= 0 {
+		return "no token savings"
+	}
+	return fmt.Sprintf("~%d tokens (%.1f%%)", rec.EstimatedSave, rec.SavePercentage)
+}
+
 // IssueSection represents a formatted issue section
 type IssueSection struct {
 	Title       string
@@ -383,6 +395,16 @@ func (f *AnalysisFormatter) printLLMSafetyAnalysis(analysis *analyzer.Analysis)
 		trojanSourceMsg = fmt.Sprintf("CRITICAL: %d Trojan Source attack patterns detected!", trojanSourceCount)
 	}
 
+	injectionMsg := ""
+	if len(safetyAnalysis.InjectionIssues) > 0 {
+		injectionMsg = fmt.Sprintf("CRITICAL: %d prompt-injection trigger phrases detected!", len(safetyAnalysis.InjectionIssues))
+	}
+
+	secretMsg := ""
+	if len(safetyAnalysis.SecretIssues) > 0 {
+		secretMsg = fmt.Sprintf("CRITICAL: %d secret(s) or credential(s) detected!", len(safetyAnalysis.SecretIssues))
+	}
+
 	sections := []IssueSection{
 		{
 			Title:  "emoji issues (tokenization cost)",
@@ -441,10 +463,24 @@ func (f *AnalysisFormatter) printLLMSafetyAnalysis(analysis *analyzer.Analysis)
 			Fix:    "Clarify instructions; remove sycophantic framing",
 		},
 		{
-			Title:  "unformatted large numbers",
+			Title:       "prompt-injection trigger phrases",
+			Count:       len(safetyAnalysis.InjectionIssues),
+			Impact:      "Attempt to override system instructions (OWASP LLM01)",
+			Fix:         "Remove the phrases or wrap untrusted content in clear delimiters",
+			CriticalMsg: injectionMsg,
+		},
+		{
+			Title:       "secrets and credentials",
+			Count:       len(safetyAnalysis.SecretIssues),
+			Impact:      "Leak credentials to the model provider and logs (OWASP LLM02)",
+			Fix:         "Remove or redact the keys and rotate any that were exposed",
+			CriticalMsg: secretMsg,
+		},
+		{
+			Title:  "unformatted or unit-adjacent numbers",
 			Count:  len(safetyAnalysis.NumberFormatIssues),
 			Impact: "Reduces arithmetic accuracy by 8-15%",
-			Fix:    "Format with commas (1,234,567 instead of 1234567)",
+			Fix:    "Format with commas and separate units (1,234,567 kg instead of 1234567kg)",
 		},
 		{
 			Title:  "OOV strings (URLs, hashes, IDs, tokens)",
@@ -482,3 +518,72 @@ func (f *AnalysisFormatter) printSectionHeader(title string) {
 		fmt.Printf("## %s\n", title)
 	}
 }
+
+// FormatDirectoryAnalysis outputs a per-file summary of a directory analysis followed by the
+// most expensive lines across all files
+func (f *AnalysisFormatter) FormatDirectoryAnalysis(dir *analyzer.DirectoryAnalysis, path string, cfg *config.Config) error {
+	title := fmt.Sprintf("Token Optimization Analysis: %s", path)
+	subtitle := fmt.Sprintf("Total: %d tokens across %d files", dir.TotalTokens, len(dir.Files))
+	if f.useColor {
+		color.New(color.Bold, color.FgCyan).Println(title)
+		color.New(color.FgWhite).Println(subtitle)
+	} else {
+		fmt.Println(title)
+		fmt.Println(subtitle)
+	}
+	fmt.Println()
+
+	// Per-file summary, most expensive first
+	files := make([]*analyzer.FileAnalysis, len(dir.Files))
+	copy(files, dir.Files)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Analysis.TotalTokens > files[j].Analysis.TotalTokens
+	})
+
+	f.printSectionHeader("FILES")
+	for _, file := range files {
+		fmt.Printf("%-50s %8d tokens  score %3d/100  ~%d tokens savable\n",
+			file.Path, file.Analysis.TotalTokens, file.Analysis.EfficiencyScore, file.Analysis.PotentialSavings)
+	}
+	fmt.Println()
+
+	topLines := dir.GetTopExpensiveLines(topExpensiveN)
+	totalTopTokens := 0
+	for _, line := range topLines {
+		totalTopTokens += line.Tokens
+	}
+	percentage := 0.0
+	if dir.TotalTokens > 0 {
+		percentage = float64(totalTopTokens) / float64(dir.TotalTokens) * 100
+	}
+
+	f.printSectionHeader(fmt.Sprintf("TOP EXPENSIVE LINES ACROSS FILES (%d lines, %d tokens, %.1f%%)",
+		len(topLines), totalTopTokens, percentage))
+	for _, line := range topLines {
+		if line.Tokens < 3 {
+			break // Stop showing very low token lines
+		}
+
+		location := fmt.Sprintf("%s:%d", line.Path, line.LineNumber)
+		tokenStr := fmt.Sprintf("%d tokens", line.Tokens)
+		preview := strings.TrimSpace(utils.Truncate(line.Content, maxLinePreview))
+
+		if f.useColor {
+			color.New(color.FgYellow).Printf("%-40s ", location)
+			color.New(color.FgGreen).Println(tokenStr)
+			color.New(color.FgWhite, color.Faint).Printf("  %s\n", preview)
+		} else {
+			fmt.Printf("%-40s %s\n", location, tokenStr)
+			fmt.Printf("  %s\n", preview)
+		}
+	}
+	fmt.Println()
+
+	savingsPct := 0.0
+	if dir.TotalTokens > 0 {
+		savingsPct = float64(dir.PotentialSavings) / float64(dir.TotalTokens) * 100
+	}
+	f.printSectionHeader(fmt.Sprintf("TOTAL POTENTIAL SAVINGS: ~%d tokens (%.1f%% reduction)", dir.PotentialSavings, savingsPct))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// AnalysisJSONFormatter formats token optimization analysis as JSON
+type AnalysisJSONFormatter struct{}
+
+// NewAnalysisJSONFormatter creates a new analysis JSON formatter
+func NewAnalysisJSONFormatter() *AnalysisJSONFormatter {
+	return &AnalysisJSONFormatter{}
+}
+
+// analysisJSON is the serialized shape of an analysis report
+type analysisJSON struct {
+	File             string                      `json:"file"`
+	TotalTokens      int                         `json:"total_tokens"`
+	TotalLines       int                         `json:"total_lines"`
+	TotalChars       int                         `json:"total_chars"`
+	EfficiencyScore  int                         `json:"efficiency_score"`
+	PotentialSavings int                         `json:"potential_savings"`
+	WasteTokens      int                         `json:"waste_tokens"`
+	SystemTokens     int                         `json:"system_tokens,omitempty"`
+	Recommendations  []*analyzer.Recommendation  `json:"recommendations"`
+	QuickWins        []*analyzer.Recommendation  `json:"quick_wins"`
+	Percentiles      *analyzer.PercentileStats   `json:"percentiles,omitempty"`
+	LLMSafety        *analyzer.LLMSafetyAnalysis `json:"llm_safety,omitempty"`
+}
+
+// FormatAnalysis outputs the analysis summary, recommendations, per-line token percentiles and
+// LLM safety issues as JSON. Quick wins are flagged on each recommendation and also listed
+// separately.
+func (f *AnalysisJSONFormatter) FormatAnalysis(analysis *analyzer.Analysis, filename string, cfg *config.Config) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newAnalysisJSON(analysis, filename))
+}
+
+// newAnalysisJSON builds the serialized report for a single file analysis
+func newAnalysisJSON(analysis *analyzer.Analysis, filename string) analysisJSON {
+	report := analysisJSON{
+		File:             filename,
+		TotalTokens:      analysis.TotalTokens,
+		TotalLines:       analysis.TotalLines,
+		TotalChars:       analysis.TotalChars,
+		EfficiencyScore:  analysis.EfficiencyScore,
+		PotentialSavings: analysis.PotentialSavings,
+		WasteTokens:      analysis.WasteTokens,
+		SystemTokens:     analysis.SystemTokens,
+		Recommendations:  analysis.Recommendations,
+		QuickWins:        analysis.QuickWins,
+		Percentiles:      analysis.Percentiles,
+		LLMSafety:        analysis.LLMSafetyAnalysis,
+	}
+
+	// Keep empty lists as [] rather than null for consumers
+	if report.Recommendations == nil {
+		report.Recommendations = []*analyzer.Recommendation{}
+	}
+	if report.QuickWins == nil {
+		report.QuickWins = []*analyzer.Recommendation{}
+	}
+
+	return report
+}
+
+// directoryAnalysisJSON is the serialized shape of a directory analysis report
+type directoryAnalysisJSON struct {
+	Path              string              `json:"path"`
+	TotalTokens       int                 `json:"total_tokens"`
+	PotentialSavings  int                 `json:"potential_savings"`
+	Files             []analysisJSON      `json:"files"`
+	TopExpensiveLines []expensiveLineJSON `json:"top_expensive_lines"`
+}
+
+// expensiveLineJSON is a single line in the cross-file top expensive lines list
+type expensiveLineJSON struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Tokens  int    `json:"tokens"`
+	Content string `json:"content"`
+}
+
+// FormatDirectoryAnalysis outputs per-file analysis summaries and the most expensive lines
+// across all files as JSON
+func (f *AnalysisJSONFormatter) FormatDirectoryAnalysis(dir *analyzer.DirectoryAnalysis, path string, cfg *config.Config) error {
+	report := directoryAnalysisJSON{
+		Path:              path,
+		TotalTokens:       dir.TotalTokens,
+		PotentialSavings:  dir.PotentialSavings,
+		Files:             make([]analysisJSON, 0, len(dir.Files)),
+		TopExpensiveLines: make([]expensiveLineJSON, 0),
+	}
+
+	for _, file := range dir.Files {
+		report.Files = append(report.Files, newAnalysisJSON(file.Analysis, file.Path))
+	}
+	for _, line := range dir.GetTopExpensiveLines(topExpensiveN) {
+		report.TopExpensiveLines = append(report.TopExpensiveLines, expensiveLineJSON{
+			File:    line.Path,
+			Line:    line.LineNumber,
+			Tokens:  line.Tokens,
+			Content: line.Content,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+func TestAnalysisJSONFlagsQuickWins(t *testing.T) {
+	quickWin := &analyzer.Recommendation{Title: "Remove zero-width characters", Priority: 1, Difficulty: "easy", IsQuickWin: true}
+	longTerm := &analyzer.Recommendation{Title: "Restructure repeated sections", Priority: 2, Difficulty: "hard"}
+	analysis := &analyzer.Analysis{
+		TotalTokens:     100,
+		Recommendations: []*analyzer.Recommendation{quickWin, longTerm},
+		QuickWins:       []*analyzer.Recommendation{quickWin},
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		if err := NewAnalysisJSONFormatter().FormatAnalysis(analysis, "prompt.txt", &config.Config{}); err != nil {
+			t.Errorf("FormatAnalysis: %v", err)
+		}
+	})
+
+	type recommendation struct {
+		Title      string `json:"title"`
+		Priority   int    `json:"priority"`
+		Difficulty string `json:"difficulty"`
+		IsQuickWin bool   `json:"is_quick_win"`
+	}
+	var report struct {
+		Recommendations []recommendation `json:"recommendations"`
+		QuickWins       []recommendation `json:"quick_wins"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+
+	want := []recommendation{
+		{"Remove zero-width characters", 1, "easy", true},
+		{"Restructure repeated sections", 2, "hard", false},
+	}
+	if len(report.Recommendations) != len(want) {
+		t.Fatalf("recommendations = %+v, want %+v", report.Recommendations, want)
+	}
+	for i := range want {
+		if report.Recommendations[i] != want[i] {
+			t.Errorf("recommendation %d = %+v, want %+v", i, report.Recommendations[i], want[i])
+		}
+	}
+	if len(report.QuickWins) != 1 || report.QuickWins[0] != want[0] {
+		t.Errorf("quick_wins = %+v, want only %+v", report.QuickWins, want[0])
+	}
+}
+
+func TestAnalysisJSONEmptyLists(t *testing.T) {
+	data, err := json.Marshal(newAnalysisJSON(&analyzer.Analysis{}, "empty.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report map[string]json.RawMessage
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"recommendations", "quick_wins"} {
+		if string(report[key]) != "[]" {
+			t.Errorf("%s = %s, want []", key, report[key])
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+)
+
+func TestFormatSavings(t *testing.T) {
+	tests := []struct {
+		rec  analyzer.Recommendation
+		want string
+	}{
+		{analyzer.Recommendation{EstimatedSave: 12, SavePercentage: 3.04}, "~12 tokens (3.0%)"},
+		{analyzer.Recommendation{EstimatedSave: 0}, "no token savings"},
+		{analyzer.Recommendation{EstimatedSave: -2, SavePercentage: -0.5}, "no token savings"},
+	}
+	for _, tt := range tests {
+		if got := formatSavings(&tt.rec); got != tt.want {
+			t.Errorf("formatSavings(%d) = %q, want %q", tt.rec.EstimatedSave, got, tt.want)
+		}
+	}
+}
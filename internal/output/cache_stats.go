@@ -0,0 +1,51 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/cache"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// FormatCacheStats outputs cache statistics in text or JSON format. A missing cache
+// reports zeros.
+func FormatCacheStats(stats cache.Stats, cfg *config.Config) error {
+	if cfg.Format == config.FormatJSON {
+		item := map[string]interface{}{
+			"path":         stats.Path,
+			"entries":      stats.Entries,
+			"models":       stats.Models,
+			"total_tokens": stats.TotalTokens,
+			"size_bytes":   stats.SizeBytes,
+			"oldest":       nil,
+			"newest":       nil,
+		}
+		if !stats.Oldest.IsZero() {
+			item["oldest"] = stats.Oldest.UTC().Format(time.RFC3339)
+			item["newest"] = stats.Newest.UTC().Format(time.RFC3339)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(item)
+	}
+
+	fmt.Printf("Cache: %s\n", stats.Path)
+	fmt.Printf("Entries: %d (across %d models)\n", stats.Entries, stats.Models)
+	fmt.Printf("Total cached tokens: %d\n", stats.TotalTokens)
+	fmt.Printf("Oldest entry: %s\n", formatCacheTime(stats.Oldest))
+	fmt.Printf("Newest entry: %s\n", formatCacheTime(stats.Newest))
+	fmt.Printf("Size on disk: %d bytes\n", stats.SizeBytes)
+	return nil
+}
+
+// formatCacheTime renders an entry timestamp, or "-" when unknown
+func formatCacheTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
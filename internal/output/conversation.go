@@ -0,0 +1,95 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+// roleOrder lists the standard roles in display order; other roles follow alphabetically
+var roleOrder = map[string]int{
+	api.RoleSystem: 0,
+	"user":         1,
+	"assistant":    2,
+}
+
+// ConversationFormatter formats per-role token counts for a conversation file
+type ConversationFormatter struct {
+	pricingService *pricing.Pricer
+}
+
+// NewConversationFormatter creates a new conversation formatter
+func NewConversationFormatter(pricingService *pricing.Pricer) *ConversationFormatter {
+	return &ConversationFormatter{pricingService: pricingService}
+}
+
+// FormatConversation outputs per-role subtotals and the API total in text or JSON format
+func (f *ConversationFormatter) FormatConversation(path string, count *api.ConversationCount, cfg *config.Config) error {
+	if cfg.Format == config.FormatJSON {
+		item := map[string]interface{}{
+			"path":         path,
+			"total_tokens": count.Total,
+			"by_role":      count.ByRole,
+		}
+		if cfg.Offline {
+			item["approximate"] = true
+		}
+		if cfg.ShowCost {
+			item["estimated_cost"] = f.pricingService.CalculateCost(count.Total, cfg.Model)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(item)
+	}
+
+	fmt.Println(path)
+	for _, role := range sortedRoles(count.ByRole) {
+		tokens := count.ByRole[role]
+		share := 0.0
+		if count.Total > 0 {
+			share = float64(tokens) / float64(count.Total) * 100
+		}
+		fmt.Printf("  %s: %d tokens (%.1f%%)\n", role, tokens, share)
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Total: %d tokens (includes message overhead)\n", count.Total)
+	if cfg.ShowCost {
+		cost := f.pricingService.CalculateCost(count.Total, cfg.Model)
+		fmt.Printf("Estimated cost: %s\n", f.pricingService.FormatCost(cost, cfg.CostPrecision))
+	}
+	if cfg.Offline {
+		fmt.Println(offlineNote)
+	}
+
+	return nil
+}
+
+// sortedRoles orders roles as system, user, assistant, then any others alphabetically
+func sortedRoles(byRole map[string]int) []string {
+	roles := make([]string, 0, len(byRole))
+	for role := range byRole {
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		oi, iKnown := roleOrder[roles[i]]
+		oj, jKnown := roleOrder[roles[j]]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if iKnown && oi != oj {
+			return oi < oj
+		}
+		return roles[i] < roles[j]
+	})
+
+	return roles
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// CostOnlyFormatter prints only the total estimated cost as a bare number, for scripts
+type CostOnlyFormatter struct {
+	pricingService *pricing.Pricer
+}
+
+// NewCostOnlyFormatter creates a new cost-only formatter
+func NewCostOnlyFormatter(pricingService *pricing.Pricer) *CostOnlyFormatter {
+	return &CostOnlyFormatter{pricingService: pricingService}
+}
+
+// Format prints the total cost of all successfully counted files to stdout, priced with prompt
+// caching when --cache-write/--cache-read are set. Failed files are reported on stderr so
+// stdout stays parseable.
+func (f *CostOnlyFormatter) Format(results []*processor.Result, cfg *config.Config) error {
+	var walk func(result *processor.Result)
+	walk = func(result *processor.Result) {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s: ERROR - %v\n", result.Path, result.Error)
+		}
+		for _, child := range result.Children {
+			walk(child)
+		}
+	}
+	for _, result := range results {
+		walk(result)
+	}
+
+	cost := f.pricingService.CalculateCost(totalTokens(results), cfg.Model)
+	if caching := computePromptCachingCost(totalTokens(results), cfg, f.pricingService); caching != nil {
+		cost = caching.Total
+	}
+	fmt.Println(pricing.FormatAmount(cost, cfg.CostPrecision))
+	if cfg.Offline {
+		fmt.Fprintln(os.Stderr, offlineNote)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// CSVFormatter formats output as CSV with one row per file, for spreadsheets
+type CSVFormatter struct {
+	pricingService *pricing.Pricer
+}
+
+// NewCSVFormatter creates a new CSV formatter
+func NewCSVFormatter(pricingService *pricing.Pricer) *CSVFormatter {
+	return &CSVFormatter{pricingService: pricingService}
+}
+
+// Format writes a header and one row per file, flattening directories. The estimated_cost
+// and hash columns are included when --show-cost and --emit-hash are set. Status is ok,
+// cached, truncated, chunked, skipped_binary or error.
+func (f *CSVFormatter) Format(results []*processor.Result, cfg *config.Config) error {
+	w := csv.NewWriter(os.Stdout)
+
+	header := []string{"path", "tokens", "line_count"}
+	if cfg.ShowCost {
+		header = append(header, "estimated_cost")
+	}
+	if cfg.EmitHash {
+		header = append(header, "hash")
+	}
+	header = append(header, "status", "error")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	var rows func(results []*processor.Result) error
+	rows = func(results []*processor.Result) error {
+		for _, result := range results {
+			if result.IsDir {
+				if err := rows(result.Children); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := w.Write(f.row(result, cfg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := rows(results); err != nil {
+		return err
+	}
+
+	w.Flush()
+	if cfg.Offline {
+		// Keep stdout parseable
+		fmt.Fprintln(os.Stderr, offlineNote)
+	}
+	return w.Error()
+}
+
+// row builds the CSV record for a single file, matching the header written by Format
+func (f *CSVFormatter) row(result *processor.Result, cfg *config.Config) []string {
+	record := []string{result.Path, strconv.Itoa(result.Tokens), strconv.Itoa(result.LineCount)}
+	if cfg.ShowCost {
+		cost := f.pricingService.CalculateCost(result.Tokens, cfg.Model)
+		record = append(record, strconv.FormatFloat(cost, 'f', -1, 64))
+	}
+	if cfg.EmitHash {
+		record = append(record, result.Hash)
+	}
+
+	status, errMsg := "ok", ""
+	switch {
+	case result.Error != nil:
+		status, errMsg = "error", result.Error.Error()
+	case result.SkippedBinary:
+		status = "skipped_binary"
+	case result.Truncated:
+		status = "truncated"
+	case result.Chunks > 0:
+		status = "chunked"
+	case result.Cached:
+		status = "cached"
+	}
+	return append(record, status, errMsg)
+}
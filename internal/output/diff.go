@@ -0,0 +1,152 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// DiffFormatter formats the token difference between two versions of a file
+type DiffFormatter struct {
+	pricingService *pricing.Pricer
+	useColor       bool
+}
+
+// NewDiffFormatter creates a new diff formatter
+func NewDiffFormatter(pricingService *pricing.Pricer, useColor bool) *DiffFormatter {
+	return &DiffFormatter{pricingService: pricingService, useColor: useColor}
+}
+
+// FormatDiff outputs both counts and the change between them in tokens, cost and percent.
+// lines holds the per-line changes (only with --analyze) and may be nil.
+func (f *DiffFormatter) FormatDiff(oldResult, newResult *processor.Result, lines []*analyzer.LineDelta, cfg *config.Config) error {
+	delta := newResult.Tokens - oldResult.Tokens
+	oldCost := f.pricingService.CalculateCost(oldResult.Tokens, cfg.Model)
+	newCost := f.pricingService.CalculateCost(newResult.Tokens, cfg.Model)
+
+	if cfg.Format == config.FormatJSON {
+		oldItem := map[string]interface{}{"path": oldResult.Path, "tokens": oldResult.Tokens}
+		newItem := map[string]interface{}{"path": newResult.Path, "tokens": newResult.Tokens}
+		item := map[string]interface{}{
+			"old":         oldItem,
+			"new":         newItem,
+			"token_delta": delta,
+		}
+		if cfg.Offline {
+			item["approximate"] = true
+		}
+		if oldResult.Tokens > 0 {
+			item["percent_change"] = percentChange(oldResult.Tokens, newResult.Tokens)
+		}
+		if cfg.ShowCost {
+			oldItem["estimated_cost"] = oldCost
+			newItem["estimated_cost"] = newCost
+			item["cost_delta"] = newCost - oldCost
+		}
+		if lines != nil {
+			changes := make([]map[string]interface{}, 0, len(lines))
+			for _, line := range lines {
+				change := map[string]interface{}{"content": line.Content, "tokens": line.Tokens}
+				if line.OldLine > 0 {
+					change["old_line"] = line.OldLine
+				}
+				if line.NewLine > 0 {
+					change["new_line"] = line.NewLine
+				}
+				changes = append(changes, change)
+			}
+			item["line_changes"] = changes
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(item)
+	}
+
+	for _, side := range []struct {
+		result *processor.Result
+		cost   float64
+	}{{oldResult, oldCost}, {newResult, newCost}} {
+		line := fmt.Sprintf("%s: %d tokens", side.result.Path, side.result.Tokens)
+		if cfg.ShowCost {
+			line += fmt.Sprintf(" (%s)", f.pricingService.FormatCost(side.cost, cfg.CostPrecision))
+		}
+		fmt.Println(line)
+	}
+	fmt.Println(strings.Repeat("-", 50))
+
+	change := fmt.Sprintf("Change: %+d tokens", delta)
+	if oldResult.Tokens > 0 {
+		change += fmt.Sprintf(" (%+.1f%%)", percentChange(oldResult.Tokens, newResult.Tokens))
+	}
+	if cfg.ShowCost {
+		change += ", " + f.signedCost(newCost-oldCost, cfg.CostPrecision)
+	}
+	f.printChange(change, delta)
+
+	if cfg.Offline {
+		fmt.Println(offlineNote)
+	}
+
+	if lines != nil {
+		f.printLineChanges(lines)
+	}
+	return nil
+}
+
+// printLineChanges lists added and removed lines with the tokens each gained or saved
+func (f *DiffFormatter) printLineChanges(lines []*analyzer.LineDelta) {
+	fmt.Println()
+	if len(lines) == 0 {
+		fmt.Println("No line changes")
+		return
+	}
+	fmt.Println("Line changes (client-side estimate):")
+
+	for _, line := range lines {
+		marker, number := "+", line.NewLine
+		if line.OldLine > 0 {
+			marker, number = "-", line.OldLine
+		}
+
+		preview := line.Content
+		if len(preview) > maxLinePreview {
+			preview = preview[:maxLinePreview] + "..."
+		}
+		preview = strings.TrimSpace(preview)
+
+		f.printChange(fmt.Sprintf("  %s L%-5d %+5d  %s", marker, number, line.Tokens, preview), line.Tokens)
+	}
+}
+
+// printChange prints a line colored by direction: green for savings, red for growth
+func (f *DiffFormatter) printChange(text string, delta int) {
+	switch {
+	case !f.useColor || delta == 0:
+		fmt.Println(text)
+	case delta < 0:
+		color.New(color.FgGreen).Println(text)
+	default:
+		color.New(color.FgRed).Println(text)
+	}
+}
+
+// signedCost formats a cost difference with an explicit sign
+func (f *DiffFormatter) signedCost(cost float64, precision int) string {
+	if cost < 0 {
+		return "-" + f.pricingService.FormatCost(-cost, precision)
+	}
+	return "+" + f.pricingService.FormatCost(cost, precision)
+}
+
+// percentChange returns the relative change from oldTokens to newTokens in percent
+func percentChange(oldTokens, newTokens int) float64 {
+	return float64(newTokens-oldTokens) / float64(oldTokens) * 100
+}
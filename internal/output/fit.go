@@ -0,0 +1,44 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// FormatFit outputs where a file must be cut to fit a token budget, in text or JSON format
+func FormatFit(path string, fit *analyzer.BudgetFit, cfg *config.Config) error {
+	if cfg.Format == config.FormatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"path":            path,
+			"budget":          fit.Budget,
+			"total_tokens":    fit.TotalTokens,
+			"total_lines":     fit.TotalLines,
+			"fits":            fit.Fits,
+			"cut_offset":      fit.CutOffset,
+			"cut_line":        fit.CutLine,
+			"line_offset":     fit.LineOffset,
+			"fitting_lines":   fit.FittingLines,
+			"fitting_tokens":  fit.FittingTokens,
+			"leftover_tokens": fit.LeftoverTokens,
+			"leftover_lines":  fit.LeftoverLines,
+			"approximate":     true,
+		})
+	}
+
+	fmt.Printf("%s: %d tokens across %d lines (client-side tokenizer)\n", path, fit.TotalTokens, fit.TotalLines)
+	fmt.Printf("Budget: %d tokens\n", fit.Budget)
+	if fit.Fits {
+		fmt.Printf("Fits: the whole file, with %d tokens to spare\n", fit.Budget-fit.TotalTokens)
+		return nil
+	}
+	fmt.Printf("Budget reached: line %d, byte %d\n", fit.CutLine, fit.CutOffset)
+	fmt.Printf("Fits: the first %d lines (%d bytes, %d tokens)\n", fit.FittingLines, fit.LineOffset, fit.FittingTokens)
+	fmt.Printf("Left over: %d tokens, %d lines\n", fit.LeftoverTokens, fit.LeftoverLines)
+	return nil
+}
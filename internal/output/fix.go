@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+)
+
+// unfixedDetectors are the LLM safety detectors whose issues --fix reports but never
+// rewrites, because fixing them needs judgment
+var unfixedDetectors = []string{
+	"emoji",
+	"oov_strings",
+	"confusables",
+	"number_formatting",
+	"encoding",
+	"glitch_token",
+	"prompt_ambiguity",
+	"injection_phrase",
+	"secret",
+	"context_placement",
+}
+
+// FormatFixSummary writes what --fix changed in a file and which issues were left for review
+func FormatFixSummary(w io.Writer, path string, fix *analyzer.FixResult, analysis *analyzer.Analysis, written bool) {
+	var changes []string
+	if fix.InvisibleRemoved > 0 {
+		changes = append(changes, fmt.Sprintf("removed %d invisible character(s)", fix.InvisibleRemoved))
+	}
+	if fix.BiDiRemoved > 0 {
+		changes = append(changes, fmt.Sprintf("removed %d BiDi control character(s)", fix.BiDiRemoved))
+	}
+	if fix.NormalizedLines > 0 {
+		changes = append(changes, fmt.Sprintf("normalized %d line(s) to NFC", fix.NormalizedLines))
+	}
+	if fix.BlankLinesRemoved > 0 {
+		changes = append(changes, fmt.Sprintf("collapsed %d blank line(s)", fix.BlankLinesRemoved))
+	}
+
+	switch {
+	case len(changes) == 0:
+		fmt.Fprintf(w, "%s: nothing to fix\n", path)
+	case written:
+		fmt.Fprintf(w, "%s: fixed (%s)\n", path, strings.Join(changes, ", "))
+	default:
+		fmt.Fprintf(w, "%s: %s\n", path, strings.Join(changes, ", "))
+	}
+
+	var unfixed []string
+	for _, detector := range unfixedDetectors {
+		if count := analysis.LLMSafetyAnalysis.IssueCount(detector); count > 0 {
+			unfixed = append(unfixed, fmt.Sprintf("%d %s", count, detector))
+		}
+	}
+	if len(unfixed) > 0 {
+		fmt.Fprintf(w, "  Left for review: %s\n", strings.Join(unfixed, ", "))
+	}
+}
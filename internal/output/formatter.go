@@ -12,14 +12,25 @@ type Formatter interface {
 	Format(results []*processor.Result, cfg *config.Config) error
 }
 
-// OutputResults formats and outputs the token counting results in either tree or JSON format
-// based on the configuration.
+// OutputResults formats and outputs the token counting results in the configured --format,
+// as a bare cost with --cost-only, or as the most expensive files with --top.
 func OutputResults(results []*processor.Result, cfg *config.Config, pricingService *pricing.Pricer) error {
 	var formatter Formatter
 
-	if cfg.JSONOutput {
+	switch {
+	case cfg.CostOnly:
+		formatter = NewCostOnlyFormatter(pricingService)
+	case cfg.Quiet:
+		formatter = NewQuietFormatter()
+	case cfg.Top > 0:
+		formatter = NewTopFormatter(pricingService)
+	case cfg.Format == config.FormatJSON:
 		formatter = NewJSONFormatter(pricingService)
-	} else {
+	case cfg.Format == config.FormatCSV:
+		formatter = NewCSVFormatter(pricingService)
+	case cfg.Format == config.FormatMarkdown:
+		formatter = NewMarkdownFormatter(pricingService)
+	default:
 		formatter = NewTreeFormatter(pricingService)
 	}
 
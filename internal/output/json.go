@@ -24,39 +24,137 @@ func (f *JSONFormatter) Format(results []*processor.Result, cfg *config.Config)
 	output := make([]map[string]interface{}, 0, len(results))
 
 	for _, result := range results {
-		item := map[string]interface{}{
-			"path":   result.Path,
-			"tokens": result.Tokens,
+		if cfg.JSONTree {
+			output = append(output, f.treeItem(processor.Nest(result), cfg))
+		} else {
+			output = append(output, f.resultItem(result, cfg))
 		}
+	}
 
-		if result.Error != nil {
-			item["error"] = result.Error.Error()
-		}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
 
-		if result.Cached {
-			item["cached"] = true
+	// Summaries wrap the results in an object alongside the requested breakdowns
+	caching := computePromptCachingCost(totalTokens(results), cfg, f.pricingService)
+	if cfg.GroupBy == config.GroupByExtension || cfg.Stats || caching != nil {
+		envelope := map[string]interface{}{
+			"results":      output,
+			"total_tokens": totalTokens(results),
+		}
+		if cfg.GroupBy == config.GroupByExtension {
+			envelope["by_extension"] = f.extensionBreakdown(results, cfg)
 		}
+		if cfg.Stats {
+			envelope["stats"] = computeFileStats(results)
+		}
+		if totals := computeSystemPromptTotals(results); totals != nil {
+			envelope["system_prompt"] = totals
+		}
+		if caching != nil {
+			envelope["prompt_caching"] = caching
+		}
+		return encoder.Encode(envelope)
+	}
 
-		if result.IsDir {
-			item["type"] = "directory"
-			item["files"] = result.CountFiles()
-		} else {
-			item["type"] = "file"
-			// Add line metrics for files
-			if result.LineCount > 0 {
-				item["line_count"] = result.LineCount
-				item["avg_tokens_per_line"] = result.AvgTokensPerLine
-			}
+	return encoder.Encode(output)
+}
+
+// resultItem builds the JSON object for a single top-level result
+func (f *JSONFormatter) resultItem(result *processor.Result, cfg *config.Config) map[string]interface{} {
+	item := map[string]interface{}{
+		"path":   result.Path,
+		"tokens": result.Tokens,
+	}
+
+	if result.Error != nil {
+		item["error"] = result.Error.Error()
+	}
+
+	if result.Cached {
+		item["cached"] = true
+	}
+	if cfg.Offline {
+		item["approximate"] = true
+	}
+
+	if result.IsDir {
+		item["type"] = "directory"
+		item["files"] = result.CountFiles()
+		if skipped := result.CountSkippedBinary(); skipped > 0 {
+			item["skipped_binary"] = skipped
+		}
+		if cfg.EmitHash {
+			item["file_hashes"] = fileHashes(result)
 		}
+	} else {
+		item["type"] = "file"
+		if result.SkippedBinary {
+			item["skipped"] = "binary"
+		}
+		if result.Truncated {
+			item["truncated"] = true
+		}
+		if result.Chunks > 0 {
+			item["chunks"] = result.Chunks
+		}
+		if result.Image {
+			item["image"] = true
+		}
+		// Add line metrics for files
+		if result.LineCount > 0 {
+			item["line_count"] = result.LineCount
+			item["avg_tokens_per_line"] = result.AvgTokensPerLine
+		}
+		if result.Hash != "" {
+			item["hash"] = result.Hash
+		}
+		if result.SystemTokens > 0 {
+			item["system_tokens"] = result.SystemTokens
+			item["user_tokens"] = result.Tokens - result.SystemTokens
+		}
+	}
 
-		if cfg.ShowCost {
-			item["estimated_cost"] = f.pricingService.CalculateCost(result.Tokens, cfg.Model)
+	if cfg.ShowCost {
+		item["estimated_cost"] = f.pricingService.CalculateCost(result.Tokens, cfg.Model)
+		if code := f.pricingService.Currency().Code; code != pricing.DefaultCurrency {
+			item["currency"] = code
 		}
+	}
+
+	return item
+}
 
-		output = append(output, item)
+// treeItem builds a nested JSON object mirroring the result tree, with per-directory
+// subtotals and a children array instead of a flat file_hashes map
+func (f *JSONFormatter) treeItem(result *processor.Result, cfg *config.Config) map[string]interface{} {
+	item := f.resultItem(result, cfg)
+	if !result.IsDir {
+		return item
 	}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	delete(item, "file_hashes")
+	children := make([]map[string]interface{}, 0, len(result.Children))
+	for _, child := range result.Children {
+		children = append(children, f.treeItem(child, cfg))
+	}
+	item["children"] = children
+	return item
+}
+
+// fileHashes maps each successfully processed file under a directory to its content hash
+func fileHashes(dir *processor.Result) map[string]string {
+	hashes := make(map[string]string)
+	for _, file := range collectFiles([]*processor.Result{dir}) {
+		hashes[file.Path] = file.Hash
+	}
+	return hashes
+}
+
+// extensionBreakdown builds the per-extension totals with estimated costs
+func (f *JSONFormatter) extensionBreakdown(results []*processor.Result, cfg *config.Config) map[string]*ExtensionTotals {
+	groups := groupByExtension(results)
+	for _, totals := range groups {
+		totals.Cost = f.pricingService.CalculateCost(totals.Tokens, cfg.Model)
+	}
+	return groups
 }
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// markdownIndent indents nested paths; GitHub collapses leading spaces in table cells
+const markdownIndent = "&nbsp;&nbsp;"
+
+// MarkdownFormatter formats output as a GitHub-flavored Markdown table, for issues and PRs
+type MarkdownFormatter struct {
+	pricingService *pricing.Pricer
+}
+
+// NewMarkdownFormatter creates a new Markdown formatter
+func NewMarkdownFormatter(pricingService *pricing.Pricer) *MarkdownFormatter {
+	return &MarkdownFormatter{pricingService: pricingService}
+}
+
+// Format writes a table with path, tokens and (with --show-cost) cost columns. Directories are
+// nested with indented paths and subtotals, and a bold totals row closes the table.
+func (f *MarkdownFormatter) Format(results []*processor.Result, cfg *config.Config) error {
+	var sb strings.Builder
+
+	if cfg.ShowCost {
+		sb.WriteString("| Path | Tokens | Cost |\n|:-----|-------:|-----:|\n")
+	} else {
+		sb.WriteString("| Path | Tokens |\n|:-----|-------:|\n")
+	}
+
+	for _, result := range results {
+		if result.IsDir {
+			root := processor.Nest(result)
+			f.writeRows(&sb, root, result.Path+"/", 0, cfg)
+			continue
+		}
+		f.writeRows(&sb, result, result.Path, 0, cfg)
+	}
+
+	total := totalTokens(results)
+	fmt.Fprintf(&sb, "| **Total** | **%d** |", total)
+	if cfg.ShowCost {
+		fmt.Fprintf(&sb, " **%s** |", f.pricingService.FormatCost(f.pricingService.CalculateCost(total, cfg.Model), cfg.CostPrecision))
+	}
+	sb.WriteString("\n")
+	if cfg.Offline {
+		fmt.Fprintf(&sb, "\n_%s_\n", offlineNote)
+	}
+
+	_, err := os.Stdout.WriteString(sb.String())
+	return err
+}
+
+// writeRows writes the row for result, followed by its children one level deeper
+func (f *MarkdownFormatter) writeRows(sb *strings.Builder, result *processor.Result, name string, depth int, cfg *config.Config) {
+	tokens := fmt.Sprintf("%d", result.Tokens)
+	cost := ""
+	if cfg.ShowCost {
+		cost = f.pricingService.FormatCost(f.pricingService.CalculateCost(result.Tokens, cfg.Model), cfg.CostPrecision)
+	}
+	switch {
+	case result.Error != nil:
+		tokens, cost = "error: "+result.Error.Error(), ""
+	case result.SkippedBinary:
+		tokens, cost = "skipped (binary)", ""
+	case result.Truncated:
+		tokens += " (truncated)"
+	case result.Chunks > 0:
+		tokens += fmt.Sprintf(" (%d chunks, approximate)", result.Chunks)
+	case result.Image:
+		tokens += " (image)"
+	}
+
+	fmt.Fprintf(sb, "| %s%s | %s |", strings.Repeat(markdownIndent, depth), escapeMarkdownCell(name), escapeMarkdownCell(tokens))
+	if cfg.ShowCost {
+		fmt.Fprintf(sb, " %s |", cost)
+	}
+	sb.WriteString("\n")
+
+	for _, child := range result.Children {
+		childName := filepath.Base(child.Path)
+		if child.IsDir {
+			childName += "/"
+		}
+		f.writeRows(sb, child, childName, depth+1, cfg)
+	}
+}
+
+// escapeMarkdownCell escapes characters that would break a table cell
+func escapeMarkdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+func TestMarkdownFormatterGolden(t *testing.T) {
+	results := []*processor.Result{
+		{Path: "README.md", Tokens: 1000},
+		{
+			Path:  "docs",
+			IsDir: true,
+			Children: []*processor.Result{
+				{Path: "docs/guide.md", Tokens: 2000},
+				{Path: "docs/api/broken.md", Error: errors.New("permission denied")},
+				{Path: "docs/api/ref|v2.md", Tokens: 500, Truncated: true},
+			},
+			Tokens: 2500,
+		},
+	}
+	cfg := &config.Config{Model: pricing.DefaultModel, ShowCost: true, CostPrecision: 4}
+
+	stdout, _ := captureOutput(t, func() {
+		if err := NewMarkdownFormatter(pricing.New("")).Format(results, cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	want := `| Path | Tokens | Cost |
+|:-----|-------:|-----:|
+| README.md | 1000 | $0.0030 |
+| docs/ | 2500 | $0.0075 |
+| &nbsp;&nbsp;guide.md | 2000 | $0.0060 |
+| &nbsp;&nbsp;api/ | 500 | $0.0015 |
+| &nbsp;&nbsp;&nbsp;&nbsp;broken.md | error: permission denied |  |
+| &nbsp;&nbsp;&nbsp;&nbsp;ref\|v2.md | 500 (truncated) | $0.0015 |
+| **Total** | **3500** | **$0.0105** |
+`
+	if stdout != want {
+		t.Errorf("markdown output:\n%s\nwant:\n%s", stdout, want)
+	}
+}
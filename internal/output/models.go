@@ -0,0 +1,75 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+// modelPricePrecision is the number of decimal places for per-1M-token prices
+const modelPricePrecision = 2
+
+// FormatModels outputs the models with known pricing as a table or JSON. Prices are per 1M
+// tokens in the configured currency.
+func FormatModels(models []pricing.ModelInfo, pricingService *pricing.Pricer, cfg *config.Config) error {
+	if cfg.Format == config.FormatJSON {
+		items := make([]map[string]interface{}, 0, len(models))
+		for _, model := range models {
+			aliases := model.Aliases
+			if aliases == nil {
+				aliases = []string{}
+			}
+			items = append(items, map[string]interface{}{
+				"name":                    model.Name,
+				"aliases":                 aliases,
+				"family":                  model.Family,
+				"version":                 model.Version,
+				"input_per_million":       model.InputPrice,
+				"cache_write_per_million": model.CacheWritePrice,
+				"cache_read_per_million":  model.CacheReadPrice,
+			})
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"currency":      pricingService.Currency().Code,
+			"default_model": pricing.DefaultModel,
+			"models":        items,
+		})
+	}
+
+	rows := make([][]string, 0, len(models)+1)
+	rows = append(rows, []string{"Model", "Aliases", "Input", "Cache write", "Cache read"})
+	for _, model := range models {
+		name := model.Name
+		if name == pricing.DefaultModel {
+			name += " (default)"
+		}
+		rows = append(rows, []string{
+			name,
+			strings.Join(model.Aliases, ", "),
+			pricingService.FormatCost(model.InputPrice, modelPricePrecision),
+			pricingService.FormatCost(model.CacheWritePrice, modelPricePrecision),
+			pricingService.FormatCost(model.CacheReadPrice, modelPricePrecision),
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	fmt.Printf("Prices per 1M tokens (%s)\n\n", pricingService.Currency().Code)
+	for _, row := range rows {
+		fmt.Printf("%-*s  %-*s  %*s  %*s  %*s\n",
+			widths[0], row[0], widths[1], row[1], widths[2], row[2], widths[3], row[3], widths[4], row[4])
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/iota-uz/cc-token/internal/analyzer"
+)
+
+// PerLineFormatter prints a file with each line's token count in a gutter
+type PerLineFormatter struct {
+	useColor bool
+}
+
+// NewPerLineFormatter creates a new per-line formatter
+func NewPerLineFormatter(useColor bool) *PerLineFormatter {
+	return &PerLineFormatter{useColor: useColor}
+}
+
+// Format prints every line as "NNN | <line>", followed by the total. With color, counts in the
+// top third of the file's per-line maximum are red and the middle third yellow.
+func (f *PerLineFormatter) Format(path string, lines []*analyzer.LineInsight) error {
+	// The empty element after a trailing newline is not a line of its own
+	if n := len(lines); n > 1 && lines[n-1].Content == "" && lines[n-1].Tokens == 0 {
+		lines = lines[:n-1]
+	}
+
+	total, maxTokens := 0, 0
+	for _, line := range lines {
+		total += line.Tokens
+		maxTokens = max(maxTokens, line.Tokens)
+	}
+	width := len(fmt.Sprint(maxTokens))
+
+	for _, line := range lines {
+		gutter := fmt.Sprintf("%*d", width, line.Tokens)
+		if !f.useColor {
+			fmt.Printf("%s | %s\n", gutter, line.Content)
+			continue
+		}
+
+		switch {
+		case line.Tokens == 0:
+			color.New(color.Faint).Print(gutter)
+		case line.Tokens*3 > maxTokens*2:
+			color.New(color.FgRed).Print(gutter)
+		case line.Tokens*3 > maxTokens:
+			color.New(color.FgYellow).Print(gutter)
+		default:
+			color.New(color.FgGreen).Print(gutter)
+		}
+		color.New(color.Faint).Print(" | ")
+		fmt.Println(line.Content)
+	}
+
+	fmt.Printf("\n%s: %d tokens across %d lines (client-side tokenizer)\n", path, total, len(lines))
+	return nil
+}
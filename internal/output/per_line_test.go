@@ -0,0 +1,45 @@
+package output
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/iota-uz/cc-token/internal/analyzer"
+)
+
+func TestPerLineColorsGutterByCost(t *testing.T) {
+	savedNoColor, savedOutput := color.NoColor, color.Output
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor, color.Output = savedNoColor, savedOutput })
+
+	lines := []*analyzer.LineInsight{
+		{Content: "expensive", Tokens: 30},
+		{Content: "middling", Tokens: 15},
+		{Content: "cheap", Tokens: 2},
+		{Content: "", Tokens: 0},
+	}
+	stdout, _ := captureOutput(t, func() {
+		// color writes to its own handle on stdout, opened before the capture
+		color.Output = os.Stdout
+		if err := NewPerLineFormatter(true).Format("prompt.txt", lines); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	got := strings.Split(stdout, "\n")
+	wants := []string{
+		"\x1b[31m30\x1b[0m",
+		"\x1b[33m15\x1b[0m",
+		"\x1b[32m 2\x1b[0m",
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(got[i], want) {
+			t.Errorf("line %d = %q, want gutter %q", i+1, got[i], want)
+		}
+	}
+	if !strings.Contains(stdout, "prompt.txt: 47 tokens across 3 lines") {
+		t.Errorf("output:\n%s\nwant the total over 3 lines", stdout)
+	}
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// QuietFormatter prints only the total token count as a bare integer, for scripts
+type QuietFormatter struct{}
+
+// NewQuietFormatter creates a new quiet formatter
+func NewQuietFormatter() *QuietFormatter {
+	return &QuietFormatter{}
+}
+
+// Format prints the total tokens of all successfully counted files to stdout. Failed files are
+// reported on stderr, with the --offline note, so stdout stays parseable.
+func (f *QuietFormatter) Format(results []*processor.Result, cfg *config.Config) error {
+	var walk func(result *processor.Result)
+	walk = func(result *processor.Result) {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s: ERROR - %v\n", result.Path, result.Error)
+		}
+		for _, child := range result.Children {
+			walk(child)
+		}
+	}
+	for _, result := range results {
+		walk(result)
+	}
+
+	fmt.Println(totalTokens(results))
+	if cfg.Offline {
+		fmt.Fprintln(os.Stderr, offlineNote)
+	}
+	return nil
+}
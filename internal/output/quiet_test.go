@@ -0,0 +1,104 @@
+package output
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// captureOutput runs fn with os.Stdout and os.Stderr redirected, returning what it wrote to each
+func captureOutput(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	capture := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := *target
+		*target = w
+		done := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			done <- string(data)
+		}()
+		return func() string {
+			w.Close()
+			*target = original
+			return <-done
+		}
+	}
+	restoreStdout := capture(&os.Stdout)
+	restoreStderr := capture(&os.Stderr)
+	fn()
+	return restoreStdout(), restoreStderr()
+}
+
+func TestQuietOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []*processor.Result
+		offline    bool
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "single file",
+			results:    []*processor.Result{{Path: "a.txt", Tokens: 42}},
+			wantStdout: "42\n",
+		},
+		{
+			name: "directory with a failed file",
+			results: []*processor.Result{{
+				Path:  "dir",
+				IsDir: true,
+				Children: []*processor.Result{
+					{Path: "dir/a.txt", Tokens: 10},
+					{Path: "dir/b.txt", Error: errors.New("boom")},
+					{Path: "dir/sub", IsDir: true, Children: []*processor.Result{{Path: "dir/sub/c.txt", Tokens: 5}}},
+				},
+			}},
+			wantStdout: "15\n",
+			wantStderr: "dir/b.txt: ERROR - boom",
+		},
+		{
+			name:       "several paths",
+			results:    []*processor.Result{{Path: "a.txt", Tokens: 1}, {Path: "b.txt", Tokens: 2}},
+			wantStdout: "3\n",
+		},
+		{
+			name:       "offline note on stderr",
+			results:    []*processor.Result{{Path: "a.txt", Tokens: 7}},
+			offline:    true,
+			wantStdout: "7\n",
+			wantStderr: offlineNote,
+		},
+	}
+	digits := regexp.MustCompile(`^[0-9]+\n$`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Quiet: true, Offline: tt.offline}
+			var err error
+			stdout, stderr := captureOutput(t, func() {
+				err = OutputResults(tt.results, cfg, nil)
+			})
+			if err != nil {
+				t.Fatalf("OutputResults: %v", err)
+			}
+			if !digits.MatchString(stdout) || stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want only %q", stdout, tt.wantStdout)
+			}
+			if tt.wantStderr == "" && stderr != "" {
+				t.Errorf("stderr = %q, want nothing", stderr)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
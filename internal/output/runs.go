@@ -0,0 +1,92 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// RunStats summarizes repeated token counts of the same content
+type RunStats struct {
+	Runs        int           `json:"runs"`
+	MinTokens   int           `json:"min_tokens"`
+	MaxTokens   int           `json:"max_tokens"`
+	MeanTokens  float64       `json:"mean_tokens"`
+	MinLatency  time.Duration `json:"-"`
+	MaxLatency  time.Duration `json:"-"`
+	MeanLatency time.Duration `json:"-"`
+}
+
+// ComputeRunStats computes token and latency statistics from per-run measurements
+func ComputeRunStats(tokens []int, latencies []time.Duration) *RunStats {
+	stats := &RunStats{Runs: len(tokens)}
+	if len(tokens) == 0 {
+		return stats
+	}
+
+	stats.MinTokens, stats.MaxTokens = tokens[0], tokens[0]
+	totalTokens := 0
+	for _, t := range tokens {
+		totalTokens += t
+		if t < stats.MinTokens {
+			stats.MinTokens = t
+		}
+		if t > stats.MaxTokens {
+			stats.MaxTokens = t
+		}
+	}
+	stats.MeanTokens = float64(totalTokens) / float64(len(tokens))
+
+	if len(latencies) > 0 {
+		stats.MinLatency, stats.MaxLatency = latencies[0], latencies[0]
+		var totalLatency time.Duration
+		for _, l := range latencies {
+			totalLatency += l
+			if l < stats.MinLatency {
+				stats.MinLatency = l
+			}
+			if l > stats.MaxLatency {
+				stats.MaxLatency = l
+			}
+		}
+		stats.MeanLatency = totalLatency / time.Duration(len(latencies))
+	}
+
+	return stats
+}
+
+// FormatRunStats outputs repeated-run statistics in text or JSON format
+func FormatRunStats(path string, stats *RunStats, cfg *config.Config) error {
+	if cfg.Format == config.FormatJSON {
+		item := map[string]interface{}{
+			"path":            path,
+			"runs":            stats.Runs,
+			"min_tokens":      stats.MinTokens,
+			"max_tokens":      stats.MaxTokens,
+			"mean_tokens":     stats.MeanTokens,
+			"min_latency_ms":  durationMillis(stats.MinLatency),
+			"max_latency_ms":  durationMillis(stats.MaxLatency),
+			"mean_latency_ms": durationMillis(stats.MeanLatency),
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(item)
+	}
+
+	fmt.Printf("%s: %d runs\n", path, stats.Runs)
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Tokens:  min %d | max %d | mean %.1f\n", stats.MinTokens, stats.MaxTokens, stats.MeanTokens)
+	fmt.Printf("Latency: min %s | max %s | mean %s\n",
+		stats.MinLatency.Round(time.Millisecond), stats.MaxLatency.Round(time.Millisecond), stats.MeanLatency.Round(time.Millisecond))
+	return nil
+}
+
+// durationMillis converts a duration to fractional milliseconds for JSON output
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,26 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeRunStats(t *testing.T) {
+	stats := ComputeRunStats([]int{10, 14, 12}, []time.Duration{30 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond})
+	want := RunStats{
+		Runs:        3,
+		MinTokens:   10,
+		MaxTokens:   14,
+		MeanTokens:  12,
+		MinLatency:  10 * time.Millisecond,
+		MaxLatency:  30 * time.Millisecond,
+		MeanLatency: 20 * time.Millisecond,
+	}
+	if *stats != want {
+		t.Errorf("ComputeRunStats = %+v, want %+v", *stats, want)
+	}
+
+	if empty := ComputeRunStats(nil, nil); *empty != (RunStats{}) {
+		t.Errorf("ComputeRunStats(nil) = %+v, want zero stats", *empty)
+	}
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/iota-uz/cc-token"
+)
+
+// sarifRule describes one kind of LLM safety finding
+type sarifRule struct {
+	ID          string
+	Description string
+	Level       string // Default SARIF level: "error", "warning" or "note"
+}
+
+// SARIF rules for each LLM safety issue type
+var (
+	ruleEmoji            = sarifRule{"cc-token/emoji", "Emoji tokenize inconsistently and waste tokens", "note"}
+	ruleInvisibleChar    = sarifRule{"cc-token/invisible-char", "Invisible or zero-width characters", "warning"}
+	ruleNumberFormatting = sarifRule{"cc-token/number-formatting", "Numbers that tokenize poorly", "note"}
+	ruleOOVString        = sarifRule{"cc-token/oov-string", "Out-of-vocabulary strings such as hashes, UUIDs and IDs", "note"}
+	ruleBiDiControl      = sarifRule{"cc-token/bidi-control", "Bidirectional control characters (Trojan Source)", "error"}
+	ruleConfusable       = sarifRule{"cc-token/confusable", "Confusable characters (homoglyphs)", "warning"}
+	ruleEncoding         = sarifRule{"cc-token/encoded-text", "Encoded or obfuscated text", "warning"}
+	ruleNormalization    = sarifRule{"cc-token/unicode-normalization", "Text that is not Unicode-normalized", "note"}
+	ruleGlitchToken      = sarifRule{"cc-token/glitch-token", "Known glitch tokens", "warning"}
+	ruleContextPlacement = sarifRule{"cc-token/context-placement", "Important content lost in the middle of a long context", "note"}
+	rulePromptAmbiguity  = sarifRule{"cc-token/prompt-ambiguity", "Ambiguous or conflicting prompt instructions", "warning"}
+	ruleInjectionPhrase  = sarifRule{"cc-token/injection-phrase", "Known prompt-injection phrases", "error"}
+	ruleSecret           = sarifRule{"cc-token/secret", "API keys, tokens and other credentials", "error"}
+)
+
+// SARIF 2.1.0 document, limited to the properties cc-token fills in
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string                `json:"name"`
+		InformationURI string                `json:"informationUri"`
+		Rules          []sarifRuleDescriptor `json:"rules"`
+	}
+	sarifRuleDescriptor struct {
+		ID                   string             `json:"id"`
+		ShortDescription     sarifMessage       `json:"shortDescription"`
+		DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	}
+	sarifConfiguration struct {
+		Level string `json:"level"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+// SARIFFormatter formats LLM safety findings as SARIF for code scanning (e.g. GitHub)
+type SARIFFormatter struct{}
+
+// NewSARIFFormatter creates a new SARIF formatter
+func NewSARIFFormatter() *SARIFFormatter {
+	return &SARIFFormatter{}
+}
+
+// FormatAnalysis outputs the LLM safety issues of a single file as a SARIF log
+func (f *SARIFFormatter) FormatAnalysis(analysis *analyzer.Analysis, filename string, cfg *config.Config) error {
+	return writeSARIF(sarifResults(analysis.LLMSafetyAnalysis, filename))
+}
+
+// FormatDirectoryAnalysis outputs the LLM safety issues of every analyzed file as one SARIF run
+func (f *SARIFFormatter) FormatDirectoryAnalysis(dir *analyzer.DirectoryAnalysis, path string, cfg *config.Config) error {
+	var results []sarifResult
+	for _, file := range dir.Files {
+		results = append(results, sarifResults(file.Analysis.LLMSafetyAnalysis, file.Path)...)
+	}
+	return writeSARIF(results)
+}
+
+// writeSARIF wraps results in a single-run SARIF log, describing each rule that was reported
+func writeSARIF(results []sarifResult) error {
+	if results == nil {
+		results = []sarifResult{}
+	}
+
+	reported := make(map[string]bool)
+	for _, result := range results {
+		reported[result.RuleID] = true
+	}
+	rules := make([]sarifRuleDescriptor, 0, len(reported))
+	for _, rule := range []sarifRule{
+		ruleEmoji, ruleInvisibleChar, ruleNumberFormatting, ruleOOVString, ruleBiDiControl, ruleConfusable,
+		ruleEncoding, ruleNormalization, ruleGlitchToken, ruleContextPlacement, rulePromptAmbiguity, ruleInjectionPhrase,
+		ruleSecret,
+	} {
+		if reported[rule.ID] {
+			rules = append(rules, sarifRuleDescriptor{
+				ID:                   rule.ID,
+				ShortDescription:     sarifMessage{Text: rule.Description},
+				DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "cc-token", InformationURI: sarifToolURI, Rules: rules}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifResults converts the LLM safety issues of one file into SARIF results, ordered by line
+func sarifResults(safety *analyzer.LLMSafetyAnalysis, path string) []sarifResult {
+	if safety == nil {
+		return nil
+	}
+
+	uri := filepath.ToSlash(path)
+	var results []sarifResult
+	add := func(rule sarifRule, level string, line int, format string, args ...interface{}) {
+		if level == "" {
+			level = rule.Level
+		}
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}
+		if line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    rule.ID,
+			Level:     level,
+			Message:   sarifMessage{Text: fmt.Sprintf(format, args...)},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	for _, issue := range safety.EmojiIssues {
+		add(ruleEmoji, "", issue.LineNumber, "Emoji %s (%s) costs about %d tokens", issue.Emoji, issue.EmojiType, issue.TokenCost)
+	}
+	for _, issue := range safety.InvisibleCharIssues {
+		level := ""
+		if issue.IsEvasion {
+			level = "error"
+		}
+		add(ruleInvisibleChar, level, issue.LineNumber, "Invisible character %s (%d occurrence(s))", issue.CharType, issue.Count)
+	}
+	for _, issue := range safety.NumberFormatIssues {
+		add(ruleNumberFormatting, "", issue.LineNumber, "Number %s tokenizes poorly; consider %s", issue.Number, issue.Suggestion)
+	}
+	for _, issue := range safety.OOVStringIssues {
+		add(ruleOOVString, "", issue.LineNumber, "Out-of-vocabulary %s string costs %d tokens", issue.StringType, issue.TokenCount)
+	}
+	for _, issue := range safety.BiDiControlIssues {
+		add(ruleBiDiControl, "", issue.LineNumber, "Bidirectional control character %s (%d occurrence(s))", issue.ControlType, issue.Count)
+	}
+	for _, issue := range safety.ConfusableIssues {
+		add(ruleConfusable, "", issue.LineNumber, "Confusable character: %s", issue.CharName)
+	}
+	for _, issue := range safety.EncodingIssues {
+		if issue.DecodedText != "" && (issue.EncodingType == "base64" || issue.EncodingType == "hex") {
+			add(ruleEncoding, "", issue.LineNumber, "%s-encoded text decodes to %q", issue.EncodingType, issue.DecodedText)
+			continue
+		}
+		add(ruleEncoding, "", issue.LineNumber, "Possible %s-encoded text (%d characters)", issue.EncodingType, issue.Length)
+	}
+	for _, issue := range safety.NormalizationIssues {
+		add(ruleNormalization, "", issue.LineNumber, "Text is not %s-normalized (%s)", issue.FormExpected, issue.IssueType)
+	}
+	for _, issue := range safety.GlitchTokenIssues {
+		add(ruleGlitchToken, severityLevel(issue.Severity), issue.LineNumber, "Glitch token %q: %s", issue.Token, issue.KnownIssue)
+	}
+	for _, issue := range safety.ContextIssues {
+		add(ruleContextPlacement, "", 0, "%s", issue.RecommendedChanges)
+	}
+	for _, issue := range safety.AmbiguityIssues {
+		add(rulePromptAmbiguity, severityLevel(issue.Severity), issue.LineNumber, "%s", issue.Description)
+	}
+	for _, issue := range safety.InjectionIssues {
+		add(ruleInjectionPhrase, "", issue.LineNumber, "Prompt-injection phrase %q", issue.Phrase)
+	}
+	for _, issue := range safety.SecretIssues {
+		add(ruleSecret, "", issue.LineNumber, "Possible %s %s", issue.Kind, issue.Masked)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return resultLine(results[i]) < resultLine(results[j])
+	})
+	return results
+}
+
+// severityLevel maps a detector severity to a SARIF level
+func severityLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "high":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// resultLine returns the start line of a result, or 0 for file-level results
+func resultLine(result sarifResult) int {
+	if region := result.Locations[0].PhysicalLocation.Region; region != nil {
+		return region.StartLine
+	}
+	return 0
+}
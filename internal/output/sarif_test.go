@@ -0,0 +1,133 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// sarifDocument is the part of a SARIF log cc-token is expected to fill in
+type sarifDocument struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name  string `json:"name"`
+				Rules []struct {
+					ID                   string `json:"id"`
+					DefaultConfiguration struct {
+						Level string `json:"level"`
+					} `json:"defaultConfiguration"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region *struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func formatSARIF(t *testing.T, safety *analyzer.LLMSafetyAnalysis) sarifDocument {
+	t.Helper()
+	stdout, _ := captureOutput(t, func() {
+		if err := NewSARIFFormatter().FormatAnalysis(&analyzer.Analysis{LLMSafetyAnalysis: safety}, "prompts/system.txt", &config.Config{}); err != nil {
+			t.Errorf("FormatAnalysis: %v", err)
+		}
+	})
+	var doc sarifDocument
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, stdout)
+	}
+	if doc.Version != "2.1.0" || doc.Schema == "" {
+		t.Errorf("version = %q, $schema = %q, want SARIF 2.1.0 with a schema", doc.Version, doc.Schema)
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(doc.Runs))
+	}
+	if name := doc.Runs[0].Tool.Driver.Name; name != "cc-token" {
+		t.Errorf("tool.driver.name = %q, want cc-token", name)
+	}
+	return doc
+}
+
+func TestSARIFResults(t *testing.T) {
+	doc := formatSARIF(t, &analyzer.LLMSafetyAnalysis{
+		BiDiControlIssues:   []*analyzer.BiDiControlIssue{{ControlType: "RLO", LineNumber: 3, Count: 1}},
+		InvisibleCharIssues: []*analyzer.InvisibleCharIssue{{CharType: "zwsp", LineNumber: 1, Count: 2, IsEvasion: true}},
+		SecretIssues:        []*analyzer.SecretIssue{{Kind: "aws_access_key", LineNumber: 2, Masked: "AKIA****"}},
+	})
+	run := doc.Runs[0]
+
+	rules := make(map[string]string)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = rule.DefaultConfiguration.Level
+	}
+	want := []struct {
+		ruleID string
+		level  string
+		line   int
+	}{
+		{"cc-token/invisible-char", "error", 1},
+		{"cc-token/secret", "error", 2},
+		{"cc-token/bidi-control", "error", 3},
+	}
+	if len(run.Results) != len(want) || len(rules) != len(want) {
+		t.Fatalf("got %d results and %d rules, want %d of each", len(run.Results), len(rules), len(want))
+	}
+	for i, w := range want {
+		result := run.Results[i]
+		if result.RuleID != w.ruleID || result.Level != w.level {
+			t.Errorf("result %d = %s (%s), want %s (%s)", i, result.RuleID, result.Level, w.ruleID, w.level)
+		}
+		if _, ok := rules[result.RuleID]; !ok {
+			t.Errorf("result %d: rule %s is not described in tool.driver.rules", i, result.RuleID)
+		}
+		if result.Message.Text == "" || len(result.Locations) != 1 {
+			t.Fatalf("result %d has message %q and %d locations, want a message and one location", i, result.Message.Text, len(result.Locations))
+		}
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != "prompts/system.txt" || location.Region == nil || location.Region.StartLine != w.line {
+			t.Errorf("result %d location = %+v, want prompts/system.txt line %d", i, location, w.line)
+		}
+	}
+	// An invisible character used for evasion is raised above the rule's default level
+	if rules["cc-token/invisible-char"] != "warning" {
+		t.Errorf("invisible-char default level = %q, want warning", rules["cc-token/invisible-char"])
+	}
+}
+
+func TestSARIFWithoutIssues(t *testing.T) {
+	stdout, _ := captureOutput(t, func() {
+		if err := NewSARIFFormatter().FormatAnalysis(&analyzer.Analysis{LLMSafetyAnalysis: &analyzer.LLMSafetyAnalysis{}}, "clean.txt", &config.Config{}); err != nil {
+			t.Errorf("FormatAnalysis: %v", err)
+		}
+	})
+	var doc struct {
+		Runs []struct {
+			Results json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Runs) != 1 || string(doc.Runs[0].Results) != "[]" {
+		t.Errorf("runs = %s, want one run with an empty results array", stdout)
+	}
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+func TestFileStats(t *testing.T) {
+	dir := &processor.Result{Path: "dir", IsDir: true}
+	// Shuffled token counts 10, 20, ..., 110
+	for _, tokens := range []int{70, 10, 110, 40, 90, 20, 60, 100, 30, 80, 50} {
+		dir.Children = append(dir.Children, &processor.Result{Path: fmt.Sprintf("dir/%d.txt", tokens), Tokens: tokens})
+	}
+	// Failed and binary files aren't part of the distribution
+	dir.Children = append(dir.Children,
+		&processor.Result{Path: "dir/broken.txt", Tokens: 1000, Error: errors.New("boom")},
+		&processor.Result{Path: "dir/image.bin", SkippedBinary: true})
+	results := []*processor.Result{dir}
+
+	want := FileStats{Files: 11, Min: 10, Median: 60, P90: 100, P95: 105, Max: 110, Mean: 60}
+	if got := computeFileStats(results); *got != want {
+		t.Errorf("computeFileStats = %+v, want %+v", *got, want)
+	}
+
+	data, _ := json.Marshal(computeFileStats(results))
+	if !strings.Contains(string(data), `"p90":100,"p95":105`) {
+		t.Errorf("JSON stats = %s, want p90 and p95 fields", data)
+	}
+
+	stdout, _ := captureOutput(t, func() { printFileStats(results) })
+	wantLine := "Min: 10 | Median: 60 | P90: 100 | P95: 105 | Max: 110 | Mean: 60.0"
+	if !strings.Contains(stdout, "Tokens per file (11 files):") || !strings.Contains(stdout, wantLine) {
+		t.Errorf("printFileStats output:\n%s\nwant a line with %q", stdout, wantLine)
+	}
+}
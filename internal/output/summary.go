@@ -0,0 +1,208 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+const (
+	// noExtensionKey groups files that have no extension
+	noExtensionKey = "(none)"
+	// offlineNote labels output whose counts come from the client-side tokenizer (--offline)
+	offlineNote = "Note: counts are approximate (--offline uses the client-side tokenizer)"
+)
+
+// ExtensionTotals holds aggregated token counts for a single file extension
+type ExtensionTotals struct {
+	Tokens int     `json:"tokens"`
+	Files  int     `json:"files"`
+	Cost   float64 `json:"cost"`
+}
+
+// extensionEntry pairs an extension with its totals for ordered output
+type extensionEntry struct {
+	Ext    string
+	Totals *ExtensionTotals
+}
+
+// collectFiles flattens result trees into the list of successfully processed files
+func collectFiles(results []*processor.Result) []*processor.Result {
+	var files []*processor.Result
+	for _, result := range results {
+		if result.IsDir {
+			files = append(files, collectFiles(result.Children)...)
+			continue
+		}
+		if result.Error == nil && !result.SkippedBinary {
+			files = append(files, result)
+		}
+	}
+	return files
+}
+
+// totalTokens sums the token counts of all successfully processed files
+func totalTokens(results []*processor.Result) int {
+	total := 0
+	for _, file := range collectFiles(results) {
+		total += file.Tokens
+	}
+	return total
+}
+
+// groupByExtension accumulates token and file totals per file extension across all results.
+// Costs are left at zero; formatters fill them in using their pricing service.
+func groupByExtension(results []*processor.Result) map[string]*ExtensionTotals {
+	groups := make(map[string]*ExtensionTotals)
+	for _, file := range collectFiles(results) {
+		ext := strings.ToLower(filepath.Ext(file.Path))
+		if ext == "" {
+			ext = noExtensionKey
+		}
+		totals, ok := groups[ext]
+		if !ok {
+			totals = &ExtensionTotals{}
+			groups[ext] = totals
+		}
+		totals.Tokens += file.Tokens
+		totals.Files++
+	}
+	return groups
+}
+
+// sortedExtensions returns extension groups ordered by token count (descending), then by name
+func sortedExtensions(groups map[string]*ExtensionTotals) []extensionEntry {
+	entries := make([]extensionEntry, 0, len(groups))
+	for ext, totals := range groups {
+		entries = append(entries, extensionEntry{Ext: ext, Totals: totals})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Totals.Tokens != entries[j].Totals.Tokens {
+			return entries[i].Totals.Tokens > entries[j].Totals.Tokens
+		}
+		return entries[i].Ext < entries[j].Ext
+	})
+	return entries
+}
+
+// FileStats summarizes the distribution of tokens per file
+type FileStats struct {
+	Files  int     `json:"files"`
+	Min    int     `json:"min"`
+	Median int     `json:"median"`
+	P90    int     `json:"p90"`
+	P95    int     `json:"p95"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+}
+
+// computeFileStats computes per-file token percentiles across all successfully processed files
+func computeFileStats(results []*processor.Result) *FileStats {
+	files := collectFiles(results)
+	tokenCounts := make([]int, len(files))
+	for i, file := range files {
+		tokenCounts[i] = file.Tokens
+	}
+
+	percentiles := analyzer.PercentilesOf(tokenCounts)
+	return &FileStats{
+		Files:  len(files),
+		Min:    percentiles.Min,
+		Median: percentiles.Median,
+		P90:    percentiles.Percentile90,
+		P95:    percentiles.Percentile95,
+		Max:    percentiles.Max,
+		Mean:   percentiles.Mean,
+	}
+}
+
+// PromptCachingCost compares the cost of sending the counted content with prompt caching
+// (written Writes times, then read Reads times) against sending it uncached every time
+type PromptCachingCost struct {
+	Writes       int     `json:"writes"`
+	Reads        int     `json:"reads"`
+	WriteCost    float64 `json:"write_cost"`
+	ReadCost     float64 `json:"read_cost"`
+	Total        float64 `json:"total_cost"`
+	UncachedCost float64 `json:"uncached_cost"`
+}
+
+// computePromptCachingCost prices --cache-write/--cache-read for the given token total.
+// It returns nil when neither flag is set.
+func computePromptCachingCost(tokens int, cfg *config.Config, pricingService *pricing.Pricer) *PromptCachingCost {
+	if cfg.CacheWrites == 0 && cfg.CacheReads == 0 {
+		return nil
+	}
+
+	costs := &PromptCachingCost{
+		Writes:       cfg.CacheWrites,
+		Reads:        cfg.CacheReads,
+		WriteCost:    pricingService.CalculateCostWithCaching(0, tokens*cfg.CacheWrites, 0, cfg.Model),
+		ReadCost:     pricingService.CalculateCostWithCaching(0, 0, tokens*cfg.CacheReads, cfg.Model),
+		UncachedCost: pricingService.CalculateCost(tokens*(cfg.CacheWrites+cfg.CacheReads), cfg.Model),
+	}
+	costs.Total = costs.WriteCost + costs.ReadCost
+	return costs
+}
+
+// SystemPromptTotals splits token totals into the --system prompt overhead and user content
+type SystemPromptTotals struct {
+	PerRequest  int `json:"per_request"`
+	Requests    int `json:"requests"`
+	System      int `json:"system_tokens"`
+	UserContent int `json:"user_tokens"`
+}
+
+// computeSystemPromptTotals sums the system prompt overhead across all counted files.
+// It returns nil when no system prompt was used.
+func computeSystemPromptTotals(results []*processor.Result) *SystemPromptTotals {
+	files := collectFiles(results)
+	if len(files) == 0 || files[0].SystemTokens == 0 {
+		return nil
+	}
+
+	totals := &SystemPromptTotals{PerRequest: files[0].SystemTokens, Requests: len(files)}
+	for _, file := range files {
+		totals.System += file.SystemTokens
+		totals.UserContent += file.Tokens - file.SystemTokens
+	}
+	return totals
+}
+
+// systemPromptSuffix formats the system vs user split for a single file line
+func systemPromptSuffix(result *processor.Result) string {
+	if result.SystemTokens == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [system: %d, user: %d]", result.SystemTokens, result.Tokens-result.SystemTokens)
+}
+
+// truncatedSuffix marks files that were cut to their first --max-tokens tokens
+func truncatedSuffix(result *processor.Result) string {
+	if !result.Truncated {
+		return ""
+	}
+	return " (truncated)"
+}
+
+// chunkedSuffix marks files over --max-size that were counted in pieces, whose totals are approximate
+func chunkedSuffix(result *processor.Result) string {
+	if result.Chunks == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d chunks, approximate)", result.Chunks)
+}
+
+// imageSuffix marks files counted as images, whose tokens come from their pixel size
+func imageSuffix(result *processor.Result) string {
+	if !result.Image {
+		return ""
+	}
+	return " (image)"
+}
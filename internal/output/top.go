@@ -0,0 +1,114 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// TopFormatter prints only the most expensive files (--top) with totals across every file
+type TopFormatter struct {
+	pricingService *pricing.Pricer
+}
+
+// NewTopFormatter creates a new top-N formatter
+func NewTopFormatter(pricingService *pricing.Pricer) *TopFormatter {
+	return &TopFormatter{pricingService: pricingService}
+}
+
+// topFiles returns the n files with the most tokens, ties in path order, and the files left out
+func topFiles(results []*processor.Result, n int) (top, omitted []*processor.Result) {
+	files := collectFiles(results)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Tokens > files[j].Tokens
+	})
+	if n > len(files) {
+		n = len(files)
+	}
+	return files[:n], files[n:]
+}
+
+// Format prints the --top files in tree or JSON format. Totals cover all files, and failed
+// files are reported on stderr.
+func (f *TopFormatter) Format(results []*processor.Result, cfg *config.Config) error {
+	var walk func(result *processor.Result)
+	walk = func(result *processor.Result) {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s: ERROR - %v\n", result.Path, result.Error)
+		}
+		for _, child := range result.Children {
+			walk(child)
+		}
+	}
+	for _, result := range results {
+		walk(result)
+	}
+
+	top, omitted := topFiles(results, cfg.Top)
+	total := totalTokens(results)
+	omittedTokens := total
+	for _, file := range top {
+		omittedTokens -= file.Tokens
+	}
+
+	if cfg.Format == config.FormatJSON {
+		return f.formatJSON(top, len(omitted), omittedTokens, total, cfg)
+	}
+
+	fmt.Printf("Top %d of %d files by tokens:\n", len(top), len(top)+len(omitted))
+	for _, file := range top {
+		line := fmt.Sprintf("  %s: %d tokens", file.Path, file.Tokens)
+		if cfg.ShowCost {
+			cost := f.pricingService.CalculateCost(file.Tokens, cfg.Model)
+			line += fmt.Sprintf(" (%s)", f.pricingService.FormatCost(cost, cfg.CostPrecision))
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	omittedMark := ""
+	if len(omitted) > 0 {
+		omittedMark = fmt.Sprintf(" (%d not shown: %d tokens)", len(omitted), omittedTokens)
+	}
+	fmt.Printf("Total: %d tokens across %d files%s\n", total, len(top)+len(omitted), omittedMark)
+	if cfg.ShowCost {
+		cost := f.pricingService.CalculateCost(total, cfg.Model)
+		fmt.Printf("Estimated cost: %s\n", f.pricingService.FormatCost(cost, cfg.CostPrecision))
+	}
+	if cfg.Offline {
+		fmt.Println(offlineNote)
+	}
+	return nil
+}
+
+// formatJSON prints the top files as JSON items with a summary of the omitted files
+func (f *TopFormatter) formatJSON(top []*processor.Result, omittedFiles, omittedTokens, total int, cfg *config.Config) error {
+	items := NewJSONFormatter(f.pricingService)
+	files := make([]map[string]interface{}, 0, len(top))
+	for _, file := range top {
+		files = append(files, items.resultItem(file, cfg))
+	}
+
+	output := map[string]interface{}{
+		"top":          files,
+		"omitted":      map[string]int{"files": omittedFiles, "tokens": omittedTokens},
+		"files":        len(top) + omittedFiles,
+		"total_tokens": total,
+	}
+	if cfg.ShowCost {
+		output["estimated_cost"] = f.pricingService.CalculateCost(total, cfg.Model)
+	}
+	if cfg.Offline {
+		output["approximate"] = true
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
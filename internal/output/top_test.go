@@ -0,0 +1,76 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// topResults returns a file and a directory holding 10, 20, ..., 60 tokens across six files
+func topResults() []*processor.Result {
+	dir := &processor.Result{Path: "dir", IsDir: true}
+	for _, tokens := range []int{30, 10, 60, 20, 50} {
+		dir.Children = append(dir.Children, &processor.Result{Path: fmt.Sprintf("dir/%d.txt", tokens), Tokens: tokens})
+		dir.Tokens += tokens
+	}
+	return []*processor.Result{{Path: "40.txt", Tokens: 40}, dir}
+}
+
+func TestTopPrintsNFilesWithFullTotal(t *testing.T) {
+	cfg := &config.Config{Model: pricing.DefaultModel, Top: 3}
+	stdout, _ := captureOutput(t, func() {
+		if err := NewTopFormatter(pricing.New("")).Format(topResults(), cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var printed []string
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.HasPrefix(line, "  ") {
+			printed = append(printed, strings.TrimSpace(line))
+		}
+	}
+	want := []string{"dir/60.txt: 60 tokens", "dir/50.txt: 50 tokens", "40.txt: 40 tokens"}
+	if strings.Join(printed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("printed files:\n%s\nwant:\n%s", strings.Join(printed, "\n"), strings.Join(want, "\n"))
+	}
+	if wantTotal := "Total: 210 tokens across 6 files (3 not shown: 60 tokens)"; !strings.Contains(stdout, wantTotal) {
+		t.Errorf("output:\n%s\nwant %q", stdout, wantTotal)
+	}
+}
+
+func TestTopJSONSummarizesOmittedFiles(t *testing.T) {
+	cfg := &config.Config{Model: pricing.DefaultModel, Top: 2, Format: config.FormatJSON}
+	stdout, _ := captureOutput(t, func() {
+		if err := NewTopFormatter(pricing.New("")).Format(topResults(), cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var output struct {
+		Top []struct {
+			Path   string `json:"path"`
+			Tokens int    `json:"tokens"`
+		} `json:"top"`
+		Omitted     map[string]int `json:"omitted"`
+		Files       int            `json:"files"`
+		TotalTokens int            `json:"total_tokens"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(output.Top) != 2 || output.Top[0].Path != "dir/60.txt" || output.Top[1].Path != "dir/50.txt" {
+		t.Errorf("top = %+v, want dir/60.txt and dir/50.txt", output.Top)
+	}
+	if output.TotalTokens != 210 || output.Files != 6 {
+		t.Errorf("total_tokens = %d, files = %d, want 210 and 6", output.TotalTokens, output.Files)
+	}
+	if output.Omitted["files"] != 4 || output.Omitted["tokens"] != 100 {
+		t.Errorf("omitted = %v, want 4 files with 100 tokens", output.Omitted)
+	}
+}
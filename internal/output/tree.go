@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/iota-uz/cc-token/internal/config"
 	"github.com/iota-uz/cc-token/internal/pricing"
@@ -25,15 +27,25 @@ func NewTreeFormatter(pricingService *pricing.Pricer) *TreeFormatter {
 func (f *TreeFormatter) Format(results []*processor.Result, cfg *config.Config) error {
 	totalTokens := 0
 	totalFiles := 0
+	skippedBinary := 0
+
+	// Consecutive file results share a cost column, so they are printed together
+	var fileRows []treeRow
 
 	for _, result := range results {
 		if result.IsDir {
-			printTreeNode(result, "", cfg.Verbose)
+			f.printRows(fileRows, cfg)
+			fileRows = nil
+			f.printTreeNode(result, "", cfg)
 			totalTokens += result.Tokens
 			totalFiles += result.CountFiles()
+			skippedBinary += result.CountSkippedBinary()
 		} else {
 			if result.Error != nil {
 				fmt.Fprintf(os.Stderr, "%s: ERROR - %v\n", result.Path, result.Error)
+			} else if result.SkippedBinary {
+				fmt.Fprintf(os.Stderr, "%s: skipped (binary file, use --include-binary to count)\n", result.Path)
+				skippedBinary++
 			} else {
 				cachedMark := ""
 				if cfg.Verbose && result.Cached {
@@ -43,41 +55,114 @@ func (f *TreeFormatter) Format(results []*processor.Result, cfg *config.Config)
 				if result.LineCount > 0 {
 					tokensPerLine = fmt.Sprintf(" (%.1f tokens/line)", result.AvgTokensPerLine)
 				}
-				fmt.Printf("%s: %d tokens%s%s\n", result.Path, result.Tokens, tokensPerLine, cachedMark)
+				fileRows = append(fileRows, treeRow{
+					text:   fmt.Sprintf("%s: %d tokens%s%s%s%s%s%s", result.Path, result.Tokens, imageSuffix(result), truncatedSuffix(result), chunkedSuffix(result), systemPromptSuffix(result), tokensPerLine, cachedMark),
+					tokens: result.Tokens,
+				})
 				totalTokens += result.Tokens
 				totalFiles++
 			}
 		}
 	}
+	f.printRows(fileRows, cfg)
 
 	// Print summary
 	if len(results) > 1 || (len(results) == 1 && results[0].IsDir) {
 		fmt.Println(strings.Repeat("-", 50))
-		fmt.Printf("Total: %d tokens across %d files\n", totalTokens, totalFiles)
+		skippedMark := ""
+		if skippedBinary > 0 {
+			skippedMark = fmt.Sprintf(" (%d skipped as binary)", skippedBinary)
+		}
+		fmt.Printf("Total: %d tokens across %d files%s\n", totalTokens, totalFiles, skippedMark)
 
 		if cfg.ShowCost {
 			cost := f.pricingService.CalculateCost(totalTokens, cfg.Model)
-			fmt.Printf("Estimated cost: $%.6f\n", cost)
+			fmt.Printf("Estimated cost: %s\n", f.pricingService.FormatCost(cost, cfg.CostPrecision))
 		}
 	} else if cfg.ShowCost && totalTokens > 0 {
 		cost := f.pricingService.CalculateCost(totalTokens, cfg.Model)
-		fmt.Printf("Estimated cost: $%.6f\n", cost)
+		fmt.Printf("Estimated cost: %s\n", f.pricingService.FormatCost(cost, cfg.CostPrecision))
+	}
+
+	if caching := computePromptCachingCost(totalTokens, cfg, f.pricingService); caching != nil && cfg.ShowCost {
+		fmt.Printf("Prompt caching: %d write(s) %s + %d read(s) %s = %s (uncached: %s)\n",
+			caching.Writes, f.pricingService.FormatCost(caching.WriteCost, cfg.CostPrecision),
+			caching.Reads, f.pricingService.FormatCost(caching.ReadCost, cfg.CostPrecision),
+			f.pricingService.FormatCost(caching.Total, cfg.CostPrecision),
+			f.pricingService.FormatCost(caching.UncachedCost, cfg.CostPrecision))
+	}
+
+	if totals := computeSystemPromptTotals(results); totals != nil && totals.Requests > 1 {
+		fmt.Printf("System prompt: %d tokens per request x %d requests = %d tokens (user content: %d tokens)\n",
+			totals.PerRequest, totals.Requests, totals.System, totals.UserContent)
+	}
+
+	if cfg.GroupBy == config.GroupByExtension {
+		f.printExtensionBreakdown(results, cfg)
+	}
+
+	if cfg.Stats {
+		printFileStats(results)
+	}
+
+	if cfg.Offline {
+		fmt.Println(offlineNote)
 	}
 
 	return nil
 }
 
-func printTreeNode(node *processor.Result, prefix string, verbose bool) {
+// printExtensionBreakdown prints token totals grouped by file extension, most expensive first
+func (f *TreeFormatter) printExtensionBreakdown(results []*processor.Result, cfg *config.Config) {
+	entries := sortedExtensions(groupByExtension(results))
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println("By extension:")
+	for _, entry := range entries {
+		line := fmt.Sprintf("  %s: %d tokens across %d files", entry.Ext, entry.Totals.Tokens, entry.Totals.Files)
+		if cfg.ShowCost {
+			cost := f.pricingService.CalculateCost(entry.Totals.Tokens, cfg.Model)
+			line += fmt.Sprintf(" (%s)", f.pricingService.FormatCost(cost, cfg.CostPrecision))
+		}
+		fmt.Println(line)
+	}
+}
+
+// printFileStats prints the distribution of tokens per file
+func printFileStats(results []*processor.Result) {
+	stats := computeFileStats(results)
+	if stats.Files == 0 {
+		return
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Tokens per file (%d files):\n", stats.Files)
+	fmt.Printf("  Min: %d | Median: %d | P90: %d | P95: %d | Max: %d | Mean: %.1f\n",
+		stats.Min, stats.Median, stats.P90, stats.P95, stats.Max, stats.Mean)
+}
+
+// printTreeNode prints a directory and its files
+func (f *TreeFormatter) printTreeNode(node *processor.Result, prefix string, cfg *config.Config) {
+	verbose := cfg.Verbose
 	basePath := filepath.Base(node.Path)
 	if node.IsDir && len(node.Children) > 0 {
 		fmt.Printf("%s%s/\n", prefix, basePath)
 
-		for i, child := range node.Children {
-			isLast := i == len(node.Children)-1
+		var rows []treeRow
+		children := sortedChildren(node.Children, cfg.Sort)
+		for i, child := range children {
+			isLast := i == len(children)-1
 			childPrefix := prefix + "  "
 
 			if child.Error != nil {
 				fmt.Fprintf(os.Stderr, "%s%s: ERROR - %v\n", childPrefix, filepath.Base(child.Path), child.Error)
+			} else if child.SkippedBinary {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "%s%s: skipped (binary)\n", childPrefix, filepath.Base(child.Path))
+				}
 			} else {
 				cachedMark := ""
 				if verbose && child.Cached {
@@ -93,8 +178,55 @@ func printTreeNode(node *processor.Result, prefix string, verbose bool) {
 					connector = "└─"
 				}
 
-				fmt.Printf("%s%s %s: %d tokens%s%s\n", prefix, connector, filepath.Base(child.Path), child.Tokens, tokensPerLine, cachedMark)
+				rows = append(rows, treeRow{
+					text:   fmt.Sprintf("%s%s %s: %d tokens%s%s%s%s%s%s", prefix, connector, filepath.Base(child.Path), child.Tokens, imageSuffix(child), truncatedSuffix(child), chunkedSuffix(child), systemPromptSuffix(child), tokensPerLine, cachedMark),
+					tokens: child.Tokens,
+				})
 			}
 		}
+		f.printRows(rows, cfg)
+	}
+}
+
+// sortedChildren returns children in the order selected by --sort. Children are already sorted
+// by path; by tokens or cost, the most expensive come first and ties keep path order. Every
+// file is priced with the same model, so cost order is token order.
+func sortedChildren(children []*processor.Result, order string) []*processor.Result {
+	if order != config.SortTokens && order != config.SortCost {
+		return children
+	}
+	sorted := append([]*processor.Result(nil), children...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Tokens > sorted[j].Tokens
+	})
+	return sorted
+}
+
+// treeRow is a file line of tree output with the tokens its cost is computed from
+type treeRow struct {
+	text   string
+	tokens int
+}
+
+// printRows prints file lines. In verbose mode with costs shown, each line is followed by the
+// file's estimated cost, right-aligned in a column after the longest line.
+func (f *TreeFormatter) printRows(rows []treeRow, cfg *config.Config) {
+	if !cfg.Verbose || !cfg.ShowCost {
+		for _, row := range rows {
+			fmt.Println(row.text)
+		}
+		return
+	}
+
+	costs := make([]string, len(rows))
+	textWidth, costWidth := 0, 0
+	for i, row := range rows {
+		costs[i] = f.pricingService.FormatCost(f.pricingService.CalculateCost(row.tokens, cfg.Model), cfg.CostPrecision)
+		textWidth = max(textWidth, utf8.RuneCountInString(row.text))
+		costWidth = max(costWidth, utf8.RuneCountInString(costs[i]))
+	}
+	for i, row := range rows {
+		padding := textWidth - utf8.RuneCountInString(row.text) + costWidth - utf8.RuneCountInString(costs[i])
+		fmt.Printf("%s  %s%s\n", row.text, strings.Repeat(" ", padding), costs[i])
 	}
 }
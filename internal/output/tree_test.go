@@ -0,0 +1,101 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// treeFileNames returns the file names of tree output's child rows, in printed order
+func treeFileNames(stdout string) []string {
+	var names []string
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "├─ ") && !strings.HasPrefix(line, "└─ ") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(line, "├─ "), "└─ "), ":")
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestTreeSortByTokens(t *testing.T) {
+	// Children arrive in path order, as buildTree leaves them
+	dir := &processor.Result{
+		Path:  "dir",
+		IsDir: true,
+		Children: []*processor.Result{
+			{Path: "dir/a.txt", Tokens: 20},
+			{Path: "dir/b.txt", Tokens: 500},
+			{Path: "dir/c.txt", Tokens: 75},
+			{Path: "dir/d.txt", Tokens: 20},
+			{Path: "dir/e.txt", Tokens: 3000},
+		},
+		Tokens: 3615,
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{config.SortName, []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"}},
+		// Ties (a.txt and d.txt) keep path order
+		{config.SortTokens, []string{"e.txt", "b.txt", "c.txt", "a.txt", "d.txt"}},
+		{config.SortCost, []string{"e.txt", "b.txt", "c.txt", "a.txt", "d.txt"}},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{Model: pricing.DefaultModel, Sort: tt.sort}
+		stdout, _ := captureOutput(t, func() {
+			if err := NewTreeFormatter(pricing.New("")).Format([]*processor.Result{dir}, cfg); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if got := treeFileNames(stdout); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("--sort %s printed %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+	// Sorting for output must not reorder the results themselves
+	if dir.Children[0].Path != "dir/a.txt" {
+		t.Errorf("children were reordered in place: first is %s", dir.Children[0].Path)
+	}
+}
+
+func TestTreeVerboseCostColumnGolden(t *testing.T) {
+	results := []*processor.Result{
+		{Path: "README.md", Tokens: 1000, LineCount: 40, AvgTokensPerLine: 25},
+		{
+			Path:  "prompts",
+			IsDir: true,
+			Children: []*processor.Result{
+				{Path: "prompts/agent.md", Tokens: 250000, LineCount: 5000, AvgTokensPerLine: 50, Cached: true},
+				{Path: "prompts/logo.png", SkippedBinary: true},
+				{Path: "prompts/short.txt", Tokens: 12, LineCount: 4, AvgTokensPerLine: 3},
+			},
+			Tokens: 250012,
+		},
+	}
+	cfg := &config.Config{Model: pricing.DefaultModel, ShowCost: true, Verbose: true, CostPrecision: 6}
+
+	stdout, _ := captureOutput(t, func() {
+		if err := NewTreeFormatter(pricing.New("")).Format(results, cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// Costs are right-aligned in a column after the longest line of each group of files
+	want := "README.md: 1000 tokens (25.0 tokens/line)  $0.003000\n" +
+		"prompts/\n" +
+		"├─ agent.md: 250000 tokens (50.0 tokens/line) (cached)  $0.750000\n" +
+		"└─ short.txt: 12 tokens (3.0 tokens/line)               $0.000036\n" +
+		"--------------------------------------------------\n" +
+		"Total: 251012 tokens across 3 files (1 skipped as binary)\n" +
+		"Estimated cost: $0.753036\n"
+	if stdout != want {
+		t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+	}
+}
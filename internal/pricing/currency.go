@@ -0,0 +1,55 @@
+package pricing
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency is the currency model prices are published in
+const DefaultCurrency = "USD"
+
+// exchangeRatesAsOf is the date the built-in exchange rates were last updated
+const exchangeRatesAsOf = "2025-11-01"
+
+// Currency describes a display currency and its conversion rate from USD
+type Currency struct {
+	Code   string  // ISO 4217 code, e.g. EUR
+	Symbol string  // Symbol printed before amounts
+	Rate   float64 // Units of this currency per 1 USD
+}
+
+// Built-in exchange rates (units per 1 USD, as of exchangeRatesAsOf)
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", Symbol: "$", Rate: 1.00},
+	"EUR": {Code: "EUR", Symbol: "€", Rate: 0.87},
+	"GBP": {Code: "GBP", Symbol: "£", Rate: 0.76},
+	"JPY": {Code: "JPY", Symbol: "¥", Rate: 154.0},
+	"CAD": {Code: "CAD", Symbol: "CA$", Rate: 1.40},
+	"AUD": {Code: "AUD", Symbol: "A$", Rate: 1.53},
+	"CHF": {Code: "CHF", Symbol: "CHF ", Rate: 0.81},
+	"INR": {Code: "INR", Symbol: "₹", Rate: 88.7},
+}
+
+// LookupCurrency returns the built-in currency for an ISO code, case-insensitively
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return currency, ok
+}
+
+// SupportedCurrencies returns the codes of all built-in currencies, sorted
+func SupportedCurrencies() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// RateSource describes where the currency's exchange rate comes from, for verbose output
+func (c Currency) RateSource() string {
+	if c.Code == DefaultCurrency {
+		return "prices are published in USD"
+	}
+	return "built-in rate as of " + exchangeRatesAsOf + ", may be stale"
+}
@@ -0,0 +1,151 @@
+package pricing
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModelInfo describes a model with known pricing. Prices are per 1M tokens in the pricer's currency.
+type ModelInfo struct {
+	Name            string   // Canonical model name, e.g. claude-sonnet-4-5
+	Aliases         []string // Alternate names and short aliases with the same pricing
+	Family          string   // haiku, sonnet or opus; empty for names that don't follow the Claude scheme
+	Version         string   // e.g. "4.5"; empty when Family is
+	InputPrice      float64
+	CacheWritePrice float64
+	CacheReadPrice  float64
+}
+
+// modelFamilies are the Claude model families, in the order Models lists them
+var modelFamilies = []string{"haiku", "sonnet", "opus"}
+
+// Models returns every model with known pricing, including pricing file overrides. Names that
+// differ only in format (claude-sonnet-4-5, claude-sonnet-4.5) are listed once, with the
+// others as aliases. Models are ordered by family, then newest version first.
+func (p *Pricer) Models() []ModelInfo {
+	names := make(map[string]bool)
+	for name := range modelPricing {
+		names[name] = true
+	}
+	for name := range p.overrides {
+		names[name] = true
+	}
+
+	type groupKey struct {
+		family, version string
+		price           float64
+	}
+	groups := make(map[groupKey][]string)
+	for name := range names {
+		price, _ := p.inputPrice(name)
+		family, version, ok := parseModelName(name)
+		key := groupKey{family: family, version: version, price: price}
+		if !ok {
+			key = groupKey{family: name, price: price} // Not a Claude name: a group of its own
+		}
+		groups[key] = append(groups[key], name)
+	}
+
+	models := make([]ModelInfo, 0, len(groups))
+	for key, group := range groups {
+		sort.Strings(group)
+		name := group[0]
+		if family, version, ok := parseModelName(name); ok {
+			canonical := "claude-" + family + "-" + strings.ReplaceAll(version, ".", "-")
+			for _, candidate := range group {
+				if candidate == canonical {
+					name = canonical
+				}
+			}
+		}
+
+		info := ModelInfo{
+			Name:            name,
+			InputPrice:      key.price * p.currency.Rate,
+			CacheWritePrice: p.CalculateCostWithCaching(0, 1_000_000, 0, name),
+			CacheReadPrice:  p.CalculateCostWithCaching(0, 0, 1_000_000, name),
+		}
+		info.Family, info.Version, _ = parseModelName(name)
+		for _, alias := range group {
+			if alias != name {
+				info.Aliases = append(info.Aliases, alias)
+			}
+		}
+		for alias, target := range modelAliases {
+			if target == name {
+				info.Aliases = append(info.Aliases, alias)
+			}
+		}
+		models = append(models, info)
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		a, b := models[i], models[j]
+		if fa, fb := familyRank(a.Family), familyRank(b.Family); fa != fb {
+			return fa < fb
+		}
+		if a.Version != b.Version {
+			return compareVersions(a.Version, b.Version) > 0
+		}
+		return a.Name < b.Name
+	})
+	return models
+}
+
+// parseModelName splits a Claude model name in any accepted format (claude-sonnet-4-5,
+// claude-sonnet-4.5, claude-3-5-sonnet) into its family and dotted version
+func parseModelName(name string) (family, version string, ok bool) {
+	rest, found := strings.CutPrefix(name, "claude-")
+	if !found {
+		return "", "", false
+	}
+
+	var versionParts []string
+	for _, part := range strings.FieldsFunc(rest, func(r rune) bool { return r == '-' || r == '.' }) {
+		switch {
+		case familyRank(part) < len(modelFamilies) && family == "":
+			family = part
+		case len(part) <= 2 && strings.Trim(part, "0123456789") == "":
+			versionParts = append(versionParts, part)
+		default:
+			return "", "", false // Snapshot dates and unknown words
+		}
+	}
+	if family == "" || len(versionParts) == 0 {
+		return "", "", false
+	}
+	return family, strings.Join(versionParts, "."), true
+}
+
+// familyRank returns the position of family in modelFamilies, or len(modelFamilies) if unknown
+func familyRank(family string) int {
+	for i, known := range modelFamilies {
+		if family == known {
+			return i
+		}
+	}
+	return len(modelFamilies)
+}
+
+// compareVersions compares dotted versions numerically, returning -1, 0 or 1. Missing parts
+// count as zero, so 4 sorts before 4.5.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
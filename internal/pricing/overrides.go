@@ -0,0 +1,48 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultPricingFile is the pricing override file under the user's home directory
+const defaultPricingFile = ".cc-token/pricing.json"
+
+// loadOverrides reads input prices (USD per 1M tokens, keyed by model) from path, or from
+// ~/.cc-token/pricing.json when path is empty. Models not in the built-in table are accepted.
+// A missing default file is ignored; an unreadable or malformed file prints a warning and
+// the built-in prices are used instead.
+func loadOverrides(path string) map[string]float64 {
+	explicit := path != ""
+	if !explicit {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(homeDir, defaultPricingFile)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if explicit || !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to read pricing file, using built-in prices: %v\n", err)
+		}
+		return nil
+	}
+
+	var overrides map[string]float64
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to parse pricing file %s, using built-in prices: %v\n", path, err)
+		return nil
+	}
+	for model, price := range overrides {
+		if price < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: Negative price for %s in pricing file %s, using built-in prices\n", model, path)
+			return nil
+		}
+	}
+
+	return overrides
+}
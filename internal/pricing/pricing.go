@@ -1,7 +1,14 @@
 // Package pricing handles model pricing, cost calculation, and model alias resolution for cc-token.
 package pricing
 
-import "strings"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/utils"
+)
 
 // Model pricing (USD per 1M tokens - input pricing)
 // Source: https://www.anthropic.com/pricing (as of 2025-11-01)
@@ -36,47 +43,246 @@ var modelPricing = map[string]float64{
 	"claude-3-sonnet":   3.00,  // Alternate format
 }
 
+// Prompt cache write pricing (USD per 1M tokens, 1.25x input for the 5-minute cache)
+var cacheWritePricing = map[string]float64{
+	// Claude 4.x models
+	"claude-sonnet-4-5": 3.75,  // Claude Sonnet 4.5
+	"claude-sonnet-4.5": 3.75,  // Alternate format
+	"claude-haiku-4-5":  1.25,  // Claude Haiku 4.5
+	"claude-haiku-4.5":  1.25,  // Alternate format
+	"claude-opus-4-1":   18.75, // Claude Opus 4.1
+	"claude-opus-4.1":   18.75, // Alternate format
+	"claude-sonnet-4":   3.75,  // Claude Sonnet 4
+	"claude-4-sonnet":   3.75,  // Alternate format
+	"claude-opus-4":     18.75, // Generic Claude Opus 4 (fallback to 4.1 pricing)
+	"claude-haiku-4":    1.25,  // Generic Claude Haiku 4 (fallback to 4.5 pricing)
+
+	// Claude 3.x models
+	"claude-haiku-3-5":  1.00,  // Claude Haiku 3.5
+	"claude-3-5-haiku":  1.00,  // Alternate format
+	"claude-haiku-3.5":  1.00,  // Alternate format
+	"claude-sonnet-3-7": 3.75,  // Claude Sonnet 3.7 (legacy)
+	"claude-3-7-sonnet": 3.75,  // Alternate format
+	"claude-sonnet-3.7": 3.75,  // Alternate format
+	"claude-3-5-sonnet": 3.75,  // Claude Sonnet 3.5 (legacy, same as 3.7)
+	"claude-sonnet-3-5": 3.75,  // Alternate format
+	"claude-sonnet-3.5": 3.75,  // Alternate format
+	"claude-opus-3":     18.75, // Claude Opus 3 (legacy)
+	"claude-3-opus":     18.75, // Alternate format
+	"claude-haiku-3":    0.30,  // Claude Haiku 3 (legacy)
+	"claude-3-haiku":    0.30,  // Alternate format
+	"claude-sonnet-3":   3.75,  // Claude Sonnet 3 (legacy)
+	"claude-3-sonnet":   3.75,  // Alternate format
+}
+
+// Prompt cache read pricing (USD per 1M tokens, 0.1x input)
+var cacheReadPricing = map[string]float64{
+	// Claude 4.x models
+	"claude-sonnet-4-5": 0.30, // Claude Sonnet 4.5
+	"claude-sonnet-4.5": 0.30, // Alternate format
+	"claude-haiku-4-5":  0.10, // Claude Haiku 4.5
+	"claude-haiku-4.5":  0.10, // Alternate format
+	"claude-opus-4-1":   1.50, // Claude Opus 4.1
+	"claude-opus-4.1":   1.50, // Alternate format
+	"claude-sonnet-4":   0.30, // Claude Sonnet 4
+	"claude-4-sonnet":   0.30, // Alternate format
+	"claude-opus-4":     1.50, // Generic Claude Opus 4 (fallback to 4.1 pricing)
+	"claude-haiku-4":    0.10, // Generic Claude Haiku 4 (fallback to 4.5 pricing)
+
+	// Claude 3.x models
+	"claude-haiku-3-5":  0.08, // Claude Haiku 3.5
+	"claude-3-5-haiku":  0.08, // Alternate format
+	"claude-haiku-3.5":  0.08, // Alternate format
+	"claude-sonnet-3-7": 0.30, // Claude Sonnet 3.7 (legacy)
+	"claude-3-7-sonnet": 0.30, // Alternate format
+	"claude-sonnet-3.7": 0.30, // Alternate format
+	"claude-3-5-sonnet": 0.30, // Claude Sonnet 3.5 (legacy, same as 3.7)
+	"claude-sonnet-3-5": 0.30, // Alternate format
+	"claude-sonnet-3.5": 0.30, // Alternate format
+	"claude-opus-3":     1.50, // Claude Opus 3 (legacy)
+	"claude-3-opus":     1.50, // Alternate format
+	"claude-haiku-3":    0.03, // Claude Haiku 3 (legacy)
+	"claude-3-haiku":    0.03, // Alternate format
+	"claude-sonnet-3":   0.30, // Claude Sonnet 3 (legacy)
+	"claude-3-sonnet":   0.30, // Alternate format
+}
+
+// Map of short aliases to full model names (latest versions)
+var modelAliases = map[string]string{
+	"sonnet": "claude-sonnet-4-5", // Latest Sonnet (Claude 4.5)
+	"haiku":  "claude-haiku-4-5",  // Latest Haiku (Claude 4.5)
+	"opus":   "claude-opus-4-1",   // Latest Opus (Claude 4.1)
+}
+
 const (
 	// DefaultModel is the default model to use for token counting
 	DefaultModel = "claude-sonnet-4-5"
+	// DefaultCostPrecision is the default number of decimal places for displayed costs
+	DefaultCostPrecision = 6
+	// MaxCostPrecision caps decimal places, including those added by auto-scaling
+	MaxCostPrecision = 12
+	// costSignificantDigits is the minimum number of significant digits shown for sub-cent costs
+	costSignificantDigits = 2
+
+	// ContextWindow is the input context window of the Claude models priced here, in tokens
+	ContextWindow = 200_000
+
+	// bytesPerTokenEstimate is the average bytes per token assumed when estimating from file size
+	bytesPerTokenEstimate = 4
+
+	// Prompt cache rates relative to input pricing, used for models missing from the cache maps
+	cacheWriteMultiplier = 1.25
+	cacheReadMultiplier  = 0.1
 )
 
 // Pricer handles cost calculations for token counts
-type Pricer struct{}
+type Pricer struct {
+	overrides map[string]float64 // Input prices per 1M tokens from a pricing file, by model
+	currency  Currency           // Currency costs are converted to and formatted in
+}
+
+// New creates a new Pricer instance. Input prices from pricingFile, or ~/.cc-token/pricing.json
+// when pricingFile is empty, are merged over the built-in defaults; see loadOverrides.
+func New(pricingFile string) *Pricer {
+	return &Pricer{overrides: loadOverrides(pricingFile), currency: currencies[DefaultCurrency]}
+}
+
+// SetCurrency converts all subsequent costs to currency and formats them with its symbol
+func (p *Pricer) SetCurrency(currency Currency) {
+	p.currency = currency
+}
+
+// Currency returns the currency costs are reported in
+func (p *Pricer) Currency() Currency {
+	return p.currency
+}
 
-// New creates a new Pricer instance
-func New() *Pricer {
-	return &Pricer{}
+// inputPrice returns the input price per 1M tokens for the model, preferring overrides
+func (p *Pricer) inputPrice(model string) (float64, bool) {
+	if price, ok := p.overrides[model]; ok {
+		return price, true
+	}
+	price, ok := modelPricing[model]
+	return price, ok
 }
 
 // CalculateCost estimates the API cost for the given number of tokens using the specified model.
-// It returns the cost in USD based on the model's pricing per million input tokens.
+// It returns the cost in the configured currency (USD by default) based on the model's pricing
+// per million input tokens.
 func (p *Pricer) CalculateCost(tokens int, model string) float64 {
-	pricePerMillion, ok := modelPricing[model]
+	return p.usdCost(tokens, model) * p.currency.Rate
+}
+
+// usdCost returns the input cost of tokens in USD, before currency conversion
+func (p *Pricer) usdCost(tokens int, model string) float64 {
+	pricePerMillion, ok := p.inputPrice(model)
 	if !ok {
 		pricePerMillion = 3.00 // Default to Sonnet pricing
 	}
 	return float64(tokens) * pricePerMillion / 1_000_000
 }
 
+// CalculateCostWithCaching estimates the cost of a request that sends tokens uncached input
+// tokens, writes cacheWriteTokens to the prompt cache and reads cacheReadTokens from it.
+func (p *Pricer) CalculateCostWithCaching(tokens, cacheWriteTokens, cacheReadTokens int, model string) float64 {
+	// Cache rates for overridden models are derived from the new input price
+	_, overridden := p.overrides[model]
+
+	writePerMillion, ok := cacheWritePricing[model]
+	if !ok || overridden {
+		writePerMillion = p.usdCost(1_000_000, model) * cacheWriteMultiplier
+	}
+	readPerMillion, ok := cacheReadPricing[model]
+	if !ok || overridden {
+		readPerMillion = p.usdCost(1_000_000, model) * cacheReadMultiplier
+	}
+
+	usd := p.usdCost(tokens, model) +
+		float64(cacheWriteTokens)*writePerMillion/1_000_000 +
+		float64(cacheReadTokens)*readPerMillion/1_000_000
+	return usd * p.currency.Rate
+}
+
+// EstimateTokensFromSize roughly estimates the token count of a text file from its size,
+// for budgeting before the exact count is known
+func EstimateTokensFromSize(bytes int64) int {
+	return int((bytes + bytesPerTokenEstimate - 1) / bytesPerTokenEstimate)
+}
+
+// CostPerToken returns the input price of a single token for the specified model, in the
+// configured currency
+func (p *Pricer) CostPerToken(model string) float64 {
+	return p.CalculateCost(1, model)
+}
+
+// IsKnownModel reports whether pricing is known for the model, i.e. CalculateCost
+// doesn't fall back to the default price.
+func (p *Pricer) IsKnownModel(model string) bool {
+	_, ok := p.inputPrice(model)
+	return ok
+}
+
+// SuggestModel returns the known model name or alias closest to model, for "did you mean"
+// hints, or "" if none is within a few edits of it
+func (p *Pricer) SuggestModel(model string) string {
+	model = strings.ToLower(strings.TrimSpace(model))
+	maxDistance := max(2, len(model)/4)
+
+	candidates := make([]string, 0, len(modelPricing)+len(p.overrides)+len(modelAliases))
+	for name := range modelPricing {
+		candidates = append(candidates, name)
+	}
+	for name := range p.overrides {
+		candidates = append(candidates, name)
+	}
+	for alias := range modelAliases {
+		candidates = append(candidates, alias)
+	}
+	sort.Strings(candidates) // Deterministic choice between equally close names
+
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if distance := utils.Levenshtein(model, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
 // ResolveModelAlias converts short model aliases (haiku, sonnet, opus) to their full
 // model names. It performs case-insensitive matching and returns the original model
 // name if no alias is found.
 func (p *Pricer) ResolveModelAlias(model string) string {
-	// Map of short aliases to full model names (latest versions)
-	aliases := map[string]string{
-		"sonnet": "claude-sonnet-4-5", // Latest Sonnet (Claude 4.5)
-		"haiku":  "claude-haiku-4-5",  // Latest Haiku (Claude 4.5)
-		"opus":   "claude-opus-4-1",   // Latest Opus (Claude 4.1)
-	}
-
 	// Convert to lowercase for case-insensitive matching
 	modelLower := strings.ToLower(strings.TrimSpace(model))
 
-	if resolved, ok := aliases[modelLower]; ok {
+	if resolved, ok := modelAliases[modelLower]; ok {
 		return resolved
 	}
 
 	// Return original if no alias found
 	return model
 }
+
+// FormatCost renders a cost with the configured currency's symbol; see FormatAmount
+func (p *Pricer) FormatCost(cost float64, precision int) string {
+	return p.currency.Symbol + FormatAmount(cost, precision)
+}
+
+// FormatAmount renders a cost without a currency symbol, using the given number of decimal
+// places. Costs too small to show at that precision are auto-scaled so at least two
+// significant digits remain visible.
+func FormatAmount(cost float64, precision int) string {
+	decimals := precision
+	if cost > 0 && cost < 0.01 {
+		// Decimal places needed to reach the leading digit, plus the extra significant digits
+		needed := int(-math.Floor(math.Log10(cost))) + costSignificantDigits - 1
+		if needed > decimals {
+			decimals = needed
+		}
+	}
+	if decimals > MaxCostPrecision {
+		decimals = MaxCostPrecision
+	}
+	return fmt.Sprintf("%.*f", decimals, cost)
+}
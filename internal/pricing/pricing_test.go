@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		cost      float64
+		precision int
+		want      string
+	}{
+		{"default precision", 1.23456789, DefaultCostPrecision, "1.234568"},
+		{"two decimals", 1234.5678, 2, "1234.57"},
+		{"no decimals", 12.7, 0, "13"},
+		{"zero", 0, 2, "0.00"},
+		{"sub-cent cost auto-scales", 0.000123, 2, "0.00012"},
+		{"sub-cent cost already visible", 0.000123, 8, "0.00012300"},
+		{"auto-scaling is capped", 1e-15, 2, "0.000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAmount(tt.cost, tt.precision); got != tt.want {
+				t.Errorf("FormatAmount(%v, %d) = %q, want %q", tt.cost, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCostUsesCurrencySymbol(t *testing.T) {
+	if got := New("").FormatCost(0.5, 2); got != "$0.50" {
+		t.Errorf("FormatCost = %q, want $0.50", got)
+	}
+}
+
+func TestFormatCostInOtherCurrencies(t *testing.T) {
+	tests := []struct {
+		code string
+		cost float64
+		want string
+	}{
+		{"eur", 1.5, "€1.50"},
+		{"GBP", 0.25, "£0.25"},
+		{"JPY", 150, "¥150.00"},
+		{"CHF", 3, "CHF 3.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			currency, ok := LookupCurrency(tt.code)
+			if !ok {
+				t.Fatalf("LookupCurrency(%q) found nothing", tt.code)
+			}
+			p := New("")
+			p.SetCurrency(currency)
+			if got := p.FormatCost(tt.cost, 2); got != tt.want {
+				t.Errorf("FormatCost(%v) = %q, want %q", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateCostConvertsFromUSD(t *testing.T) {
+	usd := New("").CalculateCost(1_000_000, DefaultModel)
+	eur, _ := LookupCurrency("EUR")
+	p := New("")
+	p.SetCurrency(eur)
+	if got, want := p.CalculateCost(1_000_000, DefaultModel), usd*eur.Rate; math.Abs(got-want) > 1e-9 {
+		t.Errorf("CalculateCost in EUR = %v, want %v (USD %v at %v)", got, want, usd, eur.Rate)
+	}
+	if _, ok := LookupCurrency("XYZ"); ok {
+		t.Error("LookupCurrency(XYZ) succeeded, want an unknown currency")
+	}
+}
+
+func TestSuggestModel(t *testing.T) {
+	p := New("")
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"claude-sonet-4-5", "claude-sonnet-4-5"},
+		{"Claude-Haiku-4-6", "claude-haiku-4-5"},
+		{"claude-opus-4.2", "claude-opus-4.1"},
+		{"sonet", "sonnet"},
+		{"gpt-4o", ""},
+		{"totally-unrelated-model-name", ""},
+	}
+	for _, tt := range tests {
+		if got := p.SuggestModel(tt.model); got != tt.want {
+			t.Errorf("SuggestModel(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package processor
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// binarySniffLen is the number of leading bytes inspected to detect binary files
+const binarySniffLen = 512
+
+// textContentTypes are detected content types that are text despite not starting with "text/"
+var textContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+// isBinaryFile sniffs the start of a file and reports whether it looks like non-text data
+func isBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return isBinaryContent(head[:n]), nil
+}
+
+// isBinaryContent reports whether content looks like binary data: it contains a NUL byte or
+// its sniffed content type is not a text type
+func isBinaryContent(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+	if bytes.IndexByte(head, 0) != -1 {
+		return true
+	}
+
+	contentType := http.DetectContentType(head)
+	if strings.HasPrefix(contentType, "text/") {
+		return false
+	}
+	for _, textType := range textContentTypes {
+		if strings.HasPrefix(contentType, textType) {
+			return false
+		}
+	}
+	return true
+}
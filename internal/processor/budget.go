@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+// ErrBudgetExceeded is returned when a directory's estimated cost exceeds --max-cost and the
+// run wasn't confirmed
+var ErrBudgetExceeded = errors.New("estimated cost exceeds --max-cost")
+
+// SetPricer sets the prices used to estimate costs for the --max-cost budget guard and
+// progress updates
+func (p *Processor) SetPricer(pricer *pricing.Pricer) {
+	p.pricer = pricer
+}
+
+// checkBudget estimates the cost of counting files before any request is made and, when it
+// exceeds --max-cost, asks for confirmation. With --yes or without an interactive stdin
+// there's nobody to ask, so it aborts instead.
+func (p *Processor) checkBudget(dirPath string, files []walkedFile) error {
+	if p.config.MaxCost <= 0 || p.pricer == nil {
+		return nil
+	}
+
+	estimate := p.pricer.CalculateCost(p.estimateTokens(files), p.config.Model)
+	if estimate <= p.config.MaxCost {
+		return nil
+	}
+
+	budgetErr := fmt.Errorf("%w: %s for %d files in %s (budget %s)", ErrBudgetExceeded,
+		p.pricer.FormatCost(estimate, p.config.CostPrecision), len(files), dirPath,
+		p.pricer.FormatCost(p.config.MaxCost, p.config.CostPrecision))
+	if p.config.SkipConfirmation || !isInteractive() {
+		return budgetErr
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %v\nProceed anyway? [y/N]: ", budgetErr)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return budgetErr
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		return budgetErr
+	}
+	return nil
+}
+
+// estimateTokens sums cached counts for unchanged files and a size-based estimate for the
+// rest. Files over --max-size are left out since they won't be counted, unless --chunk-size is set.
+// Cached entries are peeked so an estimate alone doesn't keep them from LRU eviction.
+func (p *Processor) estimateTokens(files []walkedFile) int {
+	total := 0
+	for _, file := range files {
+		if file.info.Size() > p.config.MaxSize && p.config.ChunkSize == 0 {
+			continue
+		}
+		if p.cache != nil && !p.config.Refresh {
+			if entry, ok := p.cache.Peek(p.config.Model, file.path); ok && entry.Modified.Equal(file.info.ModTime()) {
+				total += entry.Tokens
+				continue
+			}
+		}
+		total += pricing.EstimateTokensFromSize(file.info.Size())
+	}
+	return total
+}
+
+// isInteractive reports whether stdin is a terminal that can answer a prompt
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
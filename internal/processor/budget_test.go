@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/cache"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+func TestEstimateTokensPeeksCache(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"cached.txt":   "one two three",
+		"uncached.txt": "four five six seven",
+	})
+	cacheDir := t.TempDir()
+	c, err := cache.Load(cacheDir)
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+	cfg := testConfig()
+
+	var files []walkedFile
+	for _, name := range []string{"cached.txt", "uncached.txt"} {
+		path := filepath.Join(root, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, walkedFile{path: path, info: info})
+	}
+	cachedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	c.Set(cfg.Model, files[0].path, cache.Entry{Tokens: 1000, Modified: files[0].info.ModTime(), CachedAt: cachedAt})
+
+	got := New(&fakeCounter{}, c, cfg).estimateTokens(files)
+	if want := 1000 + pricing.EstimateTokensFromSize(files[1].info.Size()); got != want {
+		t.Errorf("estimateTokens = %d, want %d", got, want)
+	}
+
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded, err := cache.Load(cacheDir)
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+	if entry, _ := reloaded.Peek(cfg.Model, files[0].path); !entry.LastAccessed.Equal(cachedAt) {
+		t.Errorf("LastAccessed = %v after estimating, want unchanged %v", entry.LastAccessed, cachedAt)
+	}
+}
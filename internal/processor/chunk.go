@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// countChunks counts each piece of a file split by splitChunks and sums them. The
+// --system prompt is sent with the first chunk only, so it is counted once. The total is
+// approximate: tokens can't merge across chunk boundaries, and each request adds its own
+// message overhead.
+func (p *Processor) countChunks(parts [][]byte) (int, error) {
+	tokens := 0
+	for i, part := range parts {
+		system := ""
+		if i == 0 {
+			system = p.config.SystemPrompt
+		}
+		n, err := p.limitedCount(func() (int, error) {
+			return p.apiClient.CountTokensWithSystem(string(part), system, p.config.Model)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("chunk %d of %d: %w", i+1, len(parts), err)
+		}
+		tokens += n
+	}
+	return tokens, nil
+}
+
+// splitChunks splits content into pieces of at most size bytes, breaking after the last
+// newline in each piece when there is one, and never inside a UTF-8 sequence
+func splitChunks(content []byte, size int) [][]byte {
+	var chunks [][]byte
+	for len(content) > size {
+		end := size
+		if newline := bytes.LastIndexByte(content[:end], '\n'); newline > 0 {
+			end = newline + 1
+		} else {
+			for end > 0 && !utf8.RuneStart(content[end]) {
+				end--
+			}
+			if end == 0 {
+				end = size
+			}
+		}
+		chunks = append(chunks, content[:end])
+		content = content[end:]
+	}
+	if len(content) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, content)
+	}
+	return chunks
+}
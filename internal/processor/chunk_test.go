@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		size    int
+		want    []string
+	}{
+		{"fits", "abc\n", 10, []string{"abc\n"}},
+		{"empty", "", 10, []string{""}},
+		{"breaks after newline", "one\ntwo\nthree\n", 9, []string{"one\ntwo\n", "three\n"}},
+		{"long line split at size", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"never inside a rune", "ééé", 3, []string{"é", "é", "é"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, chunk := range splitChunks([]byte(tt.content), tt.size) {
+				if len(chunk) > tt.size {
+					t.Errorf("chunk %q exceeds %d bytes", chunk, tt.size)
+				}
+				if !utf8.Valid(chunk) {
+					t.Errorf("chunk %q splits a UTF-8 sequence", chunk)
+				}
+				got = append(got, string(chunk))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("splitChunks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessPathChunksLargeFiles(t *testing.T) {
+	var big bytes.Buffer
+	for i := 0; i < 100; i++ {
+		big.WriteString("alpha beta gamma delta\n")
+	}
+	root := writeFixture(t, map[string]string{
+		"big.txt":   big.String(),
+		"small.txt": "one two",
+		"photo.png": strings.Repeat("x", 4000),
+	})
+
+	cfg := testConfig()
+	cfg.MaxSize = 1000
+	cfg.ChunkSize = 500
+	counter := &fakeCounter{}
+	result, err := New(counter, nil, cfg).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+
+	// Oversized images can't be chunked, so the walk skips them as before instead of failing
+	if got := strings.Join(countedFiles(t, root, result), ","); got != "big.txt,small.txt" {
+		t.Fatalf("counted %s, want big.txt,small.txt", got)
+	}
+	for _, child := range result.Children {
+		if child.Error != nil {
+			t.Errorf("%s: unexpected error %v", child.Path, child.Error)
+		}
+		if !strings.HasSuffix(child.Path, "big.txt") {
+			continue
+		}
+		// 2300 bytes in chunks of 21 whole 23-byte lines
+		if child.Chunks != 5 || child.Tokens != 400 {
+			t.Errorf("big.txt: %d tokens in %d chunks, want 400 in 5", child.Tokens, child.Chunks)
+		}
+	}
+	if counter.calls() != 6 {
+		t.Errorf("API requests = %d, want 6 (5 chunks + small.txt)", counter.calls())
+	}
+}
+
+func TestProcessPathRejectsLargeFilesWithoutChunking(t *testing.T) {
+	root := writeFixture(t, map[string]string{"big.txt": strings.Repeat("word ", 300)})
+	cfg := testConfig()
+	cfg.MaxSize = 1000
+
+	result, err := New(&fakeCounter{}, nil, cfg).ProcessPath(filepath.Join(root, "big.txt"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "file too large") {
+		t.Errorf("error = %v, want file too large", result.Error)
+	}
+}
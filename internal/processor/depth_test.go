@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessPathMaxDepth(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"a.txt":             "root level",
+		"l1/b.txt":          "one level down",
+		"l1/l2/c.txt":       "two levels down",
+		"l1/l2/l3/d.txt":    "three levels down",
+		"l1/l2/l3/l4/e.txt": "four levels down",
+	})
+
+	tests := []struct {
+		maxDepth int
+		want     []string
+	}{
+		{0, []string{"a.txt"}},
+		{1, []string{"a.txt", "l1/b.txt"}},
+		{2, []string{"a.txt", "l1/b.txt", "l1/l2/c.txt"}},
+		{-1, []string{"a.txt", "l1/b.txt", "l1/l2/c.txt", "l1/l2/l3/d.txt", "l1/l2/l3/l4/e.txt"}},
+	}
+	for _, tt := range tests {
+		cfg := testConfig()
+		cfg.MaxDepth = tt.maxDepth
+		counter := &fakeCounter{}
+		result, err := New(counter, nil, cfg).ProcessPath(root)
+		if err != nil {
+			t.Fatalf("max depth %d: ProcessPath: %v", tt.maxDepth, err)
+		}
+		if got := countedFiles(t, root, result); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("max depth %d: counted %v, want %v", tt.maxDepth, got, tt.want)
+		}
+		// Files below the limit must not reach the counter at all
+		if got := counter.calls(); got != len(tt.want) {
+			t.Errorf("max depth %d: %d counting requests, want %d", tt.maxDepth, got, len(tt.want))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadFileList reads newline-separated paths from path, or from stdin when path is "-". Blank
+// lines and lines starting with "#" are ignored, as in .gitignore files.
+func LoadFileList(path string) ([]string, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file list: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file list: %w", err)
+	}
+	return paths, nil
+}
+
+// ProcessFileList counts exactly the listed files, without walking any directory. Files
+// excluded by the size or extension filters are left out, like in a directory walk; missing
+// files and directories are reported as errors. Results keep the order of the list, and
+// repeated paths are counted once.
+func (p *Processor) ProcessFileList(paths []string) ([]*Result, error) {
+	if _, err := p.systemPromptTokens(); err != nil {
+		return nil, err
+	}
+
+	var files []walkedFile
+	var failed []*Result
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			failed = append(failed, &Result{Path: path, Error: fmt.Errorf("failed to access %s: %w", path, err)})
+		case info.IsDir():
+			failed = append(failed, &Result{Path: path, Error: fmt.Errorf("%s is a directory", path)})
+		case shouldInclude(path, info, p.config):
+			files = append(files, walkedFile{path: path, info: info})
+		}
+	}
+
+	// Enforce --max-cost before any file is sent to the API
+	if err := p.checkBudget("the file list", files); err != nil {
+		return nil, err
+	}
+
+	processed := p.processFiles(files)
+
+	// Merge the failures back in list order
+	byPath := make(map[string]*Result, len(paths))
+	for _, result := range append(processed, failed...) {
+		if result != nil {
+			byPath[result.Path] = result
+		}
+	}
+	results := make([]*Result, 0, len(byPath))
+	for _, path := range paths {
+		if result, ok := byPath[path]; ok {
+			results = append(results, result)
+			delete(byPath, path) // Repeated paths appear once
+		}
+	}
+	return results, nil
+}
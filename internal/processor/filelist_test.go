@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestProcessFileList(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"main.go":         "package main",
+		"README.md":       "read me",
+		"pkg/util.go":     "package pkg util",
+		"pkg/unlisted.go": "never counted",
+	})
+	abs := func(name string) string { return filepath.Join(root, filepath.FromSlash(name)) }
+	manifest := strings.Join([]string{
+		"# changed files",
+		"",
+		abs("pkg/util.go"),
+		"  " + abs("main.go") + "  ",
+		abs("README.md"),
+		abs("pkg/util.go"),
+		abs("missing.go"),
+		abs("pkg"),
+	}, "\n")
+	manifestPath := filepath.Join(t.TempDir(), "changed.txt")
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := LoadFileList(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadFileList: %v", err)
+	}
+	if len(paths) != 6 || paths[1] != abs("main.go") {
+		t.Fatalf("paths = %q, want the 6 listed paths without comments, blank lines or padding", paths)
+	}
+
+	counter := &fakeCounter{}
+	cfg := testConfig()
+	cfg.Extensions = []string{".go"}
+	results, err := New(counter, nil, cfg).ProcessFileList(paths)
+	if err != nil {
+		t.Fatalf("ProcessFileList: %v", err)
+	}
+
+	// README.md is filtered out by extension and the repeated path is counted once
+	var got []string
+	for _, result := range results {
+		status := "ok"
+		if result.Error != nil {
+			status = "error"
+		}
+		got = append(got, filepath.ToSlash(strings.TrimPrefix(result.Path, root+string(os.PathSeparator)))+":"+status)
+	}
+	if want := "pkg/util.go:ok main.go:ok missing.go:error pkg:error"; strings.Join(got, " ") != want {
+		t.Errorf("results = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	requests := append([]string(nil), counter.requests...)
+	sort.Strings(requests)
+	if want := "package main|package pkg util"; strings.Join(requests, "|") != want {
+		t.Errorf("counted %q, want only the listed .go files", requests)
+	}
+}
+
+func TestLoadFileListFromStdin(t *testing.T) {
+	withStdin(t, "a.txt\n# skipped\n\nb.txt\n")
+	paths, err := LoadFileList("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(paths, ",") != "a.txt,b.txt" {
+		t.Errorf("paths = %q, want a.txt and b.txt", paths)
+	}
+}
@@ -2,7 +2,9 @@ package processor
 
 import (
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/iota-uz/cc-token/internal/config"
 )
@@ -10,8 +12,9 @@ import (
 // shouldInclude determines whether a file should be included in processing based on
 // size and extension filters configured by the user.
 func shouldInclude(path string, info os.FileInfo, cfg *config.Config) bool {
-	// Check size
-	if info.Size() > cfg.MaxSize {
+	// Check size; larger text files are still counted, in pieces, with --chunk-size. PDFs and
+	// images can't be split, so they stay excluded.
+	if info.Size() > cfg.MaxSize && (cfg.ChunkSize == 0 || mediaTypeOf(path) != "") {
 		return false
 	}
 
@@ -32,3 +35,60 @@ func shouldInclude(path string, info os.FileInfo, cfg *config.Config) bool {
 
 	return true
 }
+
+// isExcluded reports whether a path relative to the walk root matches any --exclude pattern.
+// Patterns without a slash match a name at any depth; others match the whole relative path,
+// with "**" matching any number of path segments.
+func isExcluded(relPath string, patterns []string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+
+		if !strings.Contains(pattern, "/") {
+			if matched, err := path.Match(pattern, path.Base(relPath)); err == nil && matched {
+				return true
+			}
+			continue
+		}
+
+		if matchGlob(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(relPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments, where a "**" segment matches zero
+// or more path segments. Malformed patterns never match.
+func matchGlob(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive ** and try every possible split
+			rest := pattern[1:]
+			for i := 0; i <= len(segments); i++ {
+				if matchGlob(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// pathDepth returns the number of path segments in a path relative to the walk root, so a
+// directory directly under the root has depth 1
+func pathDepth(relPath string) int {
+	return strings.Count(filepath.ToSlash(relPath), "/") + 1
+}
@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsExcluded(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// The directory itself matches, so the walk prunes it without visiting its files
+		{"testdata/**", "testdata", true},
+		{"testdata/**", "testdata/a/b/c.txt", true},
+		{"testdata/**", "pkg/testdata/x.txt", false},
+		{"**/testdata/**", "pkg/testdata", true},
+		{"*.min.js", "web/static/app.min.js", true},
+		{"*.min.js", "web/static/app.js", false},
+		{"vendor/", "vendor", true},
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/api/guide.md", false},
+		{"[", "anything", false},
+	}
+	for _, tt := range tests {
+		if got := isExcluded(tt.path, []string{tt.pattern}); got != tt.want {
+			t.Errorf("isExcluded(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestProcessPathExcludePrunesSubtree(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"main.go":                  "package main",
+		"testdata/input.txt":       "fixture",
+		"testdata/deep/nested.txt": "fixture",
+		"pkg/lib.go":               "package pkg",
+		"pkg/testdata/keep.txt":    "only the top-level testdata is excluded",
+		"web/app.min.js":           "minified",
+	})
+	cfg := testConfig()
+	cfg.Exclude = []string{"testdata/**", "*.min.js"}
+
+	result, err := New(&fakeCounter{}, nil, cfg).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	got := strings.Join(countedFiles(t, root, result), ",")
+	if want := "main.go,pkg/lib.go,pkg/testdata/keep.txt"; got != want {
+		t.Errorf("counted %s, want %s", got, want)
+	}
+}
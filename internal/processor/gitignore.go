@@ -7,35 +7,107 @@ import (
 	"strings"
 )
 
+// gitignoreRule is a single pattern from a .gitignore file, scoped to the directory containing it
+type gitignoreRule struct {
+	baseDir  string // Directory containing the .gitignore the rule came from
+	pattern  string
+	negate   bool // "!pattern" re-includes paths excluded by earlier rules
+	dirOnly  bool // "pattern/" only matches directories
+	anchored bool // Patterns containing a slash match relative to baseDir instead of any basename
+}
+
 // loadGitignore loads and parses .gitignore patterns from the specified directory.
 // It returns an empty list if no .gitignore file exists.
-func loadGitignore(dirPath string) ([]string, error) {
+func loadGitignore(dirPath string) ([]gitignoreRule, error) {
 	gitignorePath := filepath.Join(dirPath, ".gitignore")
 	file, err := os.Open(gitignorePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []string{}, nil
+			return []gitignoreRule{}, nil
 		}
 		return nil, err
 	}
 	defer file.Close()
 
-	var patterns []string
+	var rules []gitignoreRule
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		patterns = append(patterns, line)
+
+		rule := gitignoreRule{baseDir: dirPath}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			// Escaped leading "!" or "#" is part of the file name
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+// gitignoreStack accumulates rules from nested .gitignore files as a directory walk descends
+type gitignoreStack struct {
+	rulesByDir map[string][]gitignoreRule
+}
+
+// newGitignoreStack creates an empty rule stack
+func newGitignoreStack() *gitignoreStack {
+	return &gitignoreStack{rulesByDir: make(map[string][]gitignoreRule)}
+}
+
+// enter loads the .gitignore of dir (if any) on top of the rules inherited from its parent.
+// Directories must be entered before their contents, as filepath.Walk does.
+func (s *gitignoreStack) enter(dir string) error {
+	dir = filepath.Clean(dir)
+	inherited := s.rulesByDir[filepath.Dir(dir)]
+
+	rules, err := loadGitignore(dir)
+	if err != nil {
+		// Keep the inherited rules so a broken file doesn't disable ignoring below it
+		s.rulesByDir[dir] = inherited
+		return err
 	}
 
-	return patterns, scanner.Err()
+	if len(rules) == 0 {
+		s.rulesByDir[dir] = inherited
+		return nil
+	}
+
+	// Copy so sibling directories don't share an appended backing array
+	combined := make([]gitignoreRule, 0, len(inherited)+len(rules))
+	combined = append(combined, inherited...)
+	s.rulesByDir[dir] = append(combined, rules...)
+	return nil
+}
+
+// rulesFor returns the rules that apply to entries of dir
+func (s *gitignoreStack) rulesFor(dir string) []gitignoreRule {
+	return s.rulesByDir[filepath.Clean(dir)]
 }
 
-// shouldIgnore checks if a file or directory should be ignored based on .gitignore patterns.
-// It always ignores the .git directory and matches against provided gitignore patterns.
-func shouldIgnore(path, basePath string, patterns []string, isDir bool) bool {
+// shouldIgnore checks if a file or directory should be ignored based on .gitignore rules.
+// It always ignores the .git directory. Rules are applied in order and the last match wins,
+// so rules from deeper .gitignore files override shallower ones.
+func shouldIgnore(path, basePath string, rules []gitignoreRule, isDir bool) bool {
 	relPath, err := filepath.Rel(basePath, path)
 	if err != nil {
 		return false
@@ -46,29 +118,32 @@ func shouldIgnore(path, basePath string, patterns []string, isDir bool) bool {
 		return true
 	}
 
-	// Check gitignore patterns
-	for _, pattern := range patterns {
-		matched, err := filepath.Match(pattern, filepath.Base(relPath))
-		if err != nil {
-			// Skip malformed patterns
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
 			continue
 		}
-		if matched {
-			return true
+		if rule.matches(path) {
+			ignored = !rule.negate
 		}
+	}
 
-		// Check directory patterns
-		if isDir {
-			matched, err = filepath.Match(pattern, filepath.Base(relPath)+"/")
-			if err != nil {
-				// Skip malformed patterns
-				continue
-			}
-			if matched {
-				return true
-			}
-		}
+	return ignored
+}
+
+// matches reports whether path matches the rule, relative to the rule's .gitignore location
+func (r gitignoreRule) matches(path string) bool {
+	relPath, err := filepath.Rel(r.baseDir, path)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return false
+	}
+
+	target := filepath.Base(relPath)
+	if r.anchored {
+		target = filepath.ToSlash(relPath)
 	}
 
-	return false
+	matched, err := filepath.Match(r.pattern, target)
+	// Malformed patterns never match
+	return err == nil && matched
 }
@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessPathNestedGitignore(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		".gitignore":                 "*.log\nbuild/\n",
+		"main.go":                    "package main",
+		"debug.log":                  "ignored by root",
+		"build/out.txt":              "ignored directory",
+		"vendor/somepkg/.gitignore":  "generated/\n*.tmp\n!keep.log\n",
+		"vendor/somepkg/lib.go":      "package somepkg",
+		"vendor/somepkg/cache.tmp":   "ignored by nested",
+		"vendor/somepkg/keep.log":    "re-included by nested",
+		"vendor/somepkg/trace.log":   "still ignored by root",
+		"vendor/somepkg/generated/x": "ignored directory",
+		"vendor/other/cache.tmp":     "nested rules don't apply to siblings",
+		"docs/.gitignore":            "/guide.md\n",
+		"docs/guide.md":              "anchored to docs",
+		"docs/sub/guide.md":          "not anchored here",
+	})
+
+	result, err := New(&fakeCounter{}, nil, testConfig()).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+
+	want := []string{
+		".gitignore",
+		"docs/.gitignore",
+		"docs/sub/guide.md",
+		"main.go",
+		"vendor/other/cache.tmp",
+		"vendor/somepkg/.gitignore",
+		"vendor/somepkg/keep.log",
+		"vendor/somepkg/lib.go",
+	}
+	got := countedFiles(t, root, result)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("counted %v, want %v", got, want)
+	}
+}
+
+func TestShouldIgnore(t *testing.T) {
+	tests := []struct {
+		name      string
+		gitignore string
+		path      string
+		isDir     bool
+		want      bool
+	}{
+		{"basename anywhere", "*.log", "a/b/debug.log", false, true},
+		{"no match", "*.log", "main.go", false, false},
+		{"negation re-includes", "*.log\n!important.log", "important.log", false, false},
+		{"negation only affects its match", "*.log\n!important.log", "other.log", false, true},
+		{"later rule wins", "!important.log\n*.log", "important.log", false, true},
+		{"negation without earlier match", "!keep.txt", "keep.txt", false, false},
+		{"anchored at base", "/build", "build", true, true},
+		{"anchored not nested", "/build", "src/build", true, false},
+		{"path pattern anchored", "docs/*.md", "docs/a.md", false, true},
+		{"path pattern not nested", "docs/*.md", "x/docs/a.md", false, false},
+		{"directory-only matches directory", "dist/", "dist", true, true},
+		{"directory-only skips file", "dist/", "dist", false, false},
+		{"directory-only nested", "dist/", "pkg/dist", true, true},
+		{"anchored directory-only", "/dist/", "pkg/dist", true, false},
+		{"negated directory", "*\n!src/", "src", true, false},
+		{"escaped bang is literal", `\!notes.txt`, "!notes.txt", false, true},
+		{"escaped hash is literal", `\#tmp`, "#tmp", false, true},
+		{"comment ignored", "# *.go", "main.go", false, false},
+		{".git always ignored", "", ".git", true, true},
+		{".git contents always ignored", "", ".git/config", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			if err := os.WriteFile(filepath.Join(base, ".gitignore"), []byte(tt.gitignore+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := loadGitignore(base)
+			if err != nil {
+				t.Fatalf("loadGitignore: %v", err)
+			}
+			path := filepath.Join(base, filepath.FromSlash(tt.path))
+			if got := shouldIgnore(path, base, rules, tt.isDir); got != tt.want {
+				t.Errorf("shouldIgnore(%q, isDir=%v) with %q = %v, want %v", tt.path, tt.isDir, tt.gitignore, got, tt.want)
+			}
+		})
+	}
+}
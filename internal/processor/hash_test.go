@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sha256sum returns the hex SHA-256 of the file at path, from the sha256sum tool when it is
+// installed and crypto/sha256 otherwise
+func sha256sum(t *testing.T, path string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sha256sum"); err == nil {
+		out, err := exec.Command("sha256sum", path).Output()
+		if err != nil {
+			t.Fatalf("sha256sum: %v", err)
+		}
+		return strings.Fields(string(out))[0]
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestEmitHashCoversRawContent(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"plain.txt": "one two three four five",
+		"a.html":    "<html><body><p>Same text</p></body></html>",
+		"b.html":    "<html><body><div class=\"x\">Same text</div></body></html>",
+	})
+	cfg := testConfig()
+	cfg.EmitHash = true
+	cfg.StripHTML = true
+	cfg.MaxTokens = 2
+
+	result, err := New(&fakeCounter{}, nil, cfg).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+
+	hashes := make(map[string]string)
+	for _, child := range result.Children {
+		if child.Error != nil {
+			t.Fatalf("%s: %v", child.Path, child.Error)
+		}
+		hashes[filepath.Base(child.Path)] = child.Hash
+		if want := sha256sum(t, child.Path); child.Hash != want {
+			t.Errorf("%s hash = %s, want sha256sum %s", child.Path, child.Hash, want)
+		}
+	}
+	if len(hashes) != 3 {
+		t.Fatalf("hashed %d files, want 3", len(hashes))
+	}
+	if hashes["a.html"] == hashes["b.html"] {
+		t.Error("files that differ only in markup have the same hash")
+	}
+}
+
+func TestEmitHashStdinText(t *testing.T) {
+	cfg := testConfig()
+	cfg.EmitHash = true
+	cfg.MaxTokens = 1
+	text := "one two three"
+
+	results, err := New(&fakeCounter{}, nil, cfg).ProcessTexts([]string{text})
+	if err != nil {
+		t.Fatalf("ProcessTexts: %v", err)
+	}
+	sum := sha256.Sum256([]byte(text))
+	if want := hex.EncodeToString(sum[:]); results[0].Hash != want {
+		t.Errorf("hash = %s, want %s of the untruncated text", results[0].Hash, want)
+	}
+}
@@ -0,0 +1,148 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+// fakeCounter is an api.TokenCounter that counts whitespace-separated words and records
+// every request it receives
+type fakeCounter struct {
+	mu       sync.Mutex
+	requests []string    // Content of each counting request
+	models   []string    // Model of each counting request
+	err      error       // Returned by counting requests when set
+	failOn   string      // When set, only requests whose content contains it return err
+	onCount  func(n int) // Called with the number of requests so far, under the lock
+}
+
+func (f *fakeCounter) record(content, model string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, content)
+	f.models = append(f.models, model)
+	if f.onCount != nil {
+		f.onCount(len(f.requests))
+	}
+	if f.err != nil && strings.Contains(content, f.failOn) {
+		return 0, f.err
+	}
+	return len(strings.Fields(content)), nil
+}
+
+func (f *fakeCounter) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func (f *fakeCounter) CountTokens(content, model string) (int, error) {
+	return f.record(content, model)
+}
+
+func (f *fakeCounter) CountTokensWithSystem(content, system, model string) (int, error) {
+	tokens, err := f.record(content, model)
+	return tokens + len(strings.Fields(system)), err
+}
+
+func (f *fakeCounter) CountSystemTokens(system, model string) (int, error) {
+	return len(strings.Fields(system)), nil
+}
+
+func (f *fakeCounter) CountPDFTokens(pdf []byte, system, model string) (int, error) {
+	return f.record(string(pdf), model)
+}
+
+func (f *fakeCounter) CountImageTokens(image []byte, mediaType, system, model string) (int, error) {
+	return f.record(string(image), model)
+}
+
+func (f *fakeCounter) ExtractTokensClientSide(content string) ([]api.Token, error) {
+	var tokens []api.Token
+	offset := 0
+	for _, word := range strings.Fields(content) {
+		position := offset + strings.Index(content[offset:], word)
+		tokens = append(tokens, api.Token{Text: word, Position: position, Length: len(word)})
+		offset = position + len(word)
+	}
+	return tokens, nil
+}
+
+// testConfig returns the configuration the count command uses by default
+func testConfig() *config.Config {
+	return &config.Config{
+		Model:       "claude-sonnet-4-5",
+		MaxSize:     2 * 1024 * 1024,
+		Concurrency: 4,
+		MaxDepth:    -1,
+		MaxRetries:  api.DefaultMaxRetries,
+	}
+}
+
+// writeFixture creates files (path relative to root -> content) under a new temp directory
+func writeFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// countedFiles returns the slash-separated paths, relative to root, of the files counted in result
+func countedFiles(t *testing.T, root string, result *Result) []string {
+	t.Helper()
+	var paths []string
+	var walk func(*Result)
+	walk = func(r *Result) {
+		if r.IsDir {
+			for _, child := range r.Children {
+				walk(child)
+			}
+			return
+		}
+		if r.Error != nil || r.SkippedBinary {
+			return
+		}
+		rel, err := filepath.Rel(root, r.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	walk(result)
+	sort.Strings(paths)
+	return paths
+}
+
+// withStdin replaces os.Stdin with content for the rest of the test
+func withStdin(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		f.Close()
+	})
+}
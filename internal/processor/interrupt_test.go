@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/cache"
+)
+
+func TestProcessPathInterrupted(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i < 6; i++ {
+		files[fmt.Sprintf("%d.txt", i)] = "one two"
+	}
+	root := writeFixture(t, files)
+	cacheDir := t.TempDir()
+	c, err := cache.Load(cacheDir)
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+
+	// Cancel as the second file is being counted, as Ctrl+C would
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	counter := &fakeCounter{onCount: func(n int) {
+		if n == 2 {
+			cancel()
+		}
+	}}
+	cfg := testConfig()
+	cfg.Concurrency = 1
+	p := New(counter, c, cfg)
+	p.SetContext(ctx)
+
+	result, err := p.ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if !p.Interrupted() {
+		t.Error("Interrupted() = false after cancellation")
+	}
+	counted := countedFiles(t, root, result)
+	if len(counted) != 2 || result.Tokens != 4 {
+		t.Errorf("partial result = %v with %d tokens, want the 2 files counted before the interrupt", counted, result.Tokens)
+	}
+
+	// The counts gathered so far survive in the saved cache
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded, err := cache.Load(cacheDir)
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+	if got := reloaded.Stats().Entries; got != 2 {
+		t.Errorf("saved cache has %d entries, want 2", got)
+	}
+}
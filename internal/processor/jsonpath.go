@@ -0,0 +1,164 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is a single step in a parsed JSON path
+type jsonPathSegment struct {
+	key      string // Object key (when index is not used)
+	index    int    // Array index (when isIndex is set)
+	isIndex  bool
+	wildcard bool // Matches every element of an array or object
+}
+
+// isJSONFile reports whether the file should be treated as JSON input for --json-path
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// extractJSONPath returns the string values selected by expr, joined by newlines.
+// Supported syntax: $ (root), .key, ["key"], [N] and [*] / .* wildcards. When a selected
+// node is an object or array, all nested string values are collected.
+func extractJSONPath(content []byte, expr string) ([]byte, error) {
+	segments, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	nodes := []interface{}{doc}
+	for _, segment := range segments {
+		nodes = applySegment(nodes, segment)
+	}
+
+	var values []string
+	for _, node := range nodes {
+		values = collectStrings(node, values)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("json path %s matched no string values", expr)
+	}
+
+	return []byte(strings.Join(values, "\n")), nil
+}
+
+// parseJSONPath splits a path expression into segments
+func parseJSONPath(expr string) ([]jsonPathSegment, error) {
+	path := strings.TrimSpace(expr)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid json path %q: must start with $", expr)
+	}
+	path = path[1:]
+
+	var segments []jsonPathSegment
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+			key := path[:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid json path %q: empty key", expr)
+			}
+			path = path[end:]
+			if key == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			} else {
+				segments = append(segments, jsonPathSegment{key: key})
+			}
+
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid json path %q: unclosed bracket", expr)
+			}
+			inner := strings.TrimSpace(path[1:end])
+			path = path[end+1:]
+
+			switch {
+			case inner == "*":
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid json path %q: bad index %q", expr, inner)
+				}
+				segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid json path %q: unexpected %q", expr, path[0])
+		}
+	}
+
+	return segments, nil
+}
+
+// applySegment advances every current node by one path segment, dropping nodes that don't match
+func applySegment(nodes []interface{}, segment jsonPathSegment) []interface{} {
+	next := make([]interface{}, 0, len(nodes))
+
+	for _, node := range nodes {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if segment.wildcard {
+				for _, key := range sortedKeys(v) {
+					next = append(next, v[key])
+				}
+			} else if child, ok := v[segment.key]; ok && !segment.isIndex {
+				next = append(next, child)
+			}
+		case []interface{}:
+			if segment.wildcard {
+				next = append(next, v...)
+			} else if segment.isIndex && segment.index < len(v) {
+				next = append(next, v[segment.index])
+			}
+		}
+	}
+
+	return next
+}
+
+// collectStrings appends all string values found in node, in document order for arrays
+// and sorted key order for objects
+func collectStrings(node interface{}, values []string) []string {
+	switch v := node.(type) {
+	case string:
+		values = append(values, v)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			values = collectStrings(v[key], values)
+		}
+	case []interface{}:
+		for _, item := range v {
+			values = collectStrings(item, values)
+		}
+	}
+	return values
+}
+
+// sortedKeys returns the keys of an object in sorted order for deterministic output
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
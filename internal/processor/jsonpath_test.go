@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	doc := `{
+		"prompt": "Summarize the report",
+		"meta": {"author": "ops", "tags": ["a", "b"]},
+		"messages": [{"role": "user", "content": "first"}, {"role": "assistant", "content": "second"}],
+		"weird key": "spaced",
+		"count": 3
+	}`
+
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{"$.prompt", "Summarize the report", ""},
+		{"$.messages[*].content", "first\nsecond", ""},
+		{"$.messages[1].content", "second", ""},
+		{`$["weird key"]`, "spaced", ""},
+		{"$.meta", "ops\na\nb", ""},
+		{"$.missing", "", "matched no string values"},
+		{"$.count", "", "matched no string values"},
+		{"prompt", "", "must start with $"},
+		{"$.messages[x]", "", "bad index"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := extractJSONPath([]byte(doc), tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractJSONPath: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("extractJSONPath(%s) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessPathJSONPath(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"prompt.json": `{"id": "p-1", "model": "sonnet", "prompt": "Explain the diff in two sentences"}`,
+		"notes.txt":   `$.prompt only applies to JSON files`,
+	})
+	counter := &fakeCounter{}
+	cfg := testConfig()
+	cfg.JSONPath = "$.prompt"
+	p := New(counter, nil, cfg)
+
+	result, err := p.ProcessPath(filepath.Join(root, "prompt.json"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("result error: %v", result.Error)
+	}
+	if sent := counter.requests[0]; sent != "Explain the diff in two sentences" {
+		t.Errorf("counted %q, want only the prompt value", sent)
+	}
+	if result.Tokens != 6 {
+		t.Errorf("tokens = %d, want 6", result.Tokens)
+	}
+
+	// Other files are counted whole
+	text, err := p.ProcessPath(filepath.Join(root, "notes.txt"))
+	if err != nil || text.Error != nil || text.Tokens != 6 {
+		t.Errorf("notes.txt = %+v, %v; want 6 tokens counted whole", text, err)
+	}
+}
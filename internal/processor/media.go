@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/cache"
+)
+
+// mediaTypes maps the extensions of files counted as content blocks to their media types
+var mediaTypes = map[string]string{
+	".pdf":  api.PDFMediaType,
+	".png":  api.PNGMediaType,
+	".jpg":  api.JPEGMediaType,
+	".jpeg": api.JPEGMediaType,
+}
+
+// mediaTypeOf returns the media type of a PDF or image file, or "" for other files
+func mediaTypeOf(path string) string {
+	return mediaTypes[strings.ToLower(filepath.Ext(path))]
+}
+
+// isImageMediaType reports whether mediaType is sent as an image block
+func isImageMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "image/")
+}
+
+// processMedia counts a PDF or image by sending it to the API as a document or image block.
+// Text preprocessing and --max-tokens don't apply, and there are no lines to report.
+func (p *Processor) processMedia(filePath string, info os.FileInfo, mediaType string) *Result {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return &Result{
+			Path:  filePath,
+			Error: fmt.Errorf("failed to read file: %w", err),
+		}
+	}
+	if detected := http.DetectContentType(content); detected != mediaType {
+		return &Result{
+			Path:  filePath,
+			Error: fmt.Errorf("content is %s, not %s", detected, mediaType),
+		}
+	}
+
+	tokens, cached, err := p.cachedCount(filePath, info, content, func() (int, error) {
+		return p.countMediaTokens(content, mediaType)
+	})
+	if err != nil {
+		return &Result{
+			Path:  filePath,
+			Error: err,
+		}
+	}
+
+	result := &Result{
+		Path:         filePath,
+		Tokens:       tokens,
+		Cached:       cached,
+		SystemTokens: p.systemTokens,
+		Image:        isImageMediaType(mediaType),
+	}
+	if p.config.EmitHash {
+		result.Hash = cache.ComputeHash(content)
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// isMIMEFile reports whether the file should be parsed as a MIME email message
+func isMIMEFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".eml")
+}
+
+// extractMIMEText parses a MIME email message and returns only its text/plain and text/html
+// body parts, skipping headers and attachments.
+func extractMIMEText(content []byte) ([]byte, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	var parts [][]byte
+	header := textproto.MIMEHeader(msg.Header)
+	if err := collectTextParts(header, msg.Body, &parts); err != nil {
+		return nil, err
+	}
+
+	return bytes.Join(parts, []byte("\n\n")), nil
+}
+
+// collectTextParts walks a MIME entity, appending decoded text bodies and recursing into multiparts
+func collectTextParts(header textproto.MIMEHeader, body io.Reader, parts *[][]byte) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// Messages without a valid Content-Type default to plain text (RFC 2045)
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read MIME part: %w", err)
+			}
+			if err := collectTextParts(part.Header, part, parts); err != nil {
+				return err
+			}
+		}
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return nil
+	}
+
+	// Text files sent as attachments are not part of the message body
+	if disposition, _, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && disposition == "attachment" {
+		return nil
+	}
+
+	decoded, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("failed to decode MIME part: %w", err)
+	}
+
+	*parts = append(*parts, bytes.TrimSpace(decoded))
+	return nil
+}
+
+// decodeTransferEncoding wraps body with a decoder for the given Content-Transfer-Encoding
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// The base64 decoder skips the line breaks used to wrap encoded bodies
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// multipartEmail has a plain and HTML body plus a text and a binary attachment
+const multipartEmail = "From: support@example.com\r\n" +
+	"To: customer@example.com\r\n" +
+	"Subject: Your ticket\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Hello plain body\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"<p>Hello=20html</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=log.txt\r\n" +
+	"Content-Disposition: attachment; filename=log.txt\r\n" +
+	"\r\n" +
+	"attached log lines that must not count\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=invoice.pdf\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQKJcOkw7zDtsOfCjIgMCBvYmoKPDwvTGVuZ3RoIDMgMCBSL0ZpbHRlci9GbGF0ZURlY29kZT4+\r\n" +
+	"--outer--\r\n"
+
+func TestProcessPathParseMIME(t *testing.T) {
+	root := writeFixture(t, map[string]string{"ticket.eml": multipartEmail})
+	path := filepath.Join(root, "ticket.eml")
+
+	counter := &fakeCounter{}
+	cfg := testConfig()
+	cfg.ParseMIME = true
+	result, err := New(counter, nil, cfg).ProcessPath(path)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("result error: %v", result.Error)
+	}
+
+	sent := counter.requests[0]
+	if want := "Hello plain body\n\n<p>Hello html</p>"; sent != want {
+		t.Errorf("counted %q, want only the body parts %q", sent, want)
+	}
+	if result.Tokens != 5 {
+		t.Errorf("tokens = %d, want 5", result.Tokens)
+	}
+
+	// Without --parse-mime the raw message is counted
+	counter = &fakeCounter{}
+	if _, err := New(counter, nil, testConfig()).ProcessPath(path); err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if sent := counter.requests[0]; !strings.Contains(sent, "Subject: Your ticket") {
+		t.Errorf("raw count sent %q, want the whole message", sent)
+	}
+}
+
+func TestExtractMIMETextPlainMessage(t *testing.T) {
+	// A message without a Content-Type is plain text
+	got, err := extractMIMEText([]byte("Subject: hi\r\n\r\nJust text.\r\n"))
+	if err != nil {
+		t.Fatalf("extractMIMEText: %v", err)
+	}
+	if string(got) != "Just text." {
+		t.Errorf("extractMIMEText = %q, want %q", got, "Just text.")
+	}
+}
@@ -11,12 +11,20 @@ type Result struct {
 	Children         []*Result
 	LineCount        int     // Number of lines in the file
 	AvgTokensPerLine float64 // Average tokens per line
+	Hash             string  // SHA-256 of the raw file content, before preprocessing (only set with --emit-hash)
+	SystemTokens     int     // Tokens from the --system prompt included in Tokens
+	SkippedBinary    bool    // File was not counted because it looks like binary data
+	Truncated        bool    // Content was cut to its first --max-tokens tokens before counting
+	Chunks           int     // Pieces a file over --max-size was counted in with --chunk-size (0 = counted whole)
+	Image            bool    // File was counted as an image content block
+	Secrets          int     // Credentials found in the counted content (only scanned with --strict)
+	Content          string  // Text that was counted, after extraction and preprocessing (only kept with SetKeepContent)
 }
 
 // CountFiles recursively counts the number of successfully processed files in this result
 func (r *Result) CountFiles() int {
 	if !r.IsDir {
-		if r.Error == nil {
+		if r.Error == nil && !r.SkippedBinary {
 			return 1
 		}
 		return 0
@@ -28,3 +36,19 @@ func (r *Result) CountFiles() int {
 	}
 	return count
 }
+
+// CountSkippedBinary recursively counts the files skipped because they look like binary data
+func (r *Result) CountSkippedBinary() int {
+	if !r.IsDir {
+		if r.SkippedBinary {
+			return 1
+		}
+		return 0
+	}
+
+	count := 0
+	for _, child := range r.Children {
+		count += child.CountSkippedBinary()
+	}
+	return count
+}
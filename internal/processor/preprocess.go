@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"bytes"
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+var (
+	// htmlHiddenBlockPattern matches elements whose content is never rendered as text
+	htmlHiddenBlockPattern = regexp.MustCompile(`(?is)<(script|style|head)\b[^>]*>.*?</(script|style|head)\s*>`)
+	// htmlCommentPattern matches HTML comments
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// htmlBlockTagPattern matches tags of block-level elements, which separate their text from
+	// the surrounding text when rendered
+	htmlBlockTagPattern = regexp.MustCompile(`(?i)</?(address|article|aside|blockquote|br|dd|div|dl|dt|figcaption|figure|footer|h[1-6]|header|hr|li|main|nav|ol|p|pre|section|table|td|th|tr|ul)\b[^>]*>`)
+	// htmlTagPattern matches any remaining opening, closing or self-closing tag
+	htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+	// blankLinesPattern matches runs of blank lines left behind by removed markup
+	blankLinesPattern = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+)
+
+// contentTypeOf returns the content type used for preprocessing: the explicit --content-type
+// when set, otherwise one inferred from the file extension.
+func contentTypeOf(path string, cfg *config.Config) string {
+	if cfg.ContentType != "" {
+		return cfg.ContentType
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return config.ContentTypeHTML
+	case ".md", ".markdown":
+		return config.ContentTypeMarkdown
+	default:
+		return config.ContentTypeText
+	}
+}
+
+// preprocess applies the enabled --strip-html / --strip-frontmatter options for the content type
+func preprocess(content []byte, contentType string, cfg *config.Config) []byte {
+	switch contentType {
+	case config.ContentTypeHTML:
+		if cfg.StripHTML {
+			return stripHTML(content)
+		}
+	case config.ContentTypeMarkdown:
+		if cfg.StripFrontmatter {
+			return stripFrontmatter(content)
+		}
+	}
+	return content
+}
+
+// stripHTML removes markup from an HTML document, keeping only its visible text
+func stripHTML(content []byte) []byte {
+	text := htmlHiddenBlockPattern.ReplaceAll(content, nil)
+	text = htmlCommentPattern.ReplaceAll(text, nil)
+	text = htmlBlockTagPattern.ReplaceAll(text, []byte("\n"))
+	text = htmlTagPattern.ReplaceAll(text, nil)
+	text = []byte(html.UnescapeString(string(text)))
+	text = blankLinesPattern.ReplaceAll(text, []byte("\n\n"))
+	return bytes.TrimSpace(text)
+}
+
+// stripFrontmatter removes a leading YAML front matter block delimited by "---" lines
+func stripFrontmatter(content []byte) []byte {
+	normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return content
+	}
+
+	rest := normalized[len("---\n"):]
+	for offset := 0; offset < len(rest); {
+		end := bytes.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end != -1 {
+			line = rest[offset : offset+end]
+		}
+
+		if strings.TrimRight(string(line), " \t") == "---" {
+			if end == -1 {
+				return nil
+			}
+			return bytes.TrimLeft(rest[offset+end+1:], "\n")
+		}
+
+		if end == -1 {
+			break
+		}
+		offset += end + 1
+	}
+
+	// An unterminated block is not front matter
+	return content
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/config"
+)
+
+const htmlPage = `<html><head><style>p { color: red; }</style><script>track()</script></head>
+<body><!-- banner --><h1>Release notes</h1><p>Version <b>two</b> is out.</p></body></html>`
+
+func TestProcessStdinContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		stripHTML   bool
+		want        string // Content sent for counting
+	}{
+		{"html stripped", config.ContentTypeHTML, true, "Release notes Version two is out."},
+		{"no content type leaves stdin as text", "", true, htmlPage},
+		{"html without --strip-html", config.ContentTypeHTML, false, htmlPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, htmlPage)
+			counter := &fakeCounter{}
+			cfg := testConfig()
+			cfg.ContentType = tt.contentType
+			cfg.StripHTML = tt.stripHTML
+
+			result, err := New(counter, nil, cfg).ProcessPath("-")
+			if err != nil {
+				t.Fatalf("ProcessPath: %v", err)
+			}
+			if result.Error != nil {
+				t.Fatalf("result error: %v", result.Error)
+			}
+			if got := normalizeSpace(counter.requests[0]); got != normalizeSpace(tt.want) {
+				t.Errorf("counted %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripFrontmatterFromStdin(t *testing.T) {
+	withStdin(t, "---\ntitle: Notes\ntags: [a, b]\n---\n# Heading\nBody text.\n")
+	counter := &fakeCounter{}
+	cfg := testConfig()
+	cfg.ContentType = config.ContentTypeMarkdown
+	cfg.StripFrontmatter = true
+
+	if _, err := New(counter, nil, cfg).ProcessPath("-"); err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if got := normalizeSpace(counter.requests[0]); got != "# Heading Body text." {
+		t.Errorf("counted %q, want the markdown without front matter", got)
+	}
+}
+
+// normalizeSpace collapses whitespace runs so comparisons ignore the layout preprocessing leaves
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -1,37 +1,177 @@
 package processor
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/iota-uz/cc-token/internal/analyzer"
 	"github.com/iota-uz/cc-token/internal/api"
 	"github.com/iota-uz/cc-token/internal/cache"
 	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
 	"github.com/iota-uz/cc-token/internal/utils"
 )
 
+const (
+	// maxAdaptiveConcurrency caps the in-flight limit reachable with --adaptive-concurrency
+	maxAdaptiveConcurrency = 64
+	// interruptGracePeriod is how long in-flight requests may finish after an interrupt
+	interruptGracePeriod = 5 * time.Second
+	// textName is the path reported for --text input
+	textName = "<text>"
+)
+
 // Processor handles file and directory processing for token counting
 type Processor struct {
-	apiClient *api.Client
+	apiClient api.TokenCounter
 	cache     *cache.Cache
 	config    *config.Config
+	limiter   *api.AdaptiveLimiter // Nil unless adaptive concurrency is enabled
+	pricer    *pricing.Pricer      // Prices for the --max-cost budget guard and progress costs; may be nil
+	progress  io.Writer            // Nil unless directory scan progress is reported
+	ctx       context.Context      // Cancelled to stop starting new work (e.g. on SIGINT)
+
+	keepContent bool // Keep each file's counted text in its Result, for analysis
+
+	systemOnce   sync.Once
+	systemTokens int // Tokens added by the --system prompt to each request
+	systemErr    error
+
+	rateLimitMu sync.Mutex
+	rateLimit   *api.RateLimit // Last rate limit reported by the API, nil until one is seen
+}
+
+// walkedFile is a file found while walking a directory, pending processing
+type walkedFile struct {
+	path string
+	info os.FileInfo
 }
 
 // New creates a new Processor instance
-func New(apiClient *api.Client, c *cache.Cache, cfg *config.Config) *Processor {
-	return &Processor{
+func New(apiClient api.TokenCounter, c *cache.Cache, cfg *config.Config) *Processor {
+	p := &Processor{
 		apiClient: apiClient,
 		cache:     c,
 		config:    cfg,
+		ctx:       context.Background(),
+	}
+	if cfg.AdaptiveConcurrency {
+		p.limiter = api.NewAdaptiveLimiter(cfg.Concurrency, maxAdaptiveConcurrency)
+	}
+	if observable, ok := apiClient.(api.RateLimitObservable); ok {
+		observable.SetRateLimitObserver(p.observeRateLimit)
+	}
+	return p
+}
+
+// observeRateLimit records the rate limit reported by an API response and, with adaptive
+// concurrency, lets the limiter back off before requests start failing
+func (p *Processor) observeRateLimit(rateLimit api.RateLimit) {
+	p.rateLimitMu.Lock()
+	p.rateLimit = &rateLimit
+	p.rateLimitMu.Unlock()
+
+	if p.limiter != nil {
+		p.limiter.ObserveRateLimit(rateLimit)
+	}
+}
+
+// lastRateLimit returns the most recent rate limit reported by the API
+func (p *Processor) lastRateLimit() (api.RateLimit, bool) {
+	p.rateLimitMu.Lock()
+	defer p.rateLimitMu.Unlock()
+	if p.rateLimit == nil {
+		return api.RateLimit{}, false
+	}
+	return *p.rateLimit, true
+}
+
+// SetContext sets a context whose cancellation stops the processor from starting new files.
+// Files already being counted may finish; the rest are omitted from the results.
+func (p *Processor) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// SetKeepContent makes file results carry the text that was counted, after extraction and
+// preprocessing, so it can be analyzed without reading the file again
+func (p *Processor) SetKeepContent(keep bool) {
+	p.keepContent = keep
+}
+
+// Interrupted reports whether processing was cut short by the context being cancelled
+func (p *Processor) Interrupted() bool {
+	return p.ctx.Err() != nil
+}
+
+// countTokens counts tokens via the API. With adaptive concurrency enabled, requests go through
+// the AIMD limiter, which retries rate-limited requests (see limitedCount).
+// The --system prompt, if any, is sent with every request and included in the count.
+func (p *Processor) countTokens(content string) (int, error) {
+	return p.limitedCount(func() (int, error) {
+		return p.apiClient.CountTokensWithSystem(content, p.config.SystemPrompt, p.config.Model)
+	})
+}
+
+// countMediaTokens counts a PDF or image via the API, like countTokens
+func (p *Processor) countMediaTokens(content []byte, mediaType string) (int, error) {
+	return p.limitedCount(func() (int, error) {
+		if isImageMediaType(mediaType) {
+			return p.apiClient.CountImageTokens(content, mediaType, p.config.SystemPrompt, p.config.Model)
+		}
+		return p.apiClient.CountPDFTokens(content, p.config.SystemPrompt, p.config.Model)
+	})
+}
+
+// limitedCount runs an API count, through the adaptive limiter when it is enabled. The limiter
+// has to see every 429 to back off, so in adaptive mode rate-limited requests are retried here,
+// up to --max-retries times, and the client is set not to retry them itself.
+func (p *Processor) limitedCount(count func() (int, error)) (int, error) {
+	if p.limiter == nil {
+		return count()
+	}
+
+	for attempt := 0; ; attempt++ {
+		p.limiter.Acquire()
+		tokens, err := count()
+		p.limiter.Release(err)
+
+		if !api.IsRateLimited(err) || attempt >= p.config.MaxRetries {
+			return tokens, err
+		}
+		time.Sleep(api.RetryDelay(attempt, err))
 	}
 }
 
+// systemPromptTokens returns the per-request overhead of the --system prompt, measured once per run
+func (p *Processor) systemPromptTokens() (int, error) {
+	if p.config.SystemPrompt == "" {
+		return 0, nil
+	}
+
+	p.systemOnce.Do(func() {
+		p.systemTokens, p.systemErr = p.apiClient.CountSystemTokens(p.config.SystemPrompt, p.config.Model)
+		if p.systemErr != nil {
+			p.systemErr = fmt.Errorf("failed to count system prompt tokens: %w", p.systemErr)
+		}
+	})
+	return p.systemTokens, p.systemErr
+}
+
 // ProcessPath handles processing of a single path, which can be a file, directory, or stdin ("-").
 // It dispatches to the appropriate handler based on the path type.
 func (p *Processor) ProcessPath(path string) (*Result, error) {
+	// Measure the system prompt up front so a bad prompt fails once instead of per file
+	if _, err := p.systemPromptTokens(); err != nil {
+		return nil, err
+	}
+
 	// Handle stdin
 	if path == "-" {
 		return p.processStdin()
@@ -57,6 +197,108 @@ func (p *Processor) ProcessPath(path string) (*Result, error) {
 // processStdin reads content from stdin, validates the size, and counts tokens.
 // It returns an error if the content exceeds the maximum file size.
 func (p *Processor) processStdin() (*Result, error) {
+	content, err := p.readStdin()
+	if err != nil {
+		return nil, err
+	}
+	return p.countDocument(p.stdinName(), content)
+}
+
+// stdinName returns the path reported for stdin input
+func (p *Processor) stdinName() string {
+	if p.config.StdinName == "" {
+		return "<stdin>"
+	}
+	return p.config.StdinName
+}
+
+// ProcessStdinDocuments splits stdin on delimiter and counts each non-empty document separately.
+// Documents are named <stdin>#1, <stdin>#2, ... (or after --stdin-name); a failed document is
+// kept with its error.
+func (p *Processor) ProcessStdinDocuments(delimiter string) ([]*Result, error) {
+	if _, err := p.systemPromptTokens(); err != nil {
+		return nil, err
+	}
+
+	content, err := p.readStdin()
+	if err != nil {
+		return nil, err
+	}
+
+	var documents [][]byte
+	for _, document := range bytes.Split(content, []byte(unescapeDelimiter(delimiter))) {
+		if len(bytes.TrimSpace(document)) > 0 {
+			documents = append(documents, document)
+		}
+	}
+
+	results := make([]*Result, len(documents))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.config.Concurrency)
+
+	for i, document := range documents {
+		wg.Add(1)
+		go func(i int, document []byte) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			name := fmt.Sprintf("%s#%d", p.stdinName(), i+1)
+			result, err := p.countDocument(name, document)
+			if err != nil {
+				result = &Result{Path: name, Error: err}
+			}
+			results[i] = result
+		}(i, document)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// ProcessTexts counts literal strings given on the command line, bypassing the cache. A single
+// text is named <text>, several are named <text>#1, <text>#2, ...; a failed text is kept with
+// its error.
+func (p *Processor) ProcessTexts(texts []string) ([]*Result, error) {
+	if _, err := p.systemPromptTokens(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, len(texts))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.config.Concurrency)
+
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			name := textName
+			if len(texts) > 1 {
+				name = fmt.Sprintf("%s#%d", textName, i+1)
+			}
+			result, err := p.countDocument(name, []byte(text))
+			if err != nil {
+				result = &Result{Path: name, Error: err}
+			}
+			results[i] = result
+		}(i, text)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// unescapeDelimiter interprets \0, \n, \r, \t and \\ in a delimiter given on the command line,
+// so separators such as NUL bytes can be passed as arguments
+func unescapeDelimiter(delimiter string) string {
+	return strings.NewReplacer(`\\`, `\`, `\0`, "\x00", `\n`, "\n", `\r`, "\r", `\t`, "\t").Replace(delimiter)
+}
+
+// readStdin reads all of stdin, enforcing the maximum file size
+func (p *Processor) readStdin() ([]byte, error) {
 	content, err := io.ReadAll(os.Stdin)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read from stdin: %w", err)
@@ -66,8 +308,33 @@ func (p *Processor) processStdin() (*Result, error) {
 	if int64(len(content)) > p.config.MaxSize {
 		return nil, fmt.Errorf("stdin content too large (%d bytes, max: %d bytes)", len(content), p.config.MaxSize)
 	}
+	return content, nil
+}
+
+// countDocument preprocesses and counts a document read from stdin or given with --text
+func (p *Processor) countDocument(name string, content []byte) (*Result, error) {
+	raw := content
+	var err error
+
+	// Stdin has no extension, so an explicit --json-path always applies
+	if p.config.JSONPath != "" {
+		content, err = extractJSONPath(content, p.config.JSONPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Stdin has no extension either, so preprocessing only applies with an explicit --content-type
+	if p.config.ContentType != "" {
+		content = preprocess(content, p.config.ContentType, p.config)
+	}
 
-	tokens, err := p.apiClient.CountTokens(string(content), p.config.Model)
+	content, truncated, err := p.truncateToTokens(content, p.config.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := p.countTokens(string(content))
 	if err != nil {
 		return nil, err
 	}
@@ -75,43 +342,104 @@ func (p *Processor) processStdin() (*Result, error) {
 	// Calculate line count and average tokens per line
 	lineCount, avgTokensPerLine := utils.CalculateLineMetrics(string(content), tokens)
 
-	return &Result{
-		Path:             "<stdin>",
+	result := &Result{
+		Path:             name,
 		Tokens:           tokens,
 		Cached:           false,
 		LineCount:        lineCount,
 		AvgTokensPerLine: avgTokensPerLine,
-	}, nil
+		SystemTokens:     p.systemTokens,
+		Truncated:        truncated,
+		Secrets:          p.countSecrets(content),
+	}
+	if p.config.EmitHash {
+		result.Hash = cache.ComputeHash(raw)
+	}
+	return result, nil
 }
 
 // processDirectory recursively processes all files in a directory, respecting .gitignore patterns
 // and configured filters. It uses goroutines for parallel processing with concurrency control.
 func (p *Processor) processDirectory(dirPath string) (*Result, error) {
-	// Load gitignore patterns
-	gitignorePatterns, err := loadGitignore(dirPath)
-	if err != nil && p.config.Verbose {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to load .gitignore: %v\n", err)
-	}
+	// Accumulate .gitignore rules as the walk descends into subdirectories
+	gitignores := newGitignoreStack()
 
 	// Collect all files
-	var files []struct {
-		path string
-		info os.FileInfo
-	}
+	var files []walkedFile
+
+	// Real paths of the directories entered, so --follow-symlinks can't loop
+	visited := make(map[string]bool)
 
-	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+	var visit filepath.WalkFunc
+	visit = func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Symlinks are described by their target. Linked directories below the root are only
+		// entered with --follow-symlinks, and broken links are skipped. A linked root was named
+		// on the command line, so it is always walked.
+		isLink := info.Mode()&os.ModeSymlink != 0
+		if isLink {
+			target, err := os.Stat(path)
+			if err != nil {
+				if p.config.Verbose {
+					fmt.Fprintf(os.Stderr, "Warning: Skipping broken symlink %s\n", path)
+				}
+				return nil
+			}
+			if target.IsDir() && !p.config.FollowSymlinks && path != dirPath {
+				return nil
+			}
+			info = target
+		}
+
+		relPath, err := filepath.Rel(dirPath, path)
 		if err != nil {
 			return err
 		}
 
 		if info.IsDir() {
-			if shouldIgnore(path, dirPath, gitignorePatterns, true) {
-				return filepath.SkipDir
+			// Walk treats SkipDir from a non-directory entry as "skip the rest of the parent"
+			skipDir := filepath.SkipDir
+			if isLink {
+				skipDir = nil
+			}
+
+			if path != dirPath && p.config.MaxDepth >= 0 && pathDepth(relPath) > p.config.MaxDepth {
+				return skipDir
+			}
+			if path != dirPath && (shouldIgnore(path, dirPath, gitignores.rulesFor(filepath.Dir(path)), true) || isExcluded(relPath, p.config.Exclude)) {
+				return skipDir
+			}
+			if p.config.FollowSymlinks {
+				if realPath, err := filepath.EvalSymlinks(path); err == nil {
+					if visited[realPath] {
+						return skipDir
+					}
+					visited[realPath] = true
+				}
+			}
+			if err := gitignores.enter(path); err != nil && p.config.Verbose {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to load %s: %v\n", filepath.Join(path, ".gitignore"), err)
+			}
+
+			// Walk doesn't descend into linked directories, so walk their entries here
+			if isLink {
+				entries, err := os.ReadDir(path)
+				if err != nil {
+					return err
+				}
+				for _, entry := range entries {
+					if err := filepath.Walk(filepath.Join(path, entry.Name()), visit); err != nil {
+						return err
+					}
+				}
 			}
 			return nil
 		}
 
-		if shouldIgnore(path, dirPath, gitignorePatterns, false) {
+		if shouldIgnore(path, dirPath, gitignores.rulesFor(filepath.Dir(path)), false) || isExcluded(relPath, p.config.Exclude) {
 			return nil
 		}
 
@@ -119,12 +447,11 @@ func (p *Processor) processDirectory(dirPath string) (*Result, error) {
 			return nil
 		}
 
-		files = append(files, struct {
-			path string
-			info os.FileInfo
-		}{path, info})
+		files = append(files, walkedFile{path: path, info: info})
 		return nil
-	})
+	}
+
+	err := filepath.Walk(dirPath, visit)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to walk directory: %w", err)
@@ -137,58 +464,146 @@ func (p *Processor) processDirectory(dirPath string) (*Result, error) {
 		}, nil
 	}
 
-	// Process files with concurrency control
+	// Enforce --max-cost before any file is sent to the API
+	if err := p.checkBudget(dirPath, files); err != nil {
+		return nil, err
+	}
+
+	results := p.processFiles(files)
+
+	// Build tree structure
+	tree := buildTree(dirPath, results)
+	return tree, nil
+}
+
+// processFiles counts files concurrently, reporting progress if enabled. Results keep the
+// order of files; after an interrupt, files that were never started are left nil.
+func (p *Processor) processFiles(files []walkedFile) []*Result {
 	results := make([]*Result, len(files))
+	var resultsMu sync.Mutex
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, p.config.Concurrency)
-	errors := make(chan error, len(files))
+	// The adaptive limiter bounds API requests itself, so only cap goroutines at its ceiling
+	workers := p.config.Concurrency
+	if p.limiter != nil {
+		workers = p.limiter.Max()
+	}
+	sem := make(chan struct{}, workers)
+
+	var progress *progressReporter
+	if p.progress != nil {
+		progress = p.newProgressReporter(len(files))
+	}
 
 	for i, file := range files {
 		wg.Add(1)
 		go func(i int, path string, info os.FileInfo) {
 			defer wg.Done()
-			sem <- struct{}{}
+
+			// Once interrupted, files still waiting for a slot are left out of the results
+			select {
+			case sem <- struct{}{}:
+			case <-p.ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
+			if p.ctx.Err() != nil {
+				return
+			}
 
+			// Keep failed files in the tree with their error instead of leaving the slot empty
 			result, err := p.processFile(path, info)
 			if err != nil {
-				errors <- fmt.Errorf("%s: %w", path, err)
-				return
+				result = &Result{Path: path, Error: err}
+			} else if result == nil {
+				result = &Result{Path: path, Error: fmt.Errorf("no result produced")}
 			}
+
+			resultsMu.Lock()
 			results[i] = result
+			resultsMu.Unlock()
+
+			if progress != nil {
+				progress.add(result)
+			}
 		}(i, file.path, file.info)
 	}
 
-	wg.Wait()
-	close(errors)
+	p.waitForWorkers(&wg)
+	if progress != nil {
+		progress.finish()
+	}
 
-	// Check for errors (collect all and return first one)
-	if len(errors) > 0 {
-		// Drain all errors from channel
-		var errList []error
-		for err := range errors {
-			errList = append(errList, err)
-		}
-		// Return first error (could be enhanced to return all)
-		return nil, errList[0]
+	// Snapshot under the lock: after an interrupt, stragglers may still be finishing
+	resultsMu.Lock()
+	results = append([]*Result(nil), results...)
+	resultsMu.Unlock()
+
+	if p.limiter != nil && p.config.Verbose {
+		fmt.Fprintf(os.Stderr, "Adaptive concurrency settled at %d in-flight requests\n", p.limiter.Limit())
+	}
+	if rateLimit, ok := p.lastRateLimit(); ok && p.config.Verbose {
+		fmt.Fprintf(os.Stderr, "API rate limit: %d of %d requests remaining\n", rateLimit.Remaining, rateLimit.Limit)
 	}
 
-	// Build tree structure
-	tree := buildTree(dirPath, results)
-	return tree, nil
+	return results
+}
+
+// waitForWorkers waits for all workers to finish. After an interrupt it waits at most
+// interruptGracePeriod for in-flight requests so partial results can be reported promptly.
+func (p *Processor) waitForWorkers(wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-p.ctx.Done():
+		select {
+		case <-done:
+		case <-time.After(interruptGracePeriod):
+		}
+	}
 }
 
 // processFile processes a single file, checking the cache first and counting tokens via the API
 // if needed. It updates the cache with new results and respects the maximum file size limit.
 func (p *Processor) processFile(filePath string, info os.FileInfo) (*Result, error) {
-	// Check file size
-	if info.Size() > p.config.MaxSize {
+	// Files over the size limit are rejected unless --chunk-size allows counting them in pieces.
+	// PDFs and images can't be split, so they are always rejected.
+	chunked := info.Size() > p.config.MaxSize
+	if chunked && (p.config.ChunkSize == 0 || mediaTypeOf(filePath) != "") {
 		return &Result{
 			Path:  filePath,
 			Error: fmt.Errorf("file too large (%d bytes, max: %d bytes)", info.Size(), p.config.MaxSize),
 		}, nil
 	}
 
+	// PDFs and images are binary, but the API reads them as content blocks. Offline, images
+	// are estimated from their dimensions; PDFs can't be, so they are skipped like other
+	// binary files.
+	if mediaType := mediaTypeOf(filePath); mediaType != "" && (isImageMediaType(mediaType) || !p.config.Offline) {
+		return p.processMedia(filePath, info, mediaType), nil
+	}
+
+	// Sniff the start of the file so binary data isn't read in full or sent to the API
+	if !p.config.IncludeBinary {
+		binary, err := isBinaryFile(filePath)
+		if err != nil {
+			return &Result{
+				Path:  filePath,
+				Error: fmt.Errorf("failed to read file: %w", err),
+			}, nil
+		}
+		if binary {
+			return &Result{
+				Path:          filePath,
+				SkippedBinary: true,
+			}, nil
+		}
+	}
+
 	// Read file
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -198,49 +613,126 @@ func (p *Processor) processFile(filePath string, info os.FileInfo) (*Result, err
 		}, nil
 	}
 
-	// Check cache
-	var tokens int
-	var cached bool
-	var hash string
+	// --emit-hash identifies the file as stored, so it is taken before any extraction or preprocessing
+	raw := content
 
-	if p.cache != nil {
-		hash = cache.ComputeHash(content)
-		if entry, ok := p.cache.Get(filePath); ok {
-			if entry.Hash == hash && entry.Modified.Equal(info.ModTime()) {
-				tokens = entry.Tokens
-				cached = true
-			}
+	// Count only the message body of email files, not headers or attachments
+	if p.config.ParseMIME && isMIMEFile(filePath) {
+		content, err = extractMIMEText(content)
+		if err != nil {
+			return &Result{
+				Path:  filePath,
+				Error: err,
+			}, nil
 		}
 	}
 
-	// Count tokens if not cached
-	if !cached {
-		tokens, err = p.apiClient.CountTokens(string(content), p.config.Model)
+	// Count only the targeted string values of JSON files
+	if p.config.JSONPath != "" && isJSONFile(filePath) {
+		content, err = extractJSONPath(content, p.config.JSONPath)
 		if err != nil {
 			return &Result{
 				Path:  filePath,
 				Error: err,
 			}, nil
 		}
+	}
+
+	// Strip markup or front matter the model doesn't need
+	content = preprocess(content, contentTypeOf(filePath, p.config), p.config)
 
-		// Update cache
-		if p.cache != nil {
-			p.cache.Set(filePath, cache.Entry{
-				Tokens:   tokens,
-				Hash:     hash,
-				Modified: info.ModTime(),
-			})
+	// Sample only the first --max-tokens tokens; the cache hash below covers the truncated content
+	content, truncated, err := p.truncateToTokens(content, p.config.MaxTokens)
+	if err != nil {
+		return &Result{
+			Path:  filePath,
+			Error: err,
+		}, nil
+	}
+
+	var chunks [][]byte
+	if chunked {
+		chunks = splitChunks(content, int(p.config.ChunkSize))
+	}
+
+	tokens, cached, err := p.cachedCount(filePath, info, content, func() (int, error) {
+		if chunked {
+			return p.countChunks(chunks)
 		}
+		return p.countTokens(string(content))
+	})
+	if err != nil {
+		return &Result{
+			Path:  filePath,
+			Error: err,
+		}, nil
+	}
+	if chunked && !cached && p.config.Verbose {
+		fmt.Fprintf(os.Stderr, "Counted %s in %d chunk(s) of up to %d bytes (approximate)\n", filePath, len(chunks), p.config.ChunkSize)
 	}
 
 	// Calculate line count and average tokens per line
 	lineCount, avgTokensPerLine := utils.CalculateLineMetrics(string(content), tokens)
 
-	return &Result{
+	result := &Result{
 		Path:             filePath,
 		Tokens:           tokens,
 		Cached:           cached,
 		LineCount:        lineCount,
 		AvgTokensPerLine: avgTokensPerLine,
-	}, nil
+		SystemTokens:     p.systemTokens,
+		Truncated:        truncated,
+		Chunks:           len(chunks),
+		Secrets:          p.countSecrets(content),
+	}
+	if p.config.EmitHash {
+		result.Hash = cache.ComputeHash(raw)
+	}
+	if p.keepContent {
+		result.Content = string(content)
+	}
+	return result, nil
+}
+
+// countSecrets returns the number of credentials in content when --strict is set, and 0 otherwise
+func (p *Processor) countSecrets(content []byte) int {
+	if !p.config.Strict {
+		return 0
+	}
+	return len(analyzer.FindSecrets(strings.Split(string(content), "\n")))
+}
+
+// cachedCount returns the cached token count for a file whose content and modification time
+// are unchanged, and otherwise calls count and caches the result
+func (p *Processor) cachedCount(filePath string, info os.FileInfo, content []byte, count func() (int, error)) (tokens int, cached bool, err error) {
+	if p.cache == nil {
+		tokens, err = count()
+		return tokens, false, err
+	}
+
+	// Counts including a system prompt are only valid for that prompt, so it is part of the cache key
+	hash := cache.ComputeHash(content)
+	if p.config.SystemPrompt != "" {
+		hash = cache.ComputeHash([]byte(p.config.SystemPrompt + "\x00" + string(content)))
+	}
+
+	// --refresh skips cache hits so every count comes from the API, but still updates the cache below
+	if !p.config.Refresh {
+		if entry, ok := p.cache.Get(p.config.Model, filePath); ok {
+			if entry.Hash == hash && entry.Modified.Equal(info.ModTime()) {
+				return entry.Tokens, true, nil
+			}
+		}
+	}
+
+	tokens, err = count()
+	if err != nil {
+		return 0, false, err
+	}
+	p.cache.Set(p.config.Model, filePath, cache.Entry{
+		Tokens:   tokens,
+		Hash:     hash,
+		Modified: info.ModTime(),
+	})
+	return tokens, false, nil
 }
@@ -0,0 +1,221 @@
+package processor
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/cache"
+)
+
+func TestProcessPathWithFakeCounter(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"a.txt":     "one two three",
+		"sub/b.txt": "four five",
+		"sub/c.md":  "six",
+	})
+	counter := &fakeCounter{}
+	cfg := testConfig()
+
+	result, err := New(counter, nil, cfg).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+
+	if result.Tokens != 6 {
+		t.Errorf("Tokens = %d, want 6", result.Tokens)
+	}
+	if got := result.CountFiles(); got != 3 {
+		t.Errorf("CountFiles() = %d, want 3", got)
+	}
+	if counter.calls() != 3 {
+		t.Errorf("counter called %d times, want 3", counter.calls())
+	}
+	for _, model := range counter.models {
+		if model != cfg.Model {
+			t.Errorf("counted with model %q, want %q", model, cfg.Model)
+		}
+	}
+}
+
+func TestProcessPathCountError(t *testing.T) {
+	root := writeFixture(t, map[string]string{"a.txt": "one two"})
+	counter := &fakeCounter{err: errors.New("boom")}
+
+	result, err := New(counter, nil, testConfig()).ProcessPath(filepath.Join(root, "a.txt"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "boom") {
+		t.Errorf("result error = %v, want the counter's error", result.Error)
+	}
+	if result.CountFiles() != 0 {
+		t.Errorf("CountFiles() = %d, want 0 for a failed file", result.CountFiles())
+	}
+}
+
+func TestProcessPathDirectoryKeepsFailedFiles(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"a.txt":     "one two",
+		"b.txt":     "poison pill here",
+		"sub/c.txt": "three",
+	})
+	counter := &fakeCounter{err: errors.New("boom"), failOn: "poison"}
+
+	result, err := New(counter, nil, testConfig()).ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if len(result.Children) != 3 {
+		t.Fatalf("children = %d, want all 3 files including the failed one", len(result.Children))
+	}
+
+	var failed *Result
+	for _, child := range result.Children {
+		if child.Error != nil {
+			failed = child
+		}
+	}
+	if failed == nil || failed.Path != filepath.Join(root, "b.txt") || !strings.Contains(failed.Error.Error(), "boom") {
+		t.Fatalf("failed child = %+v, want b.txt with the counter's error", failed)
+	}
+	if result.Tokens != 3 || result.CountFiles() != 2 {
+		t.Errorf("directory = %d tokens in %d files, want 3 tokens in 2 files", result.Tokens, result.CountFiles())
+	}
+	if nested := Nest(result); nested.Tokens != 3 {
+		t.Errorf("nested total = %d, want 3", nested.Tokens)
+	}
+}
+
+func TestProcessPathCacheByModel(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"a.txt": "one two three",
+		"b.txt": "four",
+	})
+	c, err := cache.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+
+	run := func(model string) (*Result, int) {
+		t.Helper()
+		counter := &fakeCounter{}
+		cfg := testConfig()
+		cfg.Model = model
+		result, err := New(counter, c, cfg).ProcessPath(root)
+		if err != nil {
+			t.Fatalf("ProcessPath: %v", err)
+		}
+		return result, counter.calls()
+	}
+
+	if _, calls := run("claude-sonnet-4-5"); calls != 2 {
+		t.Errorf("first run made %d requests, want 2", calls)
+	}
+	result, calls := run("claude-sonnet-4-5")
+	if calls != 0 {
+		t.Errorf("cached run made %d requests, want 0", calls)
+	}
+	if result.Tokens != 4 {
+		t.Errorf("cached Tokens = %d, want 4", result.Tokens)
+	}
+	for _, child := range result.Children {
+		if !child.Cached {
+			t.Errorf("%s not served from cache", child.Path)
+		}
+	}
+	if _, calls := run("claude-haiku-4-5"); calls != 2 {
+		t.Errorf("run with another model made %d requests, want 2", calls)
+	}
+}
+
+func TestProcessTexts(t *testing.T) {
+	counter := &fakeCounter{}
+	results, err := New(counter, nil, testConfig()).ProcessTexts([]string{"one two", "three"})
+	if err != nil {
+		t.Fatalf("ProcessTexts: %v", err)
+	}
+
+	want := []struct {
+		path   string
+		tokens int
+	}{{"<text>#1", 2}, {"<text>#2", 1}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Path != w.path || results[i].Tokens != w.tokens {
+			t.Errorf("result %d = %s %d, want %s %d", i, results[i].Path, results[i].Tokens, w.path, w.tokens)
+		}
+	}
+	if counter.calls() != 2 {
+		t.Errorf("counter called %d times, want 2", counter.calls())
+	}
+}
+
+func TestProcessPathRefresh(t *testing.T) {
+	root := writeFixture(t, map[string]string{"a.txt": "one two three"})
+	path := filepath.Join(root, "a.txt")
+	c, err := cache.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+	if _, err := New(&fakeCounter{}, c, testConfig()).ProcessPath(path); err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+
+	// Keep the entry valid for the unchanged file but give it a stale count
+	entry, _ := c.Peek("claude-sonnet-4-5", path)
+	entry.Tokens = 99
+	entry.CachedAt = time.Now().Add(-time.Hour)
+	c.Set("claude-sonnet-4-5", path, entry)
+
+	run := func(refresh bool) (*Result, int) {
+		t.Helper()
+		counter := &fakeCounter{}
+		cfg := testConfig()
+		cfg.Refresh = refresh
+		result, err := New(counter, c, cfg).ProcessPath(path)
+		if err != nil {
+			t.Fatalf("ProcessPath: %v", err)
+		}
+		return result, counter.calls()
+	}
+
+	if result, calls := run(false); calls != 0 || !result.Cached || result.Tokens != 99 {
+		t.Fatalf("cached run = %d tokens (cached %v) with %d requests, want the cached 99 and no requests", result.Tokens, result.Cached, calls)
+	}
+	result, calls := run(true)
+	if calls != 1 || result.Cached || result.Tokens != 3 {
+		t.Errorf("--refresh run = %d tokens (cached %v) with %d requests, want a fresh count of 3", result.Tokens, result.Cached, calls)
+	}
+	if refreshed, _ := c.Peek("claude-sonnet-4-5", path); refreshed.Tokens != 3 || !refreshed.CachedAt.After(entry.CachedAt) {
+		t.Errorf("cache entry after --refresh = %+v, want the fresh count", refreshed)
+	}
+}
+
+func TestProcessPathKeepContent(t *testing.T) {
+	root := writeFixture(t, map[string]string{"page.html": "<p>Hello <b>world</b></p>"})
+	cfg := testConfig()
+	cfg.StripHTML = true
+
+	proc := New(&fakeCounter{}, nil, cfg)
+	result, err := proc.ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if got := result.Children[0].Content; got != "" {
+		t.Errorf("Content = %q without SetKeepContent, want empty", got)
+	}
+
+	proc.SetKeepContent(true)
+	result, err = proc.ProcessPath(root)
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if got := normalizeSpace(result.Children[0].Content); got != "Hello world" {
+		t.Errorf("Content = %q, want the stripped text that was counted", got)
+	}
+}
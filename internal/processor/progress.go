@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+// progressInterval is the minimum time between progress updates, so fast scans don't flood
+// the terminal
+const progressInterval = 100 * time.Millisecond
+
+// SetProgressWriter enables progress reporting for directory scans and file lists: a line with the files
+// counted so far and their estimated cost is rewritten on w as files complete. A nil writer
+// disables it.
+func (p *Processor) SetProgressWriter(w io.Writer) {
+	p.progress = w
+}
+
+// progressReporter tracks completed files of a directory scan and writes progress updates.
+// It is safe for concurrent use.
+type progressReporter struct {
+	w         io.Writer
+	pricer    *pricing.Pricer // Nil to leave out the cost
+	model     string
+	precision int
+	total     int
+
+	mu         sync.Mutex
+	done       int
+	tokens     int
+	lastUpdate time.Time
+	finished   bool // Set by finish; stragglers after an interrupt are ignored
+}
+
+// newProgressReporter creates a reporter for a scan of total files
+func (p *Processor) newProgressReporter(total int) *progressReporter {
+	return &progressReporter{
+		w:         p.progress,
+		pricer:    p.pricer,
+		model:     p.config.Model,
+		precision: p.config.CostPrecision,
+		total:     total,
+	}
+}
+
+// add records a completed file and writes an update unless one was written very recently
+func (r *progressReporter) add(result *Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished {
+		return
+	}
+	r.done++
+	if result.Error == nil && !result.SkippedBinary {
+		r.tokens += result.Tokens
+	}
+	if now := time.Now(); r.done == r.total || now.Sub(r.lastUpdate) >= progressInterval {
+		r.lastUpdate = now
+		r.write()
+	}
+}
+
+// finish writes the final state and ends the progress line
+func (r *progressReporter) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.finished = true
+	r.write()
+	fmt.Fprintln(r.w)
+}
+
+// write rewrites the progress line in place
+func (r *progressReporter) write() {
+	line := fmt.Sprintf("\rCounted %d/%d files", r.done, r.total)
+	if r.pricer != nil {
+		cost := r.pricer.CalculateCost(r.tokens, r.model)
+		line += fmt.Sprintf(", %s estimated so far", r.pricer.FormatCost(cost, r.precision))
+	}
+	fmt.Fprint(r.w, line)
+}
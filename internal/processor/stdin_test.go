@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessStdinDocuments(t *testing.T) {
+	tests := []struct {
+		name      string
+		stdin     string
+		delimiter string
+		stdinName string
+		want      []string // "path=tokens" per document
+	}{
+		{"null byte", "first document here\x00second one", `\0`, "", []string{"<stdin>#1=3", "<stdin>#2=2"}},
+		{"empty documents skipped", "a b\x00\x00 \n\x00c", `\0`, "", []string{"<stdin>#1=2", "<stdin>#2=1"}},
+		{"literal delimiter", "one---two three", "---", "batch", []string{"batch#1=1", "batch#2=2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.stdin)
+			cfg := testConfig()
+			cfg.StdinName = tt.stdinName
+
+			results, err := New(&fakeCounter{}, nil, cfg).ProcessStdinDocuments(tt.delimiter)
+			if err != nil {
+				t.Fatalf("ProcessStdinDocuments: %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				if result.Error != nil {
+					t.Fatalf("%s: %v", result.Path, result.Error)
+				}
+				got = append(got, fmt.Sprintf("%s=%d", result.Path, result.Tokens))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessStdinLineMetrics(t *testing.T) {
+	content := "one two three\nfour five\nsix seven eight nine\n"
+	withStdin(t, content)
+
+	proc := New(&fakeCounter{}, nil, testConfig())
+	stdin, err := proc.ProcessPath("-")
+	if err != nil {
+		t.Fatalf("ProcessPath(-): %v", err)
+	}
+	if stdin.Tokens != 9 || stdin.LineCount == 0 {
+		t.Fatalf("stdin result = %d tokens over %d lines, want 9 tokens with a line count", stdin.Tokens, stdin.LineCount)
+	}
+
+	// Stdin gets the same line metrics as a file with the same content
+	root := writeFixture(t, map[string]string{"input.txt": content})
+	file, err := proc.ProcessPath(filepath.Join(root, "input.txt"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if stdin.LineCount != file.LineCount || stdin.AvgTokensPerLine != file.AvgTokensPerLine {
+		t.Errorf("stdin: %d lines, %.2f tokens/line; file: %d lines, %.2f tokens/line",
+			stdin.LineCount, stdin.AvgTokensPerLine, file.LineCount, file.AvgTokensPerLine)
+	}
+	if want := float64(stdin.Tokens) / float64(stdin.LineCount); stdin.AvgTokensPerLine != want {
+		t.Errorf("AvgTokensPerLine = %.2f, want %.2f", stdin.AvgTokensPerLine, want)
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessPathSymlinkedRoot(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"real/a.txt":     "one two",
+		"real/sub/b.txt": "three",
+		"other/c.txt":    "four five six",
+	})
+	link := filepath.Join(root, "linkdir")
+	if err := os.Symlink(filepath.Join(root, "real"), link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "other"), filepath.Join(root, "real", "tolink")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		followSymlinks bool
+		want           []string
+	}{
+		{"root link is walked", false, []string{"a.txt", "sub/b.txt"}},
+		{"links below root followed", true, []string{"a.txt", "sub/b.txt", "tolink/c.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.FollowSymlinks = tt.followSymlinks
+			result, err := New(&fakeCounter{}, nil, cfg).ProcessPath(link)
+			if err != nil {
+				t.Fatalf("ProcessPath: %v", err)
+			}
+			got := countedFiles(t, link, result)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("counted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessPathSymlinkLoop(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"top.txt":       "zero",
+		"dir/a.txt":     "one two",
+		"dir/sub/b.txt": "three",
+	})
+	// dir/loop points back at the root, which contains dir again
+	if err := os.Symlink("..", filepath.Join(root, "dir", "loop")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	want := []string{"dir/a.txt", "dir/sub/b.txt", "top.txt"}
+	for _, followSymlinks := range []bool{false, true} {
+		cfg := testConfig()
+		cfg.FollowSymlinks = followSymlinks
+
+		done := make(chan struct{})
+		var result *Result
+		var err error
+		go func() {
+			defer close(done)
+			result, err = New(&fakeCounter{}, nil, cfg).ProcessPath(root)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("follow symlinks %v: walk didn't terminate", followSymlinks)
+		}
+
+		if err != nil {
+			t.Fatalf("follow symlinks %v: ProcessPath: %v", followSymlinks, err)
+		}
+		// The loop is skipped: nothing is counted twice through dir/loop
+		if got := countedFiles(t, root, result); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("follow symlinks %v: counted %v, want %v", followSymlinks, got, want)
+		}
+	}
+}
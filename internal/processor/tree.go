@@ -3,6 +3,7 @@ package processor
 import (
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // buildTree constructs a hierarchical tree structure from a flat list of file results,
@@ -49,3 +50,64 @@ func buildTree(rootPath string, results []*Result) *Result {
 
 	return root
 }
+
+// Nest returns a copy of a directory result with files grouped into nested directory results,
+// each carrying token subtotals of its successfully processed descendants. Directory results
+// from processDirectory hold all files as direct children; Nest reconstructs the hierarchy.
+func Nest(dir *Result) *Result {
+	if dir == nil || !dir.IsDir {
+		return dir
+	}
+
+	root := &Result{Path: dir.Path, IsDir: true}
+	for _, file := range dir.Children {
+		if file == nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(dir.Path, file.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(file.Path)
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		current := root
+		for _, part := range parts[:len(parts)-1] {
+			current = childDir(current, part)
+		}
+		current.Children = append(current.Children, file)
+	}
+
+	sumTokens(root)
+	return root
+}
+
+// childDir returns the nested directory result with the given name, creating it if missing
+func childDir(parent *Result, name string) *Result {
+	path := filepath.Join(parent.Path, name)
+	for _, child := range parent.Children {
+		if child.IsDir && child.Path == path {
+			return child
+		}
+	}
+
+	dir := &Result{Path: path, IsDir: true}
+	parent.Children = append(parent.Children, dir)
+	return dir
+}
+
+// sumTokens recalculates directory token totals from their children
+func sumTokens(r *Result) int {
+	if !r.IsDir {
+		if r.Error != nil {
+			return 0
+		}
+		return r.Tokens
+	}
+
+	r.Tokens = 0
+	for _, child := range r.Children {
+		r.Tokens += sumTokens(child)
+	}
+	return r.Tokens
+}
@@ -0,0 +1,33 @@
+package processor
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// truncateToTokens cuts content at the boundary after its first maxTokens tokens, as split by
+// the offline tokenizer, and reports whether anything was removed. The API count of the
+// truncated content can differ slightly from maxTokens since the tokenizers aren't identical.
+func (p *Processor) truncateToTokens(content []byte, maxTokens int) ([]byte, bool, error) {
+	if maxTokens <= 0 || len(content) == 0 {
+		return content, false, nil
+	}
+
+	tokens, err := p.apiClient.ExtractTokensClientSide(string(content))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to tokenize for --max-tokens: %w", err)
+	}
+	if len(tokens) <= maxTokens {
+		return content, false, nil
+	}
+
+	// Cut where the first dropped token starts, backing up so a multi-byte character isn't split
+	cut := tokens[maxTokens].Position
+	if cut > len(content) {
+		cut = len(content)
+	}
+	for cut > 0 && cut < len(content) && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut], cut < len(content), nil
+}
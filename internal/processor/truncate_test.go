@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTruncateToTokens(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		maxTokens     int
+		want          string
+		wantTruncated bool
+	}{
+		{"under limit", "one two", 5, "one two", false},
+		{"at limit", "one two three", 3, "one two three", false},
+		{"cut at token boundary", "one two three four", 2, "one two ", true},
+		{"disabled", "one two three", 0, "one two three", false},
+		{"empty", "", 3, "", false},
+		// The word tokenizer yields "héllo" whole; cutting before "wörld" must not split "ö"
+		{"multi-byte", "héllo wörld again", 1, "héllo ", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(&fakeCounter{}, nil, testConfig())
+			got, truncated, err := p.truncateToTokens([]byte(tt.content), tt.maxTokens)
+			if err != nil {
+				t.Fatalf("truncateToTokens: %v", err)
+			}
+			if string(got) != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("truncateToTokens(%q, %d) = %q, %v; want %q, %v", tt.content, tt.maxTokens, got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestProcessPathMaxTokens(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"large.log": strings.Repeat("line of log output\n", 5000),
+		"small.txt": "just three words",
+	})
+	counter := &fakeCounter{}
+	cfg := testConfig()
+	cfg.MaxTokens = 100
+
+	large, err := New(counter, nil, cfg).ProcessPath(filepath.Join(root, "large.log"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if large.Error != nil {
+		t.Fatalf("result error: %v", large.Error)
+	}
+	if large.Tokens != cfg.MaxTokens || !large.Truncated {
+		t.Errorf("large file = %d tokens, truncated %v; want %d, true", large.Tokens, large.Truncated, cfg.MaxTokens)
+	}
+	if sent := counter.requests[0]; len(sent) >= 5000*19 {
+		t.Errorf("sent %d bytes, want the truncated content only", len(sent))
+	}
+
+	small, err := New(counter, nil, cfg).ProcessPath(filepath.Join(root, "small.txt"))
+	if err != nil {
+		t.Fatalf("ProcessPath: %v", err)
+	}
+	if small.Tokens != 3 || small.Truncated {
+		t.Errorf("small file = %d tokens, truncated %v; want 3, false", small.Tokens, small.Truncated)
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// SearchResult holds the tokens matching a /api/search query
+type SearchResult struct {
+	Query   string `json:"query"`
+	Indices []int  `json:"indices"` // Token indices in order of appearance
+}
+
+// SearchTokens returns the indices of the tokens whose text contains query, ignoring case
+func SearchTokens(tokens []api.Token, query string) []int {
+	indices := []int{}
+	if query == "" {
+		return indices
+	}
+
+	query = strings.ToLower(query)
+	for i, token := range tokens {
+		if strings.Contains(strings.ToLower(token.Text), query) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// handleSearch serves the indices of the tokens matching the q parameter as JSON
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	result := SearchResult{
+		Query:   query,
+		Indices: SearchTokens(s.result.Tokens, query),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, fmt.Sprintf("Encoding error: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSearchHandler(t *testing.T) {
+	srv := newTestServer(t, "Token", "izing", " tokens", " is", " fun", ".", " TOKEN", "s")
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"token", []int{0, 2, 6}}, // Case-insensitive, in order of appearance
+		{" ", []int{2, 3, 4, 6}},
+		{"izing", []int{1}},
+		{"tokenizing", []int{}}, // Tokens are matched one at a time
+		{"", []int{}},
+	}
+	for _, tt := range tests {
+		var result SearchResult
+		getJSON(t, srv.URL+"/api/search?q="+url.QueryEscape(tt.query), &result)
+		if result.Query != tt.query || !reflect.DeepEqual(result.Indices, tt.want) {
+			t.Errorf("search %q = %+v, want indices %v", tt.query, result, tt.want)
+		}
+	}
+}
@@ -10,16 +10,22 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/utils"
 	"github.com/pkg/browser"
 )
 
 //go:embed templates/* static/*
 var content embed.FS
 
+// DefaultHost is the address the server listens on unless --host says otherwise
+const DefaultHost = "localhost"
+
 const (
 	shutdownTimeout   = 5 * time.Second
 	startPort         = 8080
@@ -35,20 +41,47 @@ type Result struct {
 	APITokens   int // API token count (includes overhead)
 	Model       string
 	Cost        float64
+	CostSymbol  string // Currency symbol printed before Cost
 }
 
 // Server handles HTTP requests for token visualization
 type Server struct {
 	addr   string
+	url    string // URL printed and opened; reachable from other machines for non-loopback hosts
 	tmpl   *template.Template
 	result *Result
+	stats  *Stats // Computed once in Start for /api/stats
+
+	lineStarts []int // Byte offset of each line of result.Content, for token locations
 }
 
-// New creates a new Server instance with an available port
-func New() (*Server, error) {
-	port, err := findAvailablePort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to find available port: %w", err)
+// TokenLocation is where a token starts in the source: a 1-based line and byte column
+type TokenLocation struct {
+	Line   int
+	Column int
+}
+
+// New creates a new Server instance listening on host and port, or on the first available
+// port from 8080 when port is 0
+func New(host string, port int) (*Server, error) {
+	if port > 0 {
+		if err := checkPort(host, port); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		port, err = findAvailablePort(host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find available port: %w", err)
+		}
+	}
+
+	s := &Server{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		url:  "http://" + net.JoinHostPort(reachableHost(host), strconv.Itoa(port)),
+	}
+	if !isLoopback(host) {
+		fmt.Fprintf(os.Stderr, "⚠️  Listening on %s, which is not a loopback address: anyone who can reach it can read the visualized content\n", host)
 	}
 
 	tmpl, err := template.New("visualize.html").Funcs(template.FuncMap{
@@ -59,30 +92,25 @@ func New() (*Server, error) {
 			colors := []string{"cyan", "green", "yellow", "blue", "magenta", "red"}
 			return colors[i%6]
 		},
+		"location": s.location,
 	}).ParseFS(content, "templates/*.html")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
 
-	return &Server{
-		addr: fmt.Sprintf("localhost:%d", port),
-		tmpl: tmpl,
-	}, nil
+	s.tmpl = tmpl
+
+	return s, nil
 }
 
 // Start launches the HTTP server and opens the browser
 func (s *Server) Start(result *Result, openBrowser bool) error {
-	s.result = result
-
-	// Setup routes
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handleIndex)
-	mux.Handle("/static/", http.FileServer(http.FS(content)))
+	s.load(result)
 
 	// Create server with graceful shutdown
 	srv := &http.Server{
 		Addr:    s.addr,
-		Handler: mux,
+		Handler: s.handler(),
 	}
 
 	// Channel to listen for interrupt signals
@@ -91,7 +119,7 @@ func (s *Server) Start(result *Result, openBrowser bool) error {
 
 	// Start server in goroutine
 	go func() {
-		fmt.Fprintf(os.Stderr, "\n✓ Visualization server started at http://%s\n", s.addr)
+		fmt.Fprintf(os.Stderr, "\n✓ Visualization server started at %s\n", s.url)
 		fmt.Fprintf(os.Stderr, "✓ Press Ctrl+C to stop the server\n\n")
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -102,10 +130,9 @@ func (s *Server) Start(result *Result, openBrowser bool) error {
 	// Open browser if requested
 	if openBrowser {
 		time.Sleep(browserStartDelay) // Give server time to start
-		url := fmt.Sprintf("http://%s", s.addr)
-		if err := browser.OpenURL(url); err != nil {
+		if err := browser.OpenURL(s.url); err != nil {
 			fmt.Fprintf(os.Stderr, "⚠️  Failed to open browser automatically: %v\n", err)
-			fmt.Fprintf(os.Stderr, "   Please open manually: %s\n\n", url)
+			fmt.Fprintf(os.Stderr, "   Please open manually: %s\n\n", s.url)
 		}
 	}
 
@@ -125,6 +152,23 @@ func (s *Server) Start(result *Result, openBrowser bool) error {
 	return nil
 }
 
+// load sets the result to serve and precomputes its statistics and line offsets
+func (s *Server) load(result *Result) {
+	s.result = result
+	s.stats = BuildStats(result)
+	s.lineStarts = utils.CalculateLineStarts(strings.Split(result.Content, "\n"))
+}
+
+// handler returns the routes of the visualization server
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/api/search", s.handleSearch)
+	mux.Handle("/static/", http.FileServer(http.FS(content)))
+	return mux
+}
+
 // handleIndex serves the main visualization page
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
@@ -144,11 +188,56 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// findAvailablePort finds an available port starting from 8080
-func findAvailablePort() (int, error) {
+// location returns where the token at a byte position starts in the visualized content
+func (s *Server) location(position int) TokenLocation {
+	line, column := utils.LineAndColumn(position, s.lineStarts)
+	return TokenLocation{Line: line, Column: column}
+}
+
+// isLoopback reports whether host only accepts connections from this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// reachableHost returns the host to put in the server URL. A wildcard address such as
+// 0.0.0.0 is replaced by this machine's first non-loopback IPv4 address, or localhost
+// when it has none.
+func reachableHost(host string) string {
+	if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+		return host
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "localhost"
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return "localhost"
+}
+
+// checkPort reports an error if the given port cannot be bound on host
+func checkPort(host string, port int) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("port %d is not available: %w", port, err)
+	}
+	listener.Close()
+	return nil
+}
+
+// findAvailablePort finds an available port on host starting from 8080
+func findAvailablePort(host string) (int, error) {
 	for i := 0; i < maxPortAttempts; i++ {
 		port := startPort + i
-		addr := fmt.Sprintf("localhost:%d", port)
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
 
 		listener, err := net.Listen("tcp", addr)
 		if err == nil {
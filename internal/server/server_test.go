@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/api"
+)
+
+// newTestServer serves the tokens with the given texts, in order, through the server's routes
+func newTestServer(t *testing.T, texts ...string) *httptest.Server {
+	t.Helper()
+	result := &Result{Model: "claude-sonnet-4-5"}
+	for _, text := range texts {
+		result.Tokens = append(result.Tokens, api.Token{Text: text, Position: len(result.Content), Length: len(text)})
+		result.Content += text
+	}
+	result.TotalTokens = len(result.Tokens)
+
+	s, err := New(DefaultHost, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.load(result)
+	srv := httptest.NewServer(s.handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d: %s", url, resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s: Content-Type = %q, want application/json", url, ct)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v\n%s", url, err, body)
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	srv := newTestServer(t, "The", " cat", " sat", ".", "\n", "The", " end", ".")
+
+	var raw map[string]any
+	getJSON(t, srv.URL+"/api/stats", &raw)
+	wantTypes := map[string]string{
+		"total_tokens":        "number",
+		"bytes":               "number",
+		"bytes_per_token":     "number",
+		"length_percentiles":  "object",
+		"length_distribution": "array",
+		"common_tokens":       "array",
+		"categories":          "array",
+	}
+	for key, want := range wantTypes {
+		var got string
+		switch raw[key].(type) {
+		case float64:
+			got = "number"
+		case map[string]any:
+			got = "object"
+		case []any:
+			got = "array"
+		}
+		if got != want {
+			t.Errorf("%s is %T, want a JSON %s", key, raw[key], want)
+		}
+	}
+
+	var stats Stats
+	getJSON(t, srv.URL+"/api/stats", &stats)
+	if stats.TotalTokens != 8 || stats.Bytes != 21 || stats.BytesPerToken != 21.0/8 {
+		t.Errorf("totals = %d tokens, %d bytes, %.3f bytes/token; want 8, 21, 2.625", stats.TotalTokens, stats.Bytes, stats.BytesPerToken)
+	}
+	wantLengths := []LengthBucket{{1, 3}, {3, 2}, {4, 3}}
+	if len(stats.LengthDistribution) != len(wantLengths) {
+		t.Fatalf("length distribution = %v, want %v", stats.LengthDistribution, wantLengths)
+	}
+	for i, want := range wantLengths {
+		if stats.LengthDistribution[i] != want {
+			t.Errorf("length distribution = %v, want %v", stats.LengthDistribution, wantLengths)
+			break
+		}
+	}
+	if len(stats.CommonTokens) < 2 || stats.CommonTokens[0] != (TokenFrequency{".", 2}) || stats.CommonTokens[1] != (TokenFrequency{"The", 2}) {
+		t.Errorf("common tokens = %v, want \".\" and \"The\" twice first", stats.CommonTokens)
+	}
+	if len(stats.Categories) != 6 || stats.Categories[0].Name != "Prose" {
+		t.Errorf("categories = %v, want the six analyzer categories starting with Prose", stats.Categories)
+	}
+}
+
+func TestIndexShowsOffsetsOnHover(t *testing.T) {
+	srv := newTestServer(t, "Hello", " world", "\n", "Bye")
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	html := string(body)
+
+	// "Bye" is token #3, at byte 12 on line 2
+	for _, want := range []string{
+		`data-index="3"`,
+		`data-position="12"`,
+		`data-length="3"`,
+		`data-line="2"`,
+		`title="Token #3 | Byte offset: 12 | Line 2, col 1 | Len: 3 bytes"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page is missing %s", want)
+		}
+	}
+}
+
+func TestNewWithBusyPort(t *testing.T) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(DefaultHost, "0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	_, err = New(DefaultHost, port)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("port %d is not available", port)) {
+		t.Fatalf("New on busy port %d: err = %v, want it reported as not available", port, err)
+	}
+
+	// A free port is bound exactly, without scanning
+	listener.Close()
+	s, err := New(DefaultHost, port)
+	if err != nil {
+		t.Fatalf("New on free port %d: %v", port, err)
+	}
+	if want := net.JoinHostPort(DefaultHost, strconv.Itoa(port)); s.addr != want {
+		t.Errorf("addr = %s, want %s", s.addr, want)
+	}
+}
+
+// captureStderr runs fn with os.Stderr redirected and returns what it wrote
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	os.Stderr = stderr
+	return <-done
+}
+
+func TestNewWithCustomHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		wantWarn bool
+	}{
+		{"localhost", false},
+		{"127.0.0.1", false},
+		{"0.0.0.0", true},
+	}
+	for _, tt := range tests {
+		var s *Server
+		stderr := captureStderr(t, func() {
+			var err error
+			if s, err = New(tt.host, 0); err != nil {
+				t.Fatalf("New(%q): %v", tt.host, err)
+			}
+		})
+
+		host, port, err := net.SplitHostPort(s.addr)
+		if err != nil || host != tt.host || port == "0" {
+			t.Errorf("New(%q) addr = %s, want the host with a chosen port", tt.host, s.addr)
+		}
+		if got := strings.Contains(stderr, "not a loopback address"); got != tt.wantWarn {
+			t.Errorf("New(%q) warned = %v, want %v (stderr %q)", tt.host, got, tt.wantWarn, stderr)
+		}
+		// A wildcard address isn't something to browse to; the URL names a reachable host
+		if strings.Contains(s.url, "0.0.0.0") || !strings.HasSuffix(s.url, ":"+port) {
+			t.Errorf("New(%q) url = %s, want a reachable host on port %s", tt.host, s.url, port)
+		}
+	}
+}
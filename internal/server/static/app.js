@@ -7,6 +7,9 @@
     let currentTheme = 'dark'; // 'dark' or 'light'
     let selectedTokenIndex = null;
     let tokens = [];
+    let searchMatches = []; // Token indices returned by /api/search
+    let currentMatch = -1;
+    let searchTimer = null;
 
     // DOM Elements
     const textView = document.getElementById('text-view');
@@ -21,6 +24,7 @@
     const searchCount = document.getElementById('search-count');
     const helpModal = document.getElementById('help-modal');
     const closeHelpBtn = document.getElementById('close-help');
+    const tokenStats = document.getElementById('token-stats');
 
     // Initialize
     function init() {
@@ -52,6 +56,80 @@
         document.getElementById('stat-min').textContent = minLength;
     }
 
+    // Fetch token frequency and category statistics from the server
+    function loadTokenStats() {
+        fetch('/api/stats')
+            .then(response => {
+                if (!response.ok) {
+                    throw new Error(`HTTP ${response.status}`);
+                }
+                return response.json();
+            })
+            .then(stats => {
+                tokenStats.dataset.loaded = 'true';
+                renderTokenStats(stats);
+            })
+            .catch(err => console.error('Failed to load statistics:', err));
+    }
+
+    // Render the /api/stats response into the token frequency panel
+    function renderTokenStats(stats) {
+        document.getElementById('stat-bytes-per-token').textContent = stats.bytes_per_token.toFixed(2);
+        document.getElementById('stat-median').textContent = stats.length_percentiles.median;
+        document.getElementById('stat-p95').textContent = stats.length_percentiles.p95;
+
+        const maxCount = Math.max(1, ...stats.length_distribution.map(b => b.count));
+        fillTable('stat-lengths', ['Length', 'Tokens', ''], stats.length_distribution.map(b => [
+            b.length, b.count, bar(b.count / maxCount),
+        ]));
+        fillTable('stat-common', ['Token', 'Count'], stats.common_tokens.map(t => [
+            code(t.text), t.count,
+        ]));
+        fillTable('stat-categories', ['Category', 'Tokens', '%'], stats.categories.map(c => [
+            c.name, c.tokens, c.percentage.toFixed(1),
+        ]));
+    }
+
+    // Replace a table's contents; cells may be text or DOM nodes
+    function fillTable(id, headers, rows) {
+        const table = document.getElementById(id);
+        table.replaceChildren();
+        const head = table.createTHead().insertRow();
+        headers.forEach(h => {
+            const th = document.createElement('th');
+            th.textContent = h;
+            head.appendChild(th);
+        });
+        const body = table.createTBody();
+        rows.forEach(cells => {
+            const row = body.insertRow();
+            cells.forEach(value => {
+                const cell = row.insertCell();
+                if (value instanceof Node) {
+                    cell.appendChild(value);
+                } else {
+                    cell.textContent = value;
+                }
+            });
+        });
+    }
+
+    // Token text with whitespace made visible
+    function code(text) {
+        const el = document.createElement('code');
+        el.className = 'token-code';
+        el.textContent = text.replace(/ /g, '·').replace(/\n/g, '↵').replace(/\t/g, '→');
+        return el;
+    }
+
+    // Horizontal bar scaled to fraction of the panel column
+    function bar(fraction) {
+        const el = document.createElement('span');
+        el.className = 'length-bar';
+        el.style.width = `${Math.round(fraction * 100)}%`;
+        return el;
+    }
+
     // Setup event listeners
     function setupEventListeners() {
         // View toggle
@@ -61,7 +139,16 @@
         themeToggleBtn.addEventListener('click', toggleTheme);
 
         // Search functionality
-        searchInput.addEventListener('input', handleSearch);
+        searchInput.addEventListener('input', () => {
+            clearTimeout(searchTimer);
+            searchTimer = setTimeout(handleSearch, 150);
+        });
+        searchInput.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter') {
+                e.preventDefault();
+                jumpToMatch(e.shiftKey ? -1 : 1);
+            }
+        });
 
         // Token click handlers (text view)
         tokens.forEach((token, index) => {
@@ -84,6 +171,13 @@
             });
         });
 
+        // Token frequency panel loads on first open
+        tokenStats.addEventListener('toggle', () => {
+            if (tokenStats.open && !tokenStats.dataset.loaded) {
+                loadTokenStats();
+            }
+        });
+
         // Help modal
         closeHelpBtn.addEventListener('click', closeHelp);
 
@@ -144,33 +238,71 @@
         }
     }
 
-    // Handle search input
+    // Handle search input by asking the server which tokens match
     function handleSearch() {
-        const query = searchInput.value.toLowerCase().trim();
+        const query = searchInput.value;
 
         if (query === '') {
             clearSearch();
             return;
         }
 
-        let matchCount = 0;
+        fetch(`/api/search?q=${encodeURIComponent(query)}`)
+            .then(response => {
+                if (!response.ok) {
+                    throw new Error(`HTTP ${response.status}`);
+                }
+                return response.json();
+            })
+            .then(result => {
+                // Ignore responses for a query the user has since changed
+                if (result.query === searchInput.value) {
+                    showMatches(result.indices);
+                }
+            })
+            .catch(err => console.error('Search failed:', err));
+    }
+
+    // Highlight the matching tokens and scroll to the first one
+    function showMatches(indices) {
+        const matched = new Set(indices);
         const tableRows = document.querySelectorAll('.token-table tbody tr');
 
         tokens.forEach((token, index) => {
-            const text = token.dataset.text.toLowerCase();
-            const matches = text.includes(query);
-
-            if (matches) {
-                matchCount++;
-                token.classList.add('highlighted');
-                tableRows[index]?.classList.add('highlighted');
-            } else {
-                token.classList.remove('highlighted');
-                tableRows[index]?.classList.remove('highlighted');
-            }
+            const matches = matched.has(index);
+            token.classList.toggle('highlighted', matches);
+            token.classList.remove('current-match');
+            tableRows[index]?.classList.toggle('highlighted', matches);
+            tableRows[index]?.classList.remove('current-match');
         });
 
-        searchCount.textContent = `${matchCount} match${matchCount !== 1 ? 'es' : ''}`;
+        searchMatches = indices;
+        currentMatch = -1;
+        searchCount.textContent = `${indices.length} match${indices.length !== 1 ? 'es' : ''}`;
+        jumpToMatch(1);
+    }
+
+    // Scroll to the next (direction 1) or previous (-1) search match, wrapping around
+    function jumpToMatch(direction) {
+        if (searchMatches.length === 0) {
+            return;
+        }
+
+        const tableRows = document.querySelectorAll('.token-table tbody tr');
+        if (currentMatch >= 0) {
+            const previous = searchMatches[currentMatch];
+            tokens[previous].classList.remove('current-match');
+            tableRows[previous]?.classList.remove('current-match');
+        }
+
+        currentMatch = (currentMatch + direction + searchMatches.length) % searchMatches.length;
+        const index = searchMatches[currentMatch];
+        tokens[index].classList.add('current-match');
+        tableRows[index]?.classList.add('current-match');
+        searchCount.textContent = `${currentMatch + 1} of ${searchMatches.length}`;
+
+        const target = currentView === 'table' ? tableRows[index] : tokens[index];
+        target?.scrollIntoView({ behavior: 'smooth', block: 'center' });
     }
 
     // Clear search
@@ -178,12 +310,14 @@
         searchInput.value = '';
         searchCount.textContent = '';
         tokens.forEach(token => {
-            token.classList.remove('highlighted');
+            token.classList.remove('highlighted', 'current-match');
         });
         const tableRows = document.querySelectorAll('.token-table tbody tr');
         tableRows.forEach(row => {
-            row.classList.remove('highlighted');
+            row.classList.remove('highlighted', 'current-match');
         });
+        searchMatches = [];
+        currentMatch = -1;
         selectedTokenIndex = null;
     }
 
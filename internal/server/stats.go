@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+)
+
+// commonTokenLimit caps how many of the most frequent tokens /api/stats reports
+const commonTokenLimit = 20
+
+// Stats holds the token statistics served by /api/stats
+type Stats struct {
+	TotalTokens        int                       `json:"total_tokens"`
+	Bytes              int                       `json:"bytes"`
+	BytesPerToken      float64                   `json:"bytes_per_token"`
+	LengthPercentiles  *analyzer.PercentileStats `json:"length_percentiles"`
+	LengthDistribution []LengthBucket            `json:"length_distribution"`
+	CommonTokens       []TokenFrequency          `json:"common_tokens"`
+	Categories         []CategoryShare           `json:"categories"`
+}
+
+// LengthBucket counts the tokens of one character length
+type LengthBucket struct {
+	Length int `json:"length"`
+	Count  int `json:"count"`
+}
+
+// TokenFrequency counts the occurrences of one token text
+type TokenFrequency struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+// CategoryShare is one category of the analyzer's token breakdown
+type CategoryShare struct {
+	Name       string  `json:"name"`
+	Tokens     int     `json:"tokens"`
+	Percentage float64 `json:"percentage"`
+}
+
+// BuildStats computes token length, frequency and category statistics for a result
+func BuildStats(result *Result) *Stats {
+	stats := &Stats{
+		TotalTokens:        len(result.Tokens),
+		Bytes:              len(result.Content),
+		LengthDistribution: []LengthBucket{},
+		CommonTokens:       []TokenFrequency{},
+	}
+	if stats.TotalTokens > 0 {
+		stats.BytesPerToken = float64(stats.Bytes) / float64(stats.TotalTokens)
+	}
+
+	lengths := make([]int, len(result.Tokens))
+	byLength := make(map[int]int)
+	byText := make(map[string]int)
+	for i, token := range result.Tokens {
+		lengths[i] = token.Length
+		byLength[token.Length]++
+		byText[token.Text]++
+	}
+	stats.LengthPercentiles = analyzer.PercentilesOf(lengths)
+
+	for length, count := range byLength {
+		stats.LengthDistribution = append(stats.LengthDistribution, LengthBucket{Length: length, Count: count})
+	}
+	sort.Slice(stats.LengthDistribution, func(i, j int) bool {
+		return stats.LengthDistribution[i].Length < stats.LengthDistribution[j].Length
+	})
+
+	for text, count := range byText {
+		stats.CommonTokens = append(stats.CommonTokens, TokenFrequency{Text: text, Count: count})
+	}
+	sort.Slice(stats.CommonTokens, func(i, j int) bool {
+		if stats.CommonTokens[i].Count != stats.CommonTokens[j].Count {
+			return stats.CommonTokens[i].Count > stats.CommonTokens[j].Count
+		}
+		return stats.CommonTokens[i].Text < stats.CommonTokens[j].Text
+	})
+	if len(stats.CommonTokens) > commonTokenLimit {
+		stats.CommonTokens = stats.CommonTokens[:commonTokenLimit]
+	}
+
+	breakdown := analyzer.CategorizeTokens(
+		strings.Split(result.Content, "\n"),
+		result.Tokens,
+		analyzer.TokensPerLine(result.Content, result.Tokens),
+	)
+	percentages := breakdown.GetStats()
+	stats.Categories = []CategoryShare{
+		{"Prose", breakdown.Prose, percentages.Prose},
+		{"Code Blocks", breakdown.CodeBlocks, percentages.CodeBlocks},
+		{"URLs", breakdown.URLs, percentages.URLs},
+		{"Formatting", breakdown.Formatting, percentages.Formatting},
+		{"Tables", breakdown.Tables, percentages.Tables},
+		{"Whitespace", breakdown.Whitespace, percentages.Whitespace},
+	}
+
+	return stats
+}
+
+// handleStats serves token statistics for the visualized content as JSON
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.stats); err != nil {
+		http.Error(w, fmt.Sprintf("Encoding error: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,109 @@
+// Package tui provides an interactive terminal UI for browsing token counting results.
+package tui
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+// sortMode controls how sibling nodes are ordered
+type sortMode int
+
+const (
+	sortByTokens sortMode = iota
+	sortByName
+)
+
+// String returns the display label for the sort mode
+func (s sortMode) String() string {
+	if s == sortByName {
+		return "name"
+	}
+	return "tokens"
+}
+
+// node is a single entry in the navigable tree
+type node struct {
+	name     string
+	path     string
+	tokens   int
+	files    int
+	isDir    bool
+	err      error
+	expanded bool
+	parent   *node
+	children []*node
+}
+
+// buildNodes converts processor results into a navigable tree, using processor.Nest to
+// reconstruct intermediate directories from the flat directory results.
+func buildNodes(results []*processor.Result) []*node {
+	roots := make([]*node, 0, len(results))
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		root := toNode(processor.Nest(result), nil)
+		root.name = result.Path
+		root.expanded = true
+		roots = append(roots, root)
+	}
+
+	for _, root := range roots {
+		aggregate(root)
+	}
+
+	return roots
+}
+
+// toNode converts a (nested) result and its children into tree nodes
+func toNode(result *processor.Result, parent *node) *node {
+	n := &node{
+		name:   filepath.Base(result.Path),
+		path:   result.Path,
+		tokens: result.Tokens,
+		isDir:  result.IsDir,
+		err:    result.Error,
+		parent: parent,
+	}
+	if !result.IsDir && result.Error == nil && !result.SkippedBinary {
+		n.files = 1
+	}
+
+	for _, child := range result.Children {
+		n.children = append(n.children, toNode(child, n))
+	}
+	return n
+}
+
+// aggregate calculates directory token and file totals from their children
+func aggregate(n *node) {
+	if !n.isDir {
+		return
+	}
+
+	n.tokens = 0
+	n.files = 0
+	for _, child := range n.children {
+		aggregate(child)
+		n.tokens += child.tokens
+		n.files += child.files
+	}
+}
+
+// sortNodes orders nodes and their descendants in place
+func sortNodes(nodes []*node, mode sortMode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if mode == sortByTokens && nodes[i].tokens != nodes[j].tokens {
+			return nodes[i].tokens > nodes[j].tokens
+		}
+		return nodes[i].name < nodes[j].name
+	})
+	for _, n := range nodes {
+		sortNodes(n.children, mode)
+	}
+}
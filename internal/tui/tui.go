@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+const (
+	// defaultHeight is used until the terminal reports its size
+	defaultHeight = 24
+	// chromeLines is the number of header and footer lines around the tree view
+	chromeLines = 4
+)
+
+// row is a visible node together with its depth in the tree
+type row struct {
+	node  *node
+	depth int
+}
+
+// Model is the bubbletea model for browsing a Result tree
+type Model struct {
+	roots          []*node
+	rows           []row
+	cursor         int
+	offset         int
+	height         int
+	sort           sortMode
+	totalTokens    int
+	cfg            *config.Config
+	pricingService *pricing.Pricer
+}
+
+// NewModel creates a TUI model from processed results
+func NewModel(results []*processor.Result, cfg *config.Config, pricingService *pricing.Pricer) *Model {
+	m := &Model{
+		roots:          buildNodes(results),
+		height:         defaultHeight,
+		sort:           sortByTokens,
+		cfg:            cfg,
+		pricingService: pricingService,
+	}
+	for _, root := range m.roots {
+		m.totalTokens += root.tokens
+	}
+
+	sortNodes(m.roots, m.sort)
+	m.refreshRows()
+	return m
+}
+
+// Run starts the interactive TUI and blocks until the user quits
+func Run(results []*processor.Result, cfg *config.Config, pricingService *pricing.Pricer) error {
+	program := tea.NewProgram(NewModel(results, cfg, pricingService), tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model, handling navigation and sorting keys
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.clampOffset()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			m.moveCursor(-1)
+		case "down", "j":
+			m.moveCursor(1)
+		case "pgup":
+			m.moveCursor(-m.viewHeight())
+		case "pgdown":
+			m.moveCursor(m.viewHeight())
+		case "home", "g":
+			m.moveCursor(-len(m.rows))
+		case "end", "G":
+			m.moveCursor(len(m.rows))
+		case "enter", " ":
+			m.toggle()
+		case "right", "l":
+			m.expand()
+		case "left", "h":
+			m.collapse()
+		case "s":
+			m.toggleSort()
+		}
+	}
+
+	return m, nil
+}
+
+// View implements tea.Model
+func (m *Model) View() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "cc-token — %d tokens across %d roots (sorted by %s)\n", m.totalTokens, len(m.roots), m.sort)
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+
+	end := m.offset + m.viewHeight()
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	for i := m.offset; i < end; i++ {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		sb.WriteString(cursor + m.renderRow(m.rows[i]) + "\n")
+	}
+
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	sb.WriteString("↑/↓ move • enter toggle • ←/→ collapse/expand • s sort • q quit")
+
+	return sb.String()
+}
+
+// renderRow formats a single tree line with tokens, share of total and optional cost
+func (m *Model) renderRow(r row) string {
+	n := r.node
+	indent := strings.Repeat("  ", r.depth)
+
+	marker := "  "
+	name := n.name
+	if n.isDir {
+		marker = "▸ "
+		if n.expanded {
+			marker = "▾ "
+		}
+		name += "/"
+	}
+
+	if n.err != nil {
+		return fmt.Sprintf("%s%s%s: ERROR - %v", indent, marker, name, n.err)
+	}
+
+	share := 0.0
+	if m.totalTokens > 0 {
+		share = float64(n.tokens) / float64(m.totalTokens) * 100
+	}
+
+	line := fmt.Sprintf("%s%s%s: %d tokens (%.1f%%)", indent, marker, name, n.tokens, share)
+	if n.isDir {
+		line += fmt.Sprintf(" [%d files]", n.files)
+	}
+	if m.cfg.ShowCost {
+		cost := m.pricingService.CalculateCost(n.tokens, m.cfg.Model)
+		line += " " + m.pricingService.FormatCost(cost, m.cfg.CostPrecision)
+	}
+	return line
+}
+
+// viewHeight returns the number of tree rows that fit on screen
+func (m *Model) viewHeight() int {
+	h := m.height - chromeLines
+	if h < 1 {
+		return 1
+	}
+	return h
+}
+
+// moveCursor moves the selection by delta rows, keeping it on screen
+func (m *Model) moveCursor(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.clampOffset()
+}
+
+// clampOffset scrolls the view so the cursor stays visible
+func (m *Model) clampOffset() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.viewHeight() {
+		m.offset = m.cursor - m.viewHeight() + 1
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// selected returns the node under the cursor, if any
+func (m *Model) selected() *node {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].node
+}
+
+// toggle expands or collapses the selected directory
+func (m *Model) toggle() {
+	if n := m.selected(); n != nil && n.isDir {
+		n.expanded = !n.expanded
+		m.refreshRows()
+	}
+}
+
+// expand opens the selected directory
+func (m *Model) expand() {
+	if n := m.selected(); n != nil && n.isDir && !n.expanded {
+		n.expanded = true
+		m.refreshRows()
+	}
+}
+
+// collapse closes the selected directory, or jumps to the parent directory
+func (m *Model) collapse() {
+	n := m.selected()
+	if n == nil {
+		return
+	}
+
+	if n.isDir && n.expanded {
+		n.expanded = false
+		m.refreshRows()
+		return
+	}
+
+	if n.parent != nil {
+		for i, r := range m.rows {
+			if r.node == n.parent {
+				m.cursor = i
+				m.clampOffset()
+				return
+			}
+		}
+	}
+}
+
+// toggleSort switches between sorting by tokens and by name, keeping the selection
+func (m *Model) toggleSort() {
+	current := m.selected()
+	if m.sort == sortByTokens {
+		m.sort = sortByName
+	} else {
+		m.sort = sortByTokens
+	}
+	sortNodes(m.roots, m.sort)
+	m.refreshRows()
+
+	for i, r := range m.rows {
+		if r.node == current {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampOffset()
+}
+
+// refreshRows rebuilds the list of visible rows from the expanded state of the tree
+func (m *Model) refreshRows() {
+	m.rows = m.rows[:0]
+	var walk func(nodes []*node, depth int)
+	walk = func(nodes []*node, depth int) {
+		for _, n := range nodes {
+			m.rows = append(m.rows, row{node: n, depth: depth})
+			if n.isDir && n.expanded {
+				walk(n.children, depth+1)
+			}
+		}
+	}
+	walk(m.roots, 0)
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.clampOffset()
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+	"github.com/iota-uz/cc-token/internal/processor"
+)
+
+func TestNewModelFromResultTree(t *testing.T) {
+	results := []*processor.Result{
+		{
+			Path:  "project",
+			IsDir: true,
+			Children: []*processor.Result{
+				{Path: "project/main.go", Tokens: 30},
+				{Path: "project/docs/guide.md", Tokens: 50},
+				{Path: "project/docs/broken.md", Error: errors.New("read failed")},
+			},
+		},
+		{Path: "notes.txt", Tokens: 20},
+		nil,
+	}
+	cfg := &config.Config{Model: "claude-sonnet-4-5", ShowCost: true, CostPrecision: pricing.DefaultCostPrecision}
+	m := NewModel(results, cfg, pricing.New(""))
+
+	if m.totalTokens != 100 {
+		t.Errorf("total tokens = %d, want 100", m.totalTokens)
+	}
+	if len(m.roots) != 2 {
+		t.Fatalf("roots = %d, want 2", len(m.roots))
+	}
+	project := m.roots[0]
+	if project.name != "project" || project.tokens != 80 || project.files != 2 {
+		t.Errorf("project root = %s with %d tokens in %d files, want project with 80 in 2", project.name, project.tokens, project.files)
+	}
+
+	// Roots start expanded; nested directories start collapsed
+	var names []string
+	for _, r := range m.rows {
+		names = append(names, r.node.name)
+	}
+	if got := strings.Join(names, ","); got != "project,docs,main.go,notes.txt" {
+		t.Errorf("visible rows = %s, want project,docs,main.go,notes.txt", got)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "100 tokens across 2 roots") || !strings.Contains(view, "▸ docs/: 50 tokens (50.0%) [1 files]") {
+		t.Errorf("unexpected view:\n%s", view)
+	}
+
+	// Expanding docs shows its files, including the error
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := m.View(); !strings.Contains(view, "broken.md: ERROR - read failed") {
+		t.Errorf("expanded view has no error row:\n%s", view)
+	}
+}
+
+func TestNewModelEmpty(t *testing.T) {
+	m := NewModel(nil, &config.Config{}, pricing.New(""))
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.selected() != nil {
+		t.Error("selected a node in an empty tree")
+	}
+	_ = m.View()
+}
@@ -41,6 +41,15 @@ func FindLineForPosition(pos int, lineStarts []int) int {
 	return right
 }
 
+// LineAndColumn returns the 1-based line and byte column of a byte position
+func LineAndColumn(pos int, lineStarts []int) (int, int) {
+	line := FindLineForPosition(pos, lineStarts)
+	if line < 0 {
+		return 1, pos + 1
+	}
+	return line + 1, pos - lineStarts[line] + 1
+}
+
 // CalculateLineMetrics computes line count and average tokens per line
 // Returns (lineCount, avgTokensPerLine)
 func CalculateLineMetrics(content string, tokens int) (int, float64) {
@@ -13,3 +13,26 @@ func Truncate(s string, maxLen int) string {
 func EstimateTokens(text string) int {
 	return (len(text) + 3) / 4
 }
+
+// Levenshtein returns the edit distance between a and b: the number of single-character
+// insertions, deletions and substitutions needed to turn one into the other
+func Levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
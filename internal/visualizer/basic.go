@@ -26,6 +26,9 @@ var tokenColors = []*color.Color{
 	color.New(color.FgRed),
 }
 
+// issueColor marks tokens overlapping a safety issue with --highlight-issues
+var issueColor = color.New(color.BgRed, color.FgWhite, color.Bold)
+
 // Render displays tokens with colored borders in the terminal
 func (r *BasicRenderer) Render(result *Result) error {
 	if result == nil {
@@ -40,13 +43,23 @@ func (r *BasicRenderer) Render(result *Result) error {
 		result.TotalTokens, result.APITokens)
 	fmt.Fprintf(os.Stdout, "Characters: %d    Model: %s\n", len(result.Content), result.Model)
 	if result.Cost > 0 {
-		fmt.Fprintf(os.Stdout, "Estimated Cost: $%.6f\n", result.Cost)
+		fmt.Fprintf(os.Stdout, "Estimated Cost: %s%.6f\n", result.Currency.Symbol, result.Cost)
 	}
 	fmt.Fprintln(os.Stdout, strings.Repeat("=", headerWidth))
 	fmt.Fprintln(os.Stdout)
 
 	// Render tokens with alternating colors
 	for i, token := range result.Tokens {
+		if issue, ok := result.Issues[i]; ok {
+			// Invisible characters get a visible placeholder so the highlight isn't empty
+			text := token.Text
+			if issue.Kind == "invisible" {
+				text = "‹" + issue.Label + "›"
+			}
+			fmt.Fprint(os.Stdout, issueColor.Sprintf("⎡%s⎦", text))
+			continue
+		}
+
 		colorIndex := i % len(tokenColors)
 		c := tokenColors[colorIndex]
 
@@ -57,6 +70,13 @@ func (r *BasicRenderer) Render(result *Result) error {
 	fmt.Fprintln(os.Stdout)
 	fmt.Fprintln(os.Stdout)
 
+	// Legend for highlighted safety issues
+	if len(result.Issues) > 0 {
+		fmt.Fprintf(os.Stdout, "%s %d tokens overlap safety issues (%s)\n",
+			issueColor.Sprint("⎡ ⎦"), len(result.Issues), strings.Join(issueSummary(result.Issues), ", "))
+		fmt.Fprintln(os.Stdout)
+	}
+
 	// Footer
 	fmt.Fprintln(os.Stdout, strings.Repeat("=", headerWidth))
 	fmt.Fprintf(os.Stdout, "Content Tokens: %d  |  API Tokens: %d  |  Overhead: %d\n",
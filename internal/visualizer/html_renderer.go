@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"html/template"
 	"os"
+	"strings"
 
+	"github.com/iota-uz/cc-token/internal/utils"
 	"github.com/pkg/browser"
 )
 
@@ -17,6 +19,12 @@ const (
 	htmlFilePerm = 0644 // File permission for exported HTML files
 )
 
+// TokenLocation is where a token starts in the source: a 1-based line and byte column
+type TokenLocation struct {
+	Line   int
+	Column int
+}
+
 // HTMLRenderer exports token visualization to a static HTML file
 type HTMLRenderer struct {
 	OutputFile  string // Path to save HTML file
@@ -29,11 +37,26 @@ func (r *HTMLRenderer) Render(result *Result) error {
 		return fmt.Errorf("result is nil")
 	}
 
+	lineStarts := utils.CalculateLineStarts(strings.Split(result.Content, "\n"))
+
 	// Parse embedded template
 	tmpl, err := template.New("static.html").Funcs(template.FuncMap{
 		"colorIndex": func(i int) int {
 			return i % 6
 		},
+		"issueFor": func(i int) *TokenIssue {
+			if issue, ok := result.Issues[i]; ok {
+				return &issue
+			}
+			return nil
+		},
+		"location": func(position int) TokenLocation {
+			line, column := utils.LineAndColumn(position, lineStarts)
+			return TokenLocation{Line: line, Column: column}
+		},
+		"issueSummary": func() string {
+			return strings.Join(issueSummary(result.Issues), ", ")
+		},
 	}).ParseFS(htmlTemplate, "templates/static.html")
 	if err != nil {
 		return fmt.Errorf("failed to parse HTML template: %w", err)
@@ -12,11 +12,20 @@ type JSONRenderer struct{}
 
 // TokenJSON represents a single token in JSON output
 type TokenJSON struct {
-	Index    int    `json:"index"`     // Token index (0-based)
-	Text     string `json:"text"`      // Token text content
-	Position int    `json:"position"`  // Start position in original content
-	Length   int    `json:"length"`    // Length in characters
-	ByteSize int    `json:"byte_size"` // Length in bytes
+	Index    int     `json:"index"`     // Token index (0-based)
+	Text     string  `json:"text"`      // Token text content
+	Position int     `json:"position"`  // Start position in original content
+	Length   int     `json:"length"`    // Length in characters
+	ByteSize int     `json:"byte_size"` // Length in bytes
+	Cost     float64 `json:"cost"`      // Marginal cost contribution in Currency
+	// Issue is the safety issue the token overlaps (only with --highlight-issues)
+	Issue *TokenIssueJSON `json:"issue,omitempty"`
+}
+
+// TokenIssueJSON describes a safety issue overlapping a token
+type TokenIssueJSON struct {
+	Kind  string `json:"kind"`  // "invisible", "emoji" or "confusable"
+	Label string `json:"label"` // Character type or name
 }
 
 // ResultJSON represents the complete visualization result in JSON format
@@ -29,7 +38,8 @@ type ResultJSON struct {
 	TotalBytes    int         `json:"total_bytes"`     // Total bytes
 	TotalLines    int         `json:"total_lines"`     // Total lines in content
 	TokensPerLine float64     `json:"tokens_per_line"` // Average tokens per line
-	Cost          float64     `json:"cost"`            // Estimated cost in USD
+	Cost          float64     `json:"cost"`            // Estimated cost in Currency
+	Currency      string      `json:"currency"`        // ISO code of the cost currency
 	Tokens        []TokenJSON `json:"tokens"`          // Array of individual tokens
 }
 
@@ -48,6 +58,10 @@ func (r *JSONRenderer) Render(result *Result) error {
 			Position: token.Position,
 			Length:   token.Length,
 			ByteSize: len(token.Text),
+			Cost:     result.TokenCost,
+		}
+		if issue, ok := result.Issues[i]; ok {
+			tokens[i].Issue = &TokenIssueJSON{Kind: issue.Kind, Label: issue.Label}
 		}
 	}
 
@@ -76,6 +90,7 @@ func (r *JSONRenderer) Render(result *Result) error {
 		TotalLines:    lineCount,
 		TokensPerLine: tokensPerLine,
 		Cost:          result.Cost,
+		Currency:      result.Currency.Code,
 		Tokens:        tokens,
 	}
 
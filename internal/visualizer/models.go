@@ -1,7 +1,10 @@
 // Package visualizer provides token visualization capabilities for cc-token.
 package visualizer
 
-import "github.com/iota-uz/cc-token/internal/api"
+import (
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
 
 // Result holds tokenization data for visualization
 type Result struct {
@@ -10,5 +13,15 @@ type Result struct {
 	TotalTokens int // Total number of content tokens (from visualization)
 	APITokens   int // API token count (includes message overhead)
 	Model       string
-	Cost        float64 // Estimated cost in USD
+	Cost        float64 // Estimated cost in Currency
+	TokenCost   float64 // Input price of a single token in Currency
+	Currency    pricing.Currency
+	// Issues maps token indexes to the first safety issue they overlap (only with --highlight-issues)
+	Issues map[int]TokenIssue
+}
+
+// TokenIssue marks a token that overlaps a detected safety issue
+type TokenIssue struct {
+	Kind  string // "invisible", "emoji" or "confusable"
+	Label string // Character type or name shown in the legend and tooltip
 }
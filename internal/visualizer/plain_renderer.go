@@ -21,7 +21,7 @@ func (r *PlainRenderer) Render(result *Result) error {
 	fmt.Fprintf(os.Stdout, "Content Tokens: %d | API Tokens: %d (includes message overhead)\n",
 		result.TotalTokens, result.APITokens)
 	fmt.Fprintf(os.Stdout, "Characters: %d    Model: %s\n", len(result.Content), result.Model)
-	fmt.Fprintf(os.Stdout, "Estimated Cost: $%.6f\n", result.Cost)
+	fmt.Fprintf(os.Stdout, "Estimated Cost: %s%.6f\n", result.Currency.Symbol, result.Cost)
 	fmt.Fprintln(os.Stdout, strings.Repeat("=", 80))
 	fmt.Fprintln(os.Stdout)
 
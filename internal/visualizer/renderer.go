@@ -32,8 +32,8 @@ const (
 
 // SelectRenderer chooses the appropriate renderer based on configuration
 func SelectRenderer(cfg *config.Config, mode string) (Renderer, error) {
-	// Priority: JSON flag > Plain flag > specified mode
-	if cfg.JSONOutput {
+	// Priority: --format json > --plain > specified mode
+	if cfg.Format == config.FormatJSON {
 		return &JSONRenderer{}, nil
 	}
 
@@ -46,7 +46,7 @@ func SelectRenderer(cfg *config.Config, mode string) (Renderer, error) {
 	case "basic":
 		return &BasicRenderer{}, nil
 	case "interactive":
-		return &WebRenderer{NoBrowser: cfg.NoBrowser}, nil
+		return &WebRenderer{NoBrowser: cfg.NoBrowser, Host: cfg.Host, Port: cfg.Port}, nil
 	case "html":
 		// For HTML mode, OutputFile must be provided (validated in cmd layer)
 		return &HTMLRenderer{
@@ -64,7 +64,7 @@ func SelectRenderer(cfg *config.Config, mode string) (Renderer, error) {
 
 // isNonInteractiveMode checks if the current configuration uses a non-interactive output mode
 func isNonInteractiveMode(cfg *config.Config, mode string) bool {
-	return cfg.JSONOutput || cfg.Plain || mode == "json" || mode == "plain" || mode == "html"
+	return cfg.Format == config.FormatJSON || cfg.Plain || mode == "json" || mode == "plain" || mode == "html"
 }
 
 // ShouldSkipConfirmation determines if cost confirmation should be skipped
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/iota-uz/cc-token/internal/analyzer"
 	"github.com/iota-uz/cc-token/internal/api"
 	"github.com/iota-uz/cc-token/internal/config"
 	"github.com/iota-uz/cc-token/internal/pricing"
@@ -14,12 +15,12 @@ import (
 
 // Visualizer handles token visualization workflows
 type Visualizer struct {
-	apiClient      *api.Client
+	apiClient      api.TokenCounter
 	pricingService *pricing.Pricer
 }
 
 // New creates a new Visualizer instance
-func New(apiClient *api.Client, pricingService *pricing.Pricer) *Visualizer {
+func New(apiClient api.TokenCounter, pricingService *pricing.Pricer) *Visualizer {
 	return &Visualizer{
 		apiClient:      apiClient,
 		pricingService: pricingService,
@@ -90,6 +91,11 @@ func (v *Visualizer) Run(path string, cfg *config.Config) error {
 		APITokens:   estimatedTokens, // API count (includes message overhead)
 		Model:       cfg.Model,
 		Cost:        cost,
+		TokenCost:   v.pricingService.CostPerToken(cfg.Model),
+		Currency:    v.pricingService.Currency(),
+	}
+	if cfg.HighlightIssues {
+		result.Issues = mapIssuesToTokens(tokens, analyzer.FindIssueSpans(content))
 	}
 
 	// Select and use appropriate renderer
@@ -109,7 +115,7 @@ func (v *Visualizer) confirmVisualization(estimatedTokens int, model string) boo
 	fmt.Fprintf(os.Stderr, "\n💡 Token Visualization\n")
 	fmt.Fprintf(os.Stderr, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 	fmt.Fprintf(os.Stderr, "API tokens:         %d (exact)\n", estimatedTokens)
-	fmt.Fprintf(os.Stderr, "Estimated cost:     $%.6f\n", cost)
+	fmt.Fprintf(os.Stderr, "Estimated cost:     %s%.6f\n", v.pricingService.Currency().Symbol, cost)
 	fmt.Fprintf(os.Stderr, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 	fmt.Fprintf(os.Stderr, "Visualization uses client-side tokenization (no additional API cost).\n")
 	fmt.Fprintf(os.Stderr, "Note: Token boundaries are approximate (94-98%% accurate for typical files).\n\n")
@@ -125,3 +131,36 @@ func (v *Visualizer) confirmVisualization(estimatedTokens int, model string) boo
 	// Default to Yes if user just presses Enter
 	return response == "" || response == "y" || response == "yes"
 }
+
+// mapIssuesToTokens returns the first issue span each token overlaps, keyed by token index.
+// Both tokens and spans are ordered by position, so a single pass over each is enough.
+func mapIssuesToTokens(tokens []api.Token, spans []analyzer.IssueSpan) map[int]TokenIssue {
+	issues := make(map[int]TokenIssue)
+	next := 0
+	for i, token := range tokens {
+		start, end := token.Position, token.Position+token.Length
+		for next < len(spans) && spans[next].End <= start {
+			next++
+		}
+		if next < len(spans) && spans[next].Start < end {
+			issues[i] = TokenIssue{Kind: spans[next].Kind, Label: spans[next].Label}
+		}
+	}
+	return issues
+}
+
+// issueSummary counts highlighted tokens by issue kind, in a stable order for legends
+func issueSummary(issues map[int]TokenIssue) []string {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+
+	var summary []string
+	for _, kind := range []string{"invisible", "emoji", "confusable"} {
+		if counts[kind] > 0 {
+			summary = append(summary, fmt.Sprintf("%s: %d", kind, counts[kind]))
+		}
+	}
+	return summary
+}
@@ -0,0 +1,135 @@
+package visualizer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iota-uz/cc-token/internal/analyzer"
+	"github.com/iota-uz/cc-token/internal/api"
+	"github.com/iota-uz/cc-token/internal/config"
+	"github.com/iota-uz/cc-token/internal/pricing"
+)
+
+func TestMapIssuesToTokens(t *testing.T) {
+	tokens := []api.Token{
+		{Text: "ab", Position: 0, Length: 2},
+		{Text: "cd", Position: 2, Length: 2},
+		{Text: "ef", Position: 4, Length: 2},
+		{Text: "gh", Position: 6, Length: 2},
+	}
+	spans := []analyzer.IssueSpan{
+		{Start: 3, End: 5, Kind: "invisible", Label: "zwsp"},
+		{Start: 7, End: 8, Kind: "emoji", Label: "emoji"},
+	}
+
+	issues := mapIssuesToTokens(tokens, spans)
+	want := map[int]string{1: "invisible", 2: "invisible", 3: "emoji"}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v, want tokens 1-3 marked", issues)
+	}
+	for i, kind := range want {
+		if issues[i].Kind != kind {
+			t.Errorf("token %d issue = %q, want %q", i, issues[i].Kind, kind)
+		}
+	}
+}
+
+func TestRunHighlightsInvisibleCharacters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	if err := os.WriteFile(path, []byte("Approve\u200b the refund"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Model: "claude-sonnet-4-5", MaxSize: 1 << 20, Visualize: "json", HighlightIssues: true}
+
+	stdout := captureStdout(t, func() {
+		if err := New(api.NewOfflineCounter(), pricing.New("")).Run(path, cfg); err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	})
+	var result ResultJSON
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+
+	zwsp := strings.Index(result.Content, "\u200b")
+	marked := 0
+	for _, token := range result.Tokens {
+		overlaps := token.Position < zwsp+len("\u200b") && token.Position+token.Length > zwsp
+		if overlaps != (token.Issue != nil) {
+			t.Errorf("token %q at %d: issue %+v, want marked = %v", token.Text, token.Position, token.Issue, overlaps)
+		}
+		if token.Issue != nil {
+			marked++
+			if token.Issue.Kind != "invisible" {
+				t.Errorf("token %q issue kind = %q, want invisible", token.Text, token.Issue.Kind)
+			}
+		}
+	}
+	if marked == 0 {
+		t.Error("no token marked for the zero-width space")
+	}
+}
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	os.Stdout = stdout
+	return <-done
+}
+
+func TestHTMLRendererShowsOffsetsOnHover(t *testing.T) {
+	content := "Hello world\nBye"
+	result := &Result{
+		Content: content,
+		Tokens: []api.Token{
+			{Text: "Hello", Position: 0, Length: 5},
+			{Text: " world", Position: 5, Length: 6},
+			{Text: "\n", Position: 11, Length: 1},
+			{Text: "Bye", Position: 12, Length: 3},
+		},
+		TotalTokens: 4,
+		Model:       "claude-sonnet-4-5",
+	}
+	result.Currency, _ = pricing.LookupCurrency(pricing.DefaultCurrency)
+	path := filepath.Join(t.TempDir(), "tokens.html")
+	if err := (&HTMLRenderer{OutputFile: path}).Render(result); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	// "Bye" is token #3, at byte 12 on line 2
+	for _, want := range []string{
+		`data-index="3"`,
+		`data-position="12"`,
+		`data-length="3"`,
+		`data-line="2"`,
+		`data-column="1"`,
+		`title="Token #3 | Byte offset: 12 | Line 2, col 1 | Len: 3 bytes"`,
+		`title="Token #1 | Byte offset: 5 | Line 1, col 6 | Len: 6 bytes"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML is missing %s", want)
+		}
+	}
+}
@@ -8,7 +8,9 @@ import (
 
 // WebRenderer launches a web server for interactive visualization
 type WebRenderer struct {
-	NoBrowser bool // Whether to skip auto-opening browser
+	NoBrowser bool   // Whether to skip auto-opening browser
+	Host      string // Address to listen on
+	Port      int    // Port to bind (0 = first free port from 8080)
 }
 
 // Render starts a web server and serves the visualization
@@ -18,7 +20,7 @@ func (r *WebRenderer) Render(result *Result) error {
 	}
 
 	// Create server instance
-	srv, err := server.New()
+	srv, err := server.New(r.Host, r.Port)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
@@ -31,6 +33,7 @@ func (r *WebRenderer) Render(result *Result) error {
 		APITokens:   result.APITokens,
 		Model:       result.Model,
 		Cost:        result.Cost,
+		CostSymbol:  result.Currency.Symbol,
 	}
 
 	// Start server (blocks until Ctrl+C)